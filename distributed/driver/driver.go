@@ -25,6 +25,7 @@ type Option struct {
 	TaskMemoryMB  int
 	FlowBid       float64
 	Module        string
+	Speculation   bool
 }
 
 type FlowDriver struct {
@@ -44,7 +45,8 @@ func NewFlowDriver(option *Option) *FlowDriver {
 }
 
 // driver runs on local, controlling all tasks
-func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow) {
+func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow) (*flow.RunResult, error) {
+	startTime := time.Now()
 
 	// task fusion to minimize disk IO
 	fcd.stepGroups, fcd.taskGroups = plan.GroupTasks(fc)
@@ -59,6 +61,7 @@ func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow)
 			TaskMemoryMB: fcd.Option.TaskMemoryMB,
 			Module:       fcd.Option.Module,
 			FlowHashcode: fc.HashCode,
+			Speculation:  fcd.Option.Speculation,
 		},
 	)
 
@@ -67,6 +70,7 @@ func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow)
 	defer fcd.cleanup(sched, fc)
 
 	ctx, cancel := context.WithCancel(parentCtx)
+	sched.SetTaskGroups(fcd.taskGroups, cancel)
 
 	on_interrupt.OnInterrupt(func() {
 		println("interrupted ...")
@@ -76,12 +80,15 @@ func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow)
 	}, nil)
 
 	// schedule to run the steps
+	// stop all other task groups if any task group failed
 	var wg, reportWg sync.WaitGroup
 	for _, taskGroup := range fcd.taskGroups {
 		wg.Add(1)
 		go func(taskGroup *plan.TaskGroup) {
-			sched.ExecuteTaskGroup(ctx, fc, fcd.GetTaskGroupStatus(taskGroup), &wg, taskGroup,
-				fcd.Option.FlowBid/float64(len(fcd.taskGroups)), fcd.Option.RequiredFiles)
+			if err := sched.ExecuteTaskGroup(ctx, fc, fcd.GetTaskGroupStatus(taskGroup), &wg, taskGroup,
+				fcd.Option.FlowBid/float64(len(fcd.taskGroups)), fcd.Option.RequiredFiles); err != nil {
+				cancel()
+			}
 		}(taskGroup)
 	}
 	go sched.Market.FetcherLoop()
@@ -97,6 +104,9 @@ func (fcd *FlowDriver) RunFlowContext(parentCtx context.Context, fc *flow.Flow)
 	stopChan <- true
 	reportWg.Wait()
 
+	fcd.collectTaskStatus()
+
+	return flow.CollectRunResult(fc, startTime, time.Now()), flow.CollectRunError(fc)
 }
 
 func (fcd *FlowDriver) cleanup(sched *scheduler.Scheduler, fc *flow.Flow) {
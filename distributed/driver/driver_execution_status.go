@@ -2,6 +2,7 @@
 package driver
 
 import (
+	"errors"
 	"os"
 	"os/user"
 	"time"
@@ -143,3 +144,29 @@ func (fcd *FlowDriver) logExecutionPlan(fc *flow.Flow) {
 	}
 
 }
+
+// collectTaskStatus copies the timings, stats, and errors of the last
+// execution of each task group back to its tasks.
+// The error of a task group is set on its last task.
+func (fcd *FlowDriver) collectTaskStatus() {
+	for _, taskGroup := range fcd.taskGroups {
+		status := fcd.GetTaskGroupStatus(taskGroup)
+		if status == nil || len(status.Executions) == 0 {
+			continue
+		}
+		execution := status.Executions[len(status.Executions)-1]
+		for _, task := range taskGroup.Tasks {
+			task.StartTime = time.Unix(0, execution.StartTime)
+			task.StopTime = time.Unix(0, execution.StopTime)
+			for _, stat := range execution.GetExecutionStat().GetStats() {
+				if int(stat.StepId) == task.Step.Id && int(stat.TaskId) == task.Id {
+					task.Stat = stat
+				}
+			}
+		}
+		if execution.Error != nil {
+			lastTask := taskGroup.Tasks[len(taskGroup.Tasks)-1]
+			lastTask.Error = errors.New(string(execution.Error))
+		}
+	}
+}
@@ -111,7 +111,7 @@ func sendExecutionRequest(ctx context.Context,
 			}
 			if err != nil {
 				log.Printf("sendExecutionRequest stream receive: %v", err)
-				break
+				return fmt.Errorf("%s %v>%v", server, request.InstructionSet.Name, err)
 			}
 			if response.GetError() != nil {
 				log.Printf("%s %v>%s", server, request.InstructionSet.Name, string(response.GetError()))
@@ -131,7 +131,7 @@ func sendExecutionRequest(ctx context.Context,
 			}
 		}
 
-		return err
+		return nil
 
 	})
 }
@@ -80,6 +80,21 @@ func (m *Market) AddDemand(r Requirement, bid float64, retChan chan Supply) {
 	m.hasDemands.Signal()
 }
 
+// RemoveDemand withdraws a demand not fulfilled yet.
+// It returns false if a supply has been sent to retChan.
+func (m *Market) RemoveDemand(retChan chan Supply) bool {
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	for i, demand := range m.Demands {
+		if demand.ReturnChan == retChan {
+			m.Demands = append(m.Demands[:i], m.Demands[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Market) FetcherLoop() {
 	for {
 		// println("FetcherLoop Lock:", len(m.Demands))
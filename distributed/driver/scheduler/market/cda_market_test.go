@@ -35,3 +35,31 @@ func TestMarket(t *testing.T) {
 	t.Logf("market works well")
 
 }
+
+func TestMarketRemoveDemand(t *testing.T) {
+
+	m := NewMarket().SetScoreFunction(func(r Requirement, bid float64, obj Object) float64 {
+		return 1
+	}).SetFetchFunction(func([]Demand) {
+	})
+
+	removed, fulfilled := make(chan Supply, 1), make(chan Supply, 1)
+	m.AddDemand(Requirement(1.0), 1, removed)
+	m.AddDemand(Requirement(2.0), 1, fulfilled)
+
+	if !m.RemoveDemand(removed) {
+		t.Errorf("failed to remove a pending demand")
+	}
+
+	m.AddSupply(Supply{Object(1.0)})
+	if s := <-fulfilled; s.Object.(float64) != 1.0 {
+		t.Errorf("unexpected supply %v", s.Object)
+	}
+	if m.RemoveDemand(fulfilled) {
+		t.Errorf("removed a fulfilled demand")
+	}
+	if len(m.Supplies) != 0 {
+		t.Errorf("supply should go to the remaining demand")
+	}
+
+}
@@ -2,12 +2,14 @@
 package scheduler
 
 import (
+	"context"
 	"os"
 	"os/user"
 	"sync"
 	"time"
 
 	"github.com/chrislusf/gleamold/distributed/driver/scheduler/market"
+	"github.com/chrislusf/gleamold/distributed/plan"
 	"github.com/chrislusf/gleamold/pb"
 )
 
@@ -19,6 +21,9 @@ type Scheduler struct {
 	Market       *market.Market
 	Option       *SchedulerOption
 	shardLocator *DatasetShardLocator
+	executions   map[*plan.TaskGroup]*taskGroupExecution
+	lostAgents   map[string]bool
+	cancelFlow   context.CancelFunc
 }
 
 type RemoteExecutorStatus struct {
@@ -40,6 +45,7 @@ type SchedulerOption struct {
 	Rack         string
 	TaskMemoryMB int
 	Module       string
+	Speculation  bool // duplicate straggling task groups on other agents
 }
 
 func NewScheduler(leader string, option *SchedulerOption) *Scheduler {
@@ -53,6 +59,8 @@ func NewScheduler(leader string, option *SchedulerOption) *Scheduler {
 		EventChan:    make(chan interface{}),
 		Market:       market.NewMarket(),
 		shardLocator: NewDatasetShardLocator(),
+		executions:   make(map[*plan.TaskGroup]*taskGroupExecution),
+		lostAgents:   make(map[string]bool),
 		Option:       option,
 	}
 	s.Market.SetScoreFunction(s.Score).SetFetchFunction(s.Fetch)
@@ -3,9 +3,11 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"github.com/chrislusf/gleamold/distributed/netchan"
 	"github.com/chrislusf/gleamold/distributed/plan"
@@ -87,9 +89,20 @@ func (s *Scheduler) localExecute(ctx context.Context, flowContext *flow.Flow, ta
 func (s *Scheduler) localExecuteSource(ctx context.Context, flowContext *flow.Flow, task *flow.Task, wg *sync.WaitGroup) error {
 	s.shardLocator.waitForOutputDatasetShardLocations(task)
 
+	sourceDone := make(chan struct{})
+	defer close(sourceDone)
+
 	for _, shard := range task.OutputShards {
 		location, _ := s.getShardLocation(shard)
 		shard.IncomingChan = util.NewPiper()
+		go func(reader *io.PipeReader) {
+			// unblock the source if the attempt is stopped
+			select {
+			case <-ctx.Done():
+				reader.CloseWithError(ctx.Err())
+			case <-sourceDone:
+			}
+		}(shard.IncomingChan.Reader)
 		wg.Add(1)
 		go func(shard *flow.DatasetShard) {
 			// println(task.Step.Name, "writing to", shard.Name(), "at", location.Location.URL())
@@ -107,13 +120,25 @@ func (s *Scheduler) localExecuteSource(ctx context.Context, flowContext *flow.Fl
 func (s *Scheduler) localExecuteOutput(ctx context.Context, flowContext *flow.Flow, task *flow.Task, wg *sync.WaitGroup) error {
 	s.shardLocator.waitForInputDatasetShardLocations(task)
 
+	outputDone := make(chan struct{})
+	defer close(outputDone)
+
 	for i, shard := range task.InputShards {
 		location, _ := s.getShardLocation(shard)
 		inChan := task.InputChans[i]
+		go func(writer *io.PipeWriter) {
+			// stop the output if the attempt is stopped
+			select {
+			case <-ctx.Done():
+				writer.CloseWithError(ctx.Err())
+			case <-outputDone:
+			}
+		}(inChan.Writer)
 		wg.Add(1)
 		go func(shard *flow.DatasetShard) {
 			// println(task.Step.Name, "reading from", shard.Name(), "at", location.Location.URL(), "to", inChan, "onDisk", shard.Dataset.GetIsOnDiskIO())
-			if err := netchan.DialReadChannel(ctx, wg, "driver_output", location.Location.URL(), shard.Name(), shard.Dataset.GetIsOnDiskIO(), inChan.Writer); err != nil {
+			writer := &countingWriter{inChan.Writer, &inChan.Counter}
+			if err := netchan.DialReadChannel(ctx, wg, "driver_output", location.Location.URL(), shard.Name(), shard.Dataset.GetIsOnDiskIO(), writer); err != nil {
 				println("starting:", task.Step.Name, "input location:", location.Location.URL(), shard.Name(), "error:", err.Error())
 			}
 		}(shard)
@@ -123,3 +148,15 @@ func (s *Scheduler) localExecuteOutput(ctx context.Context, flowContext *flow.Fl
 	}
 	return nil
 }
+
+// countingWriter counts the bytes received by the driver.
+type countingWriter struct {
+	io.WriteCloser
+	counter *int64
+}
+
+func (w *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = w.WriteCloser.Write(p)
+	atomic.AddInt64(w.counter, int64(n))
+	return
+}
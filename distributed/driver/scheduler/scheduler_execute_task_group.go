@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"sync"
@@ -17,29 +18,178 @@ import (
 
 // ExecuteTaskGroup wait for inputs and execute the task group remotely.
 // If cancelled, the output will be cleaned up.
+// The returned error is the failure of the last execution attempt.
 func (s *Scheduler) ExecuteTaskGroup(ctx context.Context,
 	fc *flow.Flow,
 	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
 	wg *sync.WaitGroup,
 	taskGroup *plan.TaskGroup,
-	bid float64, relatedFiles []resource.FileResource) {
+	bid float64, relatedFiles []resource.FileResource) error {
 
 	defer wg.Done()
 
+	exe := s.getExecution(taskGroup)
+	var execute func() error
+	if taskGroup.Tasks[0].Step.IsOnDriverSide {
+		execute = func() error {
+			return s.executeOnDriver(ctx, fc, taskGroupStatus, wg, taskGroup)
+		}
+	} else {
+		execute = func() error {
+			return util.ExecuteWithCleanup(
+				ctx,
+				func() error {
+					return s.executeWithRecovery(ctx, fc, taskGroupStatus, wg, taskGroup, bid, relatedFiles)
+				},
+				func() {
+					s.DeleteOutout(taskGroup)
+				},
+			)
+		}
+	}
+	exe.setRerun(func() {
+		defer wg.Done()
+		if err := execute(); err != nil {
+			log.Printf("Failed to re-execute %s: %v", taskGroup, err)
+			s.Lock()
+			cancelFlow := s.cancelFlow
+			s.Unlock()
+			if cancelFlow != nil {
+				cancelFlow()
+			}
+		}
+	})
+
+	return execute()
+}
+
+// executeOnDriver runs the task group on the driver,
+// and runs it again if its stage is re-executed.
+func (s *Scheduler) executeOnDriver(ctx context.Context,
+	fc *flow.Flow,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
+	wg *sync.WaitGroup,
+	taskGroup *plan.TaskGroup) (err error) {
+
+	exe := s.getExecution(taskGroup)
+	defer exe.stop()
+
 	tasks := taskGroup.Tasks
 	lastTask := tasks[len(tasks)-1]
-	if tasks[0].Step.IsOnDriverSide {
+	for {
+		if lastTask.Step.OutputDataset == nil {
+			if err = s.waitForSpeculativeParents(ctx, taskGroup); err != nil {
+				taskGroup.MarkStop(err)
+				return err
+			}
+		}
+		attemptCtx, generation, _ := s.startAttempt(ctx, exe, nil)
 		// these should be only one task on the driver side
-		if err := taskGroupStatus.Track(func(exe *pb.FlowExecutionStatus_TaskGroup_Execution) error {
-			return s.localExecute(ctx, fc, lastTask, wg)
-		}); err != nil {
-			log.Fatalf("Failed to execute on driver side: %v", err)
+		err = taskGroupStatus.Track(func(exe *pb.FlowExecutionStatus_TaskGroup_Execution) error {
+			return s.localExecute(attemptCtx, fc, lastTask, wg)
+		})
+		if err != nil {
+			log.Printf("Failed to execute on driver side: %v", err)
+			err = fmt.Errorf("Failed to execute on driver side: %v", err)
 		}
-	} else {
+		if exe.finishAttempt(generation, err) && ctx.Err() == nil {
+			log.Printf("Re-executing %s with its stage.", taskGroup)
+			if lastTask.Step.OutputDataset == nil {
+				resetOutputInputs(lastTask)
+			}
+			continue
+		}
+		taskGroup.MarkStop(err)
+		return err
+	}
+}
+
+// executeWithRecovery executes the task group until it succeeds.
+// If an agent is lost, the stage of the task group, and the stages with
+// outputs on the lost agent, are executed again.
+// Restartable task groups are also retried after other failures.
+func (s *Scheduler) executeWithRecovery(ctx context.Context,
+	fc *flow.Flow,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
+	wg *sync.WaitGroup,
+	taskGroup *plan.TaskGroup,
+	bid float64, relatedFiles []resource.FileResource) (err error) {
+
+	exe := s.getExecution(taskGroup)
+	defer exe.stop()
+	// only mark the final result, the task groups waiting for it
+	// should not stop at a failure which is recovered
+	defer func() {
+		taskGroup.MarkStop(err)
+	}()
+
+	retryDelays := []time.Duration{time.Minute, 3 * time.Minute}
+	var recoveries int
+	for {
+		var generation int
+		generation, err = s.executeOnce(ctx, fc, taskGroupStatus, wg, taskGroup, bid, relatedFiles)
+		if exe.finishAttempt(generation, err) && ctx.Err() == nil {
+			log.Printf("Re-executing %s with its stage.", taskGroup)
+			continue
+		}
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		if lostAgents := s.findLostAgents(exe); len(lostAgents) > 0 && recoveries < MaxRecoveries {
+			recoveries++
+			log.Printf("Re-executing %s after losing agents %v: %v", taskGroup, lostAgents, err)
+			if restartErr := s.restartStage(wg, exe.stage, generation); restartErr != nil {
+				log.Printf("Failed to re-execute %s: %v", taskGroup, restartErr)
+				return err
+			}
+			if recoverErr := s.reexecuteLostInputs(wg, exe); recoverErr != nil {
+				log.Printf("Failed to re-compute inputs of %s: %v", taskGroup, recoverErr)
+				return err
+			}
+			continue
+		}
+
+		if isRestartableTasks(taskGroup.Tasks) && len(retryDelays) > 0 {
+			log.Printf("Retrying %s after failure: %v", taskGroup, err)
+			time.Sleep(retryDelays[0])
+			retryDelays = retryDelays[1:]
+			if restartErr := s.restartStage(wg, exe.stage, generation); restartErr != nil {
+				log.Printf("Failed to retry %s: %v", taskGroup, restartErr)
+				return err
+			}
+			continue
+		}
+
+		return err
+	}
+}
+
+// executeOnce executes the task group on a newly allocated executor,
+// and returns the stage generation of the attempt.
+func (s *Scheduler) executeOnce(ctx context.Context,
+	fc *flow.Flow,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
+	wg *sync.WaitGroup,
+	taskGroup *plan.TaskGroup,
+	bid float64, relatedFiles []resource.FileResource) (int, error) {
+
+	exe := s.getExecution(taskGroup)
+	tasks := taskGroup.Tasks
+
+	taskGroup.WaitAt = time.Now()
+
+	var attemptCtx context.Context
+	var generation int
+	var allocation *pb.Allocation
+	for attemptCtx == nil {
 		if !needsInputFromDriver(tasks[0]) {
 			// wait until inputs are registed
 			s.shardLocator.waitForInputDatasetShardLocations(tasks[0])
 		}
+		if err := s.waitForSpeculativeParents(ctx, taskGroup); err != nil {
+			return exe.getGeneration(), err
+		}
 		if isInputOnDisk(tasks[0]) && !isRestartableTasks(tasks) {
 			// for non-restartable taskGroup, wait until on disk inputs are completed
 			for _, stepGroup := range taskGroup.ParentStepGroup.Parents {
@@ -54,91 +204,74 @@ func (s *Scheduler) ExecuteTaskGroup(ctx context.Context,
 
 		// get assigned executor location
 		supply := <-pickedServerChan
-		allocation := supply.Object.(*pb.Allocation)
-		defer s.Market.ReturnSupply(supply)
-
-		if needsInputFromDriver(tasks[0]) {
-			// tell the driver to write to me
-			for _, shard := range tasks[0].InputShards {
-				// println("registering", shard.Name(), "at", allocation.Location.URL())
-				s.setShardLocation(shard, pb.DataLocation{
-					Name:     shard.Name(),
-					Location: allocation.Location,
-					OnDisk:   shard.Dataset.GetIsOnDiskIO(),
-				})
-			}
-		}
+		allocation = supply.Object.(*pb.Allocation)
 
-		for _, shard := range lastTask.OutputShards {
-			// println("registering", shard.Name(), "at", allocation.Location.URL(), "onDisk", shard.Dataset.GetIsOnDiskIO())
-			s.setShardLocation(shard, pb.DataLocation{
-				Name:     shard.Name(),
-				Location: allocation.Location,
-				OnDisk:   shard.Dataset.GetIsOnDiskIO(),
-			})
+		var ok bool
+		if attemptCtx, generation, ok = s.startAttempt(ctx, exe, allocation); !ok {
+			// the inputs are lost again, wait for them
+			s.Market.ReturnSupply(supply)
+			continue
 		}
+		defer func() {
+			// do not hand out the executors on a lost agent again
+			if !s.isAgentLost(allocation.Location.URL()) {
+				s.Market.ReturnSupply(supply)
+			}
+		}()
+	}
 
-		// send driver code only when using go mapper reducer
-		var hasGoCode bool
-		for _, t := range tasks {
-			hasGoCode = hasGoCode || t.Step.IsGoCode
-		}
-		if hasGoCode {
-			relatedFiles = append(relatedFiles, resource.FileResource{os.Args[0], "."})
-		}
+	taskGroup.StartAt = time.Now()
 
-		if len(relatedFiles) > 0 {
-			err := withClient(allocation.Location.URL(), func(client pb.GleamoldAgentClient) error {
-				for _, relatedFile := range relatedFiles {
-					err := sendRelatedFile(ctx, client, fc.HashCode, relatedFile)
-					if err != nil {
-						taskGroup.MarkStop(err)
-						return err
-					}
-				}
-				return nil
-			})
-			if err != nil {
-				log.Fatalf("Failed to send related files: %v", err)
-			}
-		}
+	if s.isSpeculative(taskGroup) {
+		return generation, s.executeSpeculatively(attemptCtx, fc, taskGroupStatus, wg, exe, generation, allocation, bid, relatedFiles)
+	}
+
+	err := taskGroupStatus.Track(func(exeStatus *pb.FlowExecutionStatus_TaskGroup_Execution) error {
+		return s.executeOnAllocation(attemptCtx, fc, taskGroupStatus, exeStatus, taskGroup, allocation, wg, relatedFiles)
+	})
+	return generation, err
+}
 
-		fn := func() error {
-			err := taskGroupStatus.Track(func(exeStatus *pb.FlowExecutionStatus_TaskGroup_Execution) error {
-				return s.remoteExecuteOnLocation(ctx, fc, taskGroupStatus, exeStatus, taskGroup, allocation, wg)
-			})
-			if err != nil {
-				log.Printf("Failed to remoteExecuteOnLocation %v: %v", allocation, err)
+// executeOnAllocation sends the related files to the allocated agent,
+// and executes the task group there.
+func (s *Scheduler) executeOnAllocation(ctx context.Context,
+	fc *flow.Flow,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
+	exeStatus *pb.FlowExecutionStatus_TaskGroup_Execution,
+	taskGroup *plan.TaskGroup,
+	allocation *pb.Allocation, wg *sync.WaitGroup,
+	relatedFiles []resource.FileResource) error {
+
+	tasks := taskGroup.Tasks
+
+	// send driver code only when using go mapper reducer
+	var hasGoCode bool
+	for _, t := range tasks {
+		hasGoCode = hasGoCode || t.Step.IsGoCode
+	}
+	if hasGoCode {
+		relatedFiles = append(relatedFiles, resource.FileResource{os.Args[0], "."})
+	}
+
+	if len(relatedFiles) > 0 {
+		err := withClient(allocation.Location.URL(), func(client pb.GleamoldAgentClient) error {
+			for _, relatedFile := range relatedFiles {
+				err := sendRelatedFile(ctx, client, fc.HashCode, relatedFile)
+				if err != nil {
+					return err
+				}
 			}
-			taskGroup.MarkStop(err)
-			return err
+			return nil
+		})
+		if err != nil {
+			log.Printf("Failed to send related files: %v", err)
+			return fmt.Errorf("Failed to send related files: %v", err)
 		}
+	}
 
-		util.ExecuteWithCleanup(
-			ctx,
-			func() error {
-				if isRestartableTasks(tasks) {
-					return util.TimeDelayedRetry(fn, time.Minute, 3*time.Minute)
-				} else {
-					return fn()
-				}
-			},
-			func() {
-				var w sync.WaitGroup
-				for _, shard := range lastTask.OutputShards {
-					w.Add(1)
-					// println("deleting", shard.Name(), "from", allocation.Location.URL())
-					go func(shard *flow.DatasetShard) {
-						defer w.Done()
-						if err := sendDeleteRequest(allocation.Location.URL(), &pb.DeleteDatasetShardRequest{
-							Name: shard.Name(),
-						}); err != nil {
-							println("Purging dataset error:", err.Error())
-						}
-					}(shard)
-				}
-				w.Wait()
-			},
-		)
+	err := s.remoteExecuteOnLocation(ctx, fc, taskGroupStatus, exeStatus, taskGroup, allocation, wg)
+	if err != nil {
+		log.Printf("Failed to remoteExecuteOnLocation %v: %v", allocation, err)
 	}
+	return err
 }
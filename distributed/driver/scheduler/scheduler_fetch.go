@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/chrislusf/gleamold/distributed/driver/scheduler/market"
-	"github.com/chrislusf/gleamold/distributed/plan"
 	"github.com/chrislusf/gleamold/pb"
 )
 
@@ -19,7 +18,7 @@ func (s *Scheduler) Fetch(demands []market.Demand) {
 	request.FlowHashCode = s.Option.FlowHashcode
 	request.DataCenter = s.Option.DataCenter
 	for _, d := range demands {
-		taskGroup := d.Requirement.(*plan.TaskGroup)
+		taskGroup, _ := requirementOf(d.Requirement)
 		requiredResource := taskGroup.RequiredResources()
 		request.ComputeResources = append(request.ComputeResources, requiredResource)
 	}
@@ -0,0 +1,415 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chrislusf/gleamold/distributed/plan"
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+	"google.golang.org/grpc"
+)
+
+// MaxRecoveries is the max number of times a task group is re-executed
+// because of lost agents.
+var MaxRecoveries = 3
+
+// AgentCheckTimeout is how long to wait for a connection before an agent is considered lost.
+var AgentCheckTimeout = 5 * time.Second
+
+// stage is the task groups connected by in memory datasets.
+// In memory data can only be read once, so if any of these task groups
+// is executed again, all of them are executed again together.
+type stage struct {
+	sync.Mutex
+	generation int // increased every time the stage is re-executed
+	executions []*taskGroupExecution
+}
+
+// taskGroupExecution tracks the current attempt of a task group.
+// The fields are guarded by the stage lock.
+type taskGroupExecution struct {
+	taskGroup         *plan.TaskGroup
+	stage             *stage
+	generation        int // the stage generation of the current attempt
+	isRunning         bool
+	cancel            context.CancelFunc // stops the current attempt
+	allocation        *pb.Allocation
+	inputs            []string // the agents providing the inputs to the current attempt
+	parentGenerations map[*plan.TaskGroup]int
+	rerun             func()
+	progress          progress
+}
+
+// SetTaskGroups groups the task groups connected by in memory datasets
+// into stages, which are re-executed together if any agent is lost.
+// cancelFlow is called if a re-executed task group fails.
+func (s *Scheduler) SetTaskGroups(taskGroups []*plan.TaskGroup, cancelFlow context.CancelFunc) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.cancelFlow = cancelFlow
+
+	roots := make(map[*plan.TaskGroup]*plan.TaskGroup)
+	var find func(tg *plan.TaskGroup) *plan.TaskGroup
+	find = func(tg *plan.TaskGroup) *plan.TaskGroup {
+		root, found := roots[tg]
+		if !found || root == tg {
+			return tg
+		}
+		root = find(root)
+		roots[tg] = root
+		return root
+	}
+	for _, tg := range taskGroups {
+		for _, parent := range tg.Parents {
+			if isInMemoryInput(parent, tg) {
+				if a, b := find(tg), find(parent); a != b {
+					roots[a] = b
+				}
+			}
+		}
+	}
+
+	stages := make(map[*plan.TaskGroup]*stage)
+	for _, tg := range taskGroups {
+		root := find(tg)
+		st, found := stages[root]
+		if !found {
+			st = &stage{}
+			stages[root] = st
+		}
+		exe := &taskGroupExecution{taskGroup: tg, stage: st, isRunning: true}
+		st.executions = append(st.executions, exe)
+		s.executions[tg] = exe
+	}
+}
+
+func (s *Scheduler) getExecution(taskGroup *plan.TaskGroup) *taskGroupExecution {
+	s.Lock()
+	defer s.Unlock()
+
+	exe, found := s.executions[taskGroup]
+	if !found {
+		// not planned with SetTaskGroups, so the task group is its own stage
+		exe = &taskGroupExecution{taskGroup: taskGroup, stage: &stage{}, isRunning: true}
+		exe.stage.executions = append(exe.stage.executions, exe)
+		s.executions[taskGroup] = exe
+	}
+	return exe
+}
+
+func (e *taskGroupExecution) setRerun(rerun func()) {
+	e.stage.Lock()
+	defer e.stage.Unlock()
+	e.rerun = rerun
+}
+
+// startAttempt registers the output locations of a new attempt,
+// if all the inputs from other task groups are registered.
+// The allocation is nil for the task groups on the driver.
+func (s *Scheduler) startAttempt(ctx context.Context, exe *taskGroupExecution, allocation *pb.Allocation) (attemptCtx context.Context, generation int, ok bool) {
+	tasks := exe.taskGroup.Tasks
+
+	// read before locking this stage, to lock only one stage at a time
+	parentGenerations := make(map[*plan.TaskGroup]int)
+	for _, parent := range exe.taskGroup.Parents {
+		parentGenerations[parent] = s.getExecution(parent).getGeneration()
+	}
+
+	exe.stage.Lock()
+	defer exe.stage.Unlock()
+
+	var inputs []string
+	if allocation != nil && !needsInputFromDriver(tasks[0]) {
+		for _, shard := range tasks[0].InputShards {
+			location, found := s.getShardLocation(shard)
+			if !found {
+				// the inputs are being re-computed
+				return nil, 0, false
+			}
+			inputs = append(inputs, location.Location.URL())
+		}
+	}
+
+	exe.generation = exe.stage.generation
+	exe.allocation = allocation
+	exe.inputs = inputs
+	exe.parentGenerations = parentGenerations
+	attemptCtx, exe.cancel = context.WithCancel(ctx)
+
+	if allocation == nil {
+		return attemptCtx, exe.generation, true
+	}
+
+	if needsInputFromDriver(tasks[0]) {
+		// tell the driver to write to me
+		for _, shard := range tasks[0].InputShards {
+			// println("registering", shard.Name(), "at", allocation.Location.URL())
+			s.setShardLocation(shard, pb.DataLocation{
+				Name:     shard.Name(),
+				Location: allocation.Location,
+				OnDisk:   shard.Dataset.GetIsOnDiskIO(),
+			})
+		}
+	}
+
+	s.setOutputLocations(exe.taskGroup, allocation)
+
+	return attemptCtx, exe.generation, true
+}
+
+func (s *Scheduler) setOutputLocations(taskGroup *plan.TaskGroup, allocation *pb.Allocation) {
+	tasks := taskGroup.Tasks
+	for _, shard := range tasks[len(tasks)-1].OutputShards {
+		// println("registering", shard.Name(), "at", allocation.Location.URL(), "onDisk", shard.Dataset.GetIsOnDiskIO())
+		s.setShardLocation(shard, pb.DataLocation{
+			Name:     shard.Name(),
+			Location: allocation.Location,
+			OnDisk:   shard.Dataset.GetIsOnDiskIO(),
+		})
+	}
+}
+
+// finishAttempt returns true if the stage is re-executed during the attempt,
+// and the task group should be executed again.
+func (e *taskGroupExecution) finishAttempt(generation int, err error) bool {
+	e.stage.Lock()
+	defer e.stage.Unlock()
+
+	if e.cancel != nil {
+		e.cancel()
+		e.cancel = nil
+	}
+	if e.stage.generation != generation {
+		return true
+	}
+	if err == nil {
+		e.isRunning = false
+	}
+	return false
+}
+
+func (e *taskGroupExecution) stop() {
+	e.stage.Lock()
+	defer e.stage.Unlock()
+	e.isRunning = false
+}
+
+func (e *taskGroupExecution) getGeneration() int {
+	e.stage.Lock()
+	defer e.stage.Unlock()
+	return e.stage.generation
+}
+
+// restartStage stops the current attempts of all the task groups in the stage,
+// and executes them again, unless the stage is already re-executed
+// after the generation.
+func (s *Scheduler) restartStage(wg *sync.WaitGroup, st *stage, generation int) error {
+	st.Lock()
+	defer st.Unlock()
+
+	if st.generation != generation {
+		return nil
+	}
+	for _, exe := range st.executions {
+		if err := checkReexecutable(exe); err != nil {
+			return err
+		}
+	}
+	st.generation++
+
+	for _, exe := range st.executions {
+		if exe.cancel != nil {
+			exe.cancel()
+			exe.cancel = nil
+		}
+	}
+	// readers of the next attempts should not find the partial outputs
+	var deleteWaitGroup sync.WaitGroup
+	for _, exe := range st.executions {
+		tasks := exe.taskGroup.Tasks
+		for _, shard := range tasks[len(tasks)-1].OutputShards {
+			location, found := s.getShardLocation(shard)
+			if !found || s.isAgentLost(location.Location.URL()) {
+				continue
+			}
+			deleteWaitGroup.Add(1)
+			go func(location pb.DataLocation) {
+				defer deleteWaitGroup.Done()
+				if err := sendDeleteRequest(location.Location.URL(), &pb.DeleteDatasetShardRequest{
+					Name: location.Name,
+				}); err != nil {
+					println("Purging dataset error:", err.Error())
+				}
+			}(location)
+		}
+	}
+	deleteWaitGroup.Wait()
+
+	for _, exe := range st.executions {
+		s.forgetOutputLocations(exe.taskGroup)
+		exe.taskGroup.MarkRestart()
+		if !exe.isRunning {
+			log.Printf("Re-executing %s to re-compute its output.", exe.taskGroup)
+			exe.isRunning = true
+			wg.Add(1)
+			go exe.rerun()
+		}
+	}
+	return nil
+}
+
+// checkReexecutable returns an error if the task group can only run once,
+// or its output has been partially consumed by the driver.
+func checkReexecutable(exe *taskGroupExecution) error {
+	for _, task := range exe.taskGroup.Tasks {
+		if !task.Step.IsOnDriverSide {
+			continue
+		}
+		if task.Step.OutputDataset == nil && hasReceivedOutput(task) {
+			return fmt.Errorf("Can not re-execute %s, which has sent output to the driver", exe.taskGroup)
+		}
+		if !task.Step.Meta.IsIdempotent {
+			return fmt.Errorf("Can not re-execute %s, which can only run once", exe.taskGroup)
+		}
+	}
+	if exe.rerun == nil && !exe.isRunning {
+		return fmt.Errorf("Can not re-execute %s before it starts", exe.taskGroup)
+	}
+	return nil
+}
+
+// reexecuteLostInputs re-executes the stages of the parent task groups,
+// if the outputs were stored on a lost agent.
+func (s *Scheduler) reexecuteLostInputs(wg *sync.WaitGroup, exe *taskGroupExecution) error {
+	exe.stage.Lock()
+	parentGenerations := exe.parentGenerations
+	exe.stage.Unlock()
+
+	for _, parent := range exe.taskGroup.Parents {
+		parentExe := s.getExecution(parent)
+		if parentExe.stage == exe.stage || !s.isOutputLost(parent, exe.taskGroup) {
+			continue
+		}
+		log.Printf("Re-executing %s for its lost output.", parent)
+		if err := s.restartStage(wg, parentExe.stage, parentGenerations[parent]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) isOutputLost(parent, taskGroup *plan.TaskGroup) bool {
+	for _, shard := range taskGroup.Tasks[0].InputShards {
+		if !isOutputOf(parent, shard.Name()) {
+			continue
+		}
+		location, found := s.getShardLocation(shard)
+		if !found {
+			// already being re-executed
+			continue
+		}
+		if s.isAgentLost(location.Location.URL()) {
+			return true
+		}
+	}
+	return false
+}
+
+// findLostAgents checks the agents executing the task group,
+// or providing the inputs to the current attempt.
+func (s *Scheduler) findLostAgents(exe *taskGroupExecution) (lostAgents []string) {
+	servers := make(map[string]bool)
+
+	exe.stage.Lock()
+	if exe.allocation != nil {
+		servers[exe.allocation.Location.URL()] = true
+	}
+	for _, server := range exe.inputs {
+		servers[server] = true
+	}
+	exe.stage.Unlock()
+
+	for server := range servers {
+		if s.checkAgent(server) {
+			continue
+		}
+		lostAgents = append(lostAgents, server)
+	}
+	return
+}
+
+// checkAgent returns false if the agent can not be connected.
+// A lost agent is never used again in this flow.
+func (s *Scheduler) checkAgent(server string) bool {
+	if s.isAgentLost(server) {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), AgentCheckTimeout)
+	defer cancel()
+	grpcConection, err := grpc.DialContext(ctx, server, grpc.WithInsecure(), grpc.WithBlock())
+	if err == nil {
+		grpcConection.Close()
+		return true
+	}
+
+	log.Printf("Agent %s is lost: %v", server, err)
+	s.Lock()
+	s.lostAgents[server] = true
+	s.Unlock()
+	return false
+}
+
+func (s *Scheduler) isAgentLost(server string) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.lostAgents[server]
+}
+
+// forgetOutputLocations lets the readers wait for the output of the next attempt.
+func (s *Scheduler) forgetOutputLocations(taskGroup *plan.TaskGroup) {
+	for _, shard := range taskGroup.Tasks[len(taskGroup.Tasks)-1].OutputShards {
+		s.shardLocator.RemoveShardLocation(shard.Name())
+	}
+}
+
+func hasReceivedOutput(task *flow.Task) bool {
+	for _, inChan := range task.InputChans {
+		if atomic.LoadInt64(&inChan.Counter) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resetOutputInputs prepares new input pipes to run the driver output again.
+func resetOutputInputs(task *flow.Task) {
+	for i := range task.InputChans {
+		task.InputChans[i] = util.NewPiper()
+	}
+}
+
+func isInMemoryInput(parent, taskGroup *plan.TaskGroup) bool {
+	for _, shard := range taskGroup.Tasks[0].InputShards {
+		if isOutputOf(parent, shard.Name()) && !shard.Dataset.GetIsOnDiskIO() {
+			return true
+		}
+	}
+	return false
+}
+
+func isOutputOf(taskGroup *plan.TaskGroup, shardName string) bool {
+	for _, shard := range taskGroup.Tasks[len(taskGroup.Tasks)-1].OutputShards {
+		if shard.Name() == shardName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chrislusf/gleamold/distributed/plan"
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/pb"
+)
+
+// planStages plans a flow whose sorted partitions are on disk, so the
+// task groups are split into 2 stages:
+//
+//	0 Slices, 1 ScatterPartitions, 2 and 3 CollectPartitions-Map-LocalSort
+//	4 MergeSortedTo, 5 Output
+func planStages(t *testing.T) (*Scheduler, []*plan.TaskGroup) {
+	f := flow.New()
+	f.Slices([][]interface{}{{3}, {1}, {2}}).Partition(2).Map("m").OnDisk(func(d *flow.Dataset) *flow.Dataset {
+		return d.LocalSort().MergeSortedTo(1)
+	}).Printlnf("%d")
+
+	_, taskGroups := plan.GroupTasks(f)
+	if len(taskGroups) != 6 {
+		t.Fatalf("planned %d task groups, expected 6", len(taskGroups))
+	}
+
+	s := NewScheduler("", &SchedulerOption{})
+	s.SetTaskGroups(taskGroups, nil)
+	return s, taskGroups
+}
+
+// finishAll marks every task group as completed, counting the re-executions.
+func finishAll(s *Scheduler, wg *sync.WaitGroup, taskGroups []*plan.TaskGroup) (reruns map[*plan.TaskGroup]int, lock *sync.Mutex) {
+	reruns = make(map[*plan.TaskGroup]int)
+	lock = &sync.Mutex{}
+	for _, tg := range taskGroups {
+		tg := tg
+		exe := s.getExecution(tg)
+		exe.setRerun(func() {
+			defer wg.Done()
+			lock.Lock()
+			reruns[tg]++
+			lock.Unlock()
+		})
+		exe.stop()
+	}
+	return
+}
+
+func TestSetTaskGroupsStages(t *testing.T) {
+	s, taskGroups := planStages(t)
+
+	first := s.getExecution(taskGroups[0]).stage
+	for _, tg := range taskGroups[1:4] {
+		if s.getExecution(tg).stage != first {
+			t.Errorf("%s is not in the stage of its in memory inputs", tg)
+		}
+	}
+
+	second := s.getExecution(taskGroups[4]).stage
+	if second == first {
+		t.Errorf("%s should not be in the stage of its on disk inputs", taskGroups[4])
+	}
+	if s.getExecution(taskGroups[5]).stage != second {
+		t.Errorf("%s is not in the stage of its in memory input", taskGroups[5])
+	}
+
+	if len(first.executions) != 4 || len(second.executions) != 2 {
+		t.Errorf("stages have %d and %d task groups, expected 4 and 2",
+			len(first.executions), len(second.executions))
+	}
+}
+
+func TestRestartStage(t *testing.T) {
+	s, taskGroups := planStages(t)
+	var wg sync.WaitGroup
+	reruns, lock := finishAll(s, &wg, taskGroups)
+
+	st := s.getExecution(taskGroups[4]).stage
+	if err := s.restartStage(&wg, st, 0); err != nil {
+		t.Fatalf("restart stage: %v", err)
+	}
+	wg.Wait()
+
+	if st.generation != 1 {
+		t.Errorf("stage generation is %d, expected 1", st.generation)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	for i, tg := range taskGroups {
+		expected := 0
+		if i >= 4 {
+			expected = 1
+		}
+		if reruns[tg] != expected {
+			t.Errorf("%s re-executed %d times, expected %d", tg, reruns[tg], expected)
+		}
+	}
+}
+
+func TestRestartStageWithStaleGeneration(t *testing.T) {
+	s, taskGroups := planStages(t)
+	var wg sync.WaitGroup
+	reruns, lock := finishAll(s, &wg, taskGroups)
+
+	st := s.getExecution(taskGroups[4]).stage
+	if err := s.restartStage(&wg, st, 0); err != nil {
+		t.Fatalf("restart stage: %v", err)
+	}
+	wg.Wait()
+
+	// another failure of the first attempt should not restart the stage again
+	if err := s.restartStage(&wg, st, 0); err != nil {
+		t.Fatalf("restart stage with stale generation: %v", err)
+	}
+	wg.Wait()
+
+	if st.generation != 1 {
+		t.Errorf("stage generation is %d, expected 1", st.generation)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if reruns[taskGroups[4]] != 1 {
+		t.Errorf("%s re-executed %d times, expected 1", taskGroups[4], reruns[taskGroups[4]])
+	}
+}
+
+func TestRestartStageNotIdempotent(t *testing.T) {
+	f := flow.New()
+	f.Ints([]int{3, 1, 2}).Map("m").Printlnf("%d")
+
+	_, taskGroups := plan.GroupTasks(f)
+	s := NewScheduler("", &SchedulerOption{})
+	s.SetTaskGroups(taskGroups, nil)
+	var wg sync.WaitGroup
+	finishAll(s, &wg, taskGroups)
+
+	st := s.getExecution(taskGroups[0]).stage
+	if err := s.restartStage(&wg, st, 0); err == nil {
+		t.Errorf("restarted a stage reading a channel")
+	}
+	if st.generation != 0 {
+		t.Errorf("stage generation is %d, expected 0", st.generation)
+	}
+}
+
+func TestReexecuteLostInputs(t *testing.T) {
+	s, taskGroups := planStages(t)
+	var wg sync.WaitGroup
+	reruns, lock := finishAll(s, &wg, taskGroups)
+
+	// the sorted partition of task group 2 is on a lost agent
+	lostShard := taskGroups[2].Tasks[len(taskGroups[2].Tasks)-1].OutputShards[0]
+	s.setShardLocation(lostShard, pb.DataLocation{
+		Name:     lostShard.Name(),
+		Location: &pb.Location{Server: "localhost", Port: 1},
+		OnDisk:   true,
+	})
+	s.lostAgents["localhost:1"] = true
+
+	if !s.isOutputLost(taskGroups[2], taskGroups[4]) {
+		t.Errorf("output of %s should be lost", taskGroups[2])
+	}
+	if s.isOutputLost(taskGroups[3], taskGroups[4]) {
+		t.Errorf("output of %s should not be lost", taskGroups[3])
+	}
+
+	if err := s.reexecuteLostInputs(&wg, s.getExecution(taskGroups[4])); err != nil {
+		t.Fatalf("re-execute lost inputs: %v", err)
+	}
+	wg.Wait()
+
+	if _, found := s.getShardLocation(lostShard); found {
+		t.Errorf("location of the lost shard %s is not forgotten", lostShard.Name())
+	}
+	if generation := s.getExecution(taskGroups[0]).getGeneration(); generation != 1 {
+		t.Errorf("input stage generation is %d, expected 1", generation)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	for i, tg := range taskGroups {
+		expected := 0
+		if i < 4 {
+			expected = 1
+		}
+		if reruns[tg] != expected {
+			t.Errorf("%s re-executed %d times, expected %d", tg, reruns[tg], expected)
+		}
+	}
+}
@@ -8,7 +8,12 @@ import (
 
 func (s *Scheduler) Score(r market.Requirement, bid float64, obj market.Object) float64 {
 	alloc := obj.(*pb.Allocation)
-	tg, loc := r.(*plan.TaskGroup), alloc.Location
+	tg, avoidServer := requirementOf(r)
+	loc := alloc.Location
+
+	if s.isAgentLost(loc.URL()) || loc.URL() == avoidServer {
+		return -1
+	}
 
 	memCost := memoryCost(tg)
 	if memCost > alloc.Allocated.MemoryMb {
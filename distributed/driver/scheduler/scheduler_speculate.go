@@ -0,0 +1,355 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/gleamold/distributed/driver/scheduler/market"
+	"github.com/chrislusf/gleamold/distributed/plan"
+	"github.com/chrislusf/gleamold/distributed/resource"
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/pb"
+)
+
+// SpeculationInterval is how often a running task group is compared with its siblings.
+var SpeculationInterval = 10 * time.Second
+
+// SpeculationQuantile is the fraction of the other task groups in the same step group
+// that should complete, before a straggling task group is duplicated.
+var SpeculationQuantile = 0.75
+
+// SpeculationMultiplier is how many times slower than the median of the completed
+// task groups a task group should be, to be duplicated.
+var SpeculationMultiplier = 1.5
+
+// speculativeDemand asks for an executor to duplicate a straggling task group,
+// on any agent other than the one running it.
+type speculativeDemand struct {
+	taskGroup   *plan.TaskGroup
+	avoidServer string
+}
+
+// requirementOf returns the task group of a market requirement,
+// and the agent to avoid for a speculative demand.
+func requirementOf(r market.Requirement) (taskGroup *plan.TaskGroup, avoidServer string) {
+	if demand, ok := r.(*speculativeDemand); ok {
+		return demand.taskGroup, demand.avoidServer
+	}
+	return r.(*plan.TaskGroup), ""
+}
+
+// progress tracks the primary execution of the current attempt,
+// and the completed attempt. The fields are guarded by the stage lock.
+type progress struct {
+	status      *pb.FlowExecutionStatus_TaskGroup_Execution
+	startedAt   time.Time
+	isCompleted bool
+	duration    time.Duration
+	rate        float64 // input rows per second
+}
+
+// isSpeculative returns true if the task group can be duplicated.
+// The duplicate reads all the inputs again, and writes the outputs to its own agent,
+// so all the inputs and outputs should be on disk.
+func (s *Scheduler) isSpeculative(taskGroup *plan.TaskGroup) bool {
+	if !s.Option.Speculation {
+		return false
+	}
+	tasks := taskGroup.Tasks
+	if tasks[0].Step.IsOnDriverSide || needsInputFromDriver(tasks[0]) || !isInputOnDisk(tasks[0]) {
+		return false
+	}
+	for _, task := range tasks {
+		if !task.Step.Meta.IsIdempotent {
+			return false
+		}
+	}
+	for _, shard := range tasks[len(tasks)-1].OutputShards {
+		if !shard.Dataset.GetIsOnDiskIO() {
+			return false
+		}
+	}
+	return true
+}
+
+// waitForSpeculativeParents waits for the parents that may be duplicated,
+// since the output locations are only final after they complete.
+func (s *Scheduler) waitForSpeculativeParents(ctx context.Context, taskGroup *plan.TaskGroup) error {
+	for _, parent := range taskGroup.Parents {
+		if !s.isSpeculative(parent) {
+			continue
+		}
+		if err := parent.WaitForCompletion(ctx); err != nil {
+			return fmt.Errorf("Failed to wait for %s: %v", parent, err)
+		}
+	}
+	return nil
+}
+
+type speculativeAttempt struct {
+	allocation *pb.Allocation
+	cancel     context.CancelFunc
+	status     *pb.FlowExecutionStatus_TaskGroup_Execution
+	err        error
+}
+
+// executeSpeculatively executes the task group on the allocation, and duplicates it
+// on another agent if it runs much slower than its siblings in the same step group.
+// The first execution to complete is kept, and the output of the other one is deleted.
+// Only the failed executions and the kept one are recorded in the task group status,
+// so the cancelled execution is not reported as a failure.
+func (s *Scheduler) executeSpeculatively(ctx context.Context,
+	fc *flow.Flow,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
+	wg *sync.WaitGroup,
+	exe *taskGroupExecution,
+	generation int,
+	allocation *pb.Allocation,
+	bid float64, relatedFiles []resource.FileResource) error {
+
+	return s.speculate(ctx, taskGroupStatus, exe, generation, allocation, bid,
+		func(attemptCtx context.Context, exeStatus *pb.FlowExecutionStatus_TaskGroup_Execution, allocation *pb.Allocation) error {
+			return s.executeOnAllocation(attemptCtx, fc, taskGroupStatus, exeStatus, exe.taskGroup, allocation, wg, relatedFiles)
+		})
+}
+
+// speculate runs execute on the allocation, and on another agent if it is straggling.
+func (s *Scheduler) speculate(ctx context.Context,
+	taskGroupStatus *pb.FlowExecutionStatus_TaskGroup,
+	exe *taskGroupExecution,
+	generation int,
+	allocation *pb.Allocation,
+	bid float64,
+	execute func(context.Context, *pb.FlowExecutionStatus_TaskGroup_Execution, *pb.Allocation) error) error {
+
+	taskGroup := exe.taskGroup
+	doneChan := make(chan *speculativeAttempt, 2)
+	run := func(attemptCtx context.Context, attempt *speculativeAttempt) {
+		attempt.status = &pb.FlowExecutionStatus_TaskGroup_Execution{}
+		attempt.err = attempt.status.Track(func(exeStatus *pb.FlowExecutionStatus_TaskGroup_Execution) error {
+			if attempt.allocation == allocation {
+				exe.startProgress(exeStatus)
+			}
+			return execute(attemptCtx, exeStatus, attempt.allocation)
+		})
+		doneChan <- attempt
+	}
+
+	primaryCtx, cancelPrimary := context.WithCancel(ctx)
+	primary := &speculativeAttempt{allocation: allocation, cancel: cancelPrimary}
+	go run(primaryCtx, primary)
+
+	var duplicate *speculativeAttempt
+	ticker := time.NewTicker(SpeculationInterval)
+	defer ticker.Stop()
+
+	var err error
+	var isPrimaryFailed bool
+	for running := 1; running > 0; {
+		select {
+		case attempt := <-doneChan:
+			running--
+			if attempt.err != nil {
+				isPrimaryFailed = isPrimaryFailed || attempt == primary
+				err = attempt.err
+				if attempt.status != nil {
+					taskGroupStatus.AddExecution(attempt.status)
+				}
+				if attempt == duplicate {
+					s.deleteOutputOnAllocation(taskGroup, attempt.allocation)
+				}
+				continue
+			}
+			// keep the first completed execution, and stop the other one
+			for _, other := range []*speculativeAttempt{primary, duplicate} {
+				if other != nil && other != attempt {
+					other.cancel()
+				}
+			}
+			for ; running > 0; running-- {
+				loser := <-doneChan
+				s.deleteOutputOnAllocation(taskGroup, loser.allocation)
+			}
+			if attempt == duplicate {
+				log.Printf("Duplicated %s on %s completed first.", taskGroup, attempt.allocation.Location.URL())
+				if isPrimaryFailed {
+					s.deleteOutputOnAllocation(taskGroup, primary.allocation)
+				}
+				s.moveOutputLocations(exe, generation, attempt.allocation)
+			}
+			taskGroupStatus.AddExecution(attempt.status)
+			exe.completeProgress()
+			return nil
+		case <-ticker.C:
+			if duplicate != nil || isPrimaryFailed || !s.isStraggling(exe) {
+				continue
+			}
+			duplicateCtx, cancelDuplicate := context.WithCancel(ctx)
+			duplicate = &speculativeAttempt{cancel: cancelDuplicate}
+			running++
+			go s.executeDuplicate(duplicateCtx, exe, allocation, bid, duplicate, doneChan, run)
+		}
+	}
+	return err
+}
+
+// executeDuplicate allocates an executor on another agent, and runs the duplicate there.
+func (s *Scheduler) executeDuplicate(ctx context.Context,
+	exe *taskGroupExecution,
+	primaryAllocation *pb.Allocation,
+	bid float64,
+	duplicate *speculativeAttempt,
+	doneChan chan *speculativeAttempt,
+	run func(context.Context, *speculativeAttempt)) {
+
+	pickedServerChan := make(chan market.Supply, 1)
+	s.Market.AddDemand(&speculativeDemand{
+		taskGroup:   exe.taskGroup,
+		avoidServer: primaryAllocation.Location.URL(),
+	}, bid, pickedServerChan)
+
+	var supply market.Supply
+	select {
+	case supply = <-pickedServerChan:
+	case <-ctx.Done():
+		if !s.Market.RemoveDemand(pickedServerChan) {
+			s.Market.ReturnSupply(<-pickedServerChan)
+		}
+		duplicate.err = ctx.Err()
+		doneChan <- duplicate
+		return
+	}
+	duplicate.allocation = supply.Object.(*pb.Allocation)
+	defer func() {
+		// do not hand out the executors on a lost agent again
+		if !s.isAgentLost(duplicate.allocation.Location.URL()) {
+			s.Market.ReturnSupply(supply)
+		}
+	}()
+
+	log.Printf("Duplicating straggling %s from %s to %s.", exe.taskGroup,
+		primaryAllocation.Location.URL(), duplicate.allocation.Location.URL())
+	run(ctx, duplicate)
+}
+
+// isStraggling returns true if most of the other task groups in the same step group
+// have completed, and the task group has been running much longer than them,
+// without processing its input much faster than them.
+func (s *Scheduler) isStraggling(exe *taskGroupExecution) bool {
+	siblings := exe.taskGroup.ParentStepGroup.TaskGroups
+	if len(siblings) < 2 {
+		return false
+	}
+
+	var durations, rates []float64
+	for _, sibling := range siblings {
+		if sibling == exe.taskGroup {
+			continue
+		}
+		p := s.getExecution(sibling).getProgress()
+		if !p.isCompleted {
+			continue
+		}
+		durations = append(durations, p.duration.Seconds())
+		if p.rate > 0 {
+			rates = append(rates, p.rate)
+		}
+	}
+	if float64(len(durations)) < SpeculationQuantile*float64(len(siblings)-1) {
+		return false
+	}
+
+	p := exe.getProgress()
+	if p.isCompleted || p.startedAt.IsZero() {
+		return false
+	}
+	elapsed := time.Since(p.startedAt)
+	if elapsed.Seconds() < SpeculationMultiplier*median(durations) {
+		return false
+	}
+	rate := float64(inputCount(exe.taskGroup, p.status)) / elapsed.Seconds()
+	if len(rates) > 0 && rate*SpeculationMultiplier >= median(rates) {
+		// just more inputs to process, a duplicate would not be faster
+		return false
+	}
+	return true
+}
+
+func (e *taskGroupExecution) startProgress(status *pb.FlowExecutionStatus_TaskGroup_Execution) {
+	e.stage.Lock()
+	defer e.stage.Unlock()
+	e.progress = progress{
+		status:    status,
+		startedAt: time.Now(),
+	}
+}
+
+func (e *taskGroupExecution) completeProgress() {
+	e.stage.Lock()
+	defer e.stage.Unlock()
+	e.progress.isCompleted = true
+	e.progress.duration = time.Since(e.progress.startedAt)
+	if seconds := e.progress.duration.Seconds(); seconds > 0 {
+		e.progress.rate = float64(inputCount(e.taskGroup, e.progress.status)) / seconds
+	}
+}
+
+func (e *taskGroupExecution) getProgress() progress {
+	e.stage.Lock()
+	defer e.stage.Unlock()
+	return e.progress
+}
+
+// moveOutputLocations lets the readers read from the duplicate,
+// unless the stage has been re-executed.
+func (s *Scheduler) moveOutputLocations(exe *taskGroupExecution, generation int, allocation *pb.Allocation) {
+	exe.stage.Lock()
+	defer exe.stage.Unlock()
+
+	if exe.stage.generation != generation {
+		return
+	}
+	exe.allocation = allocation
+	s.setOutputLocations(exe.taskGroup, allocation)
+}
+
+func (s *Scheduler) deleteOutputOnAllocation(taskGroup *plan.TaskGroup, allocation *pb.Allocation) {
+	if allocation == nil || s.isAgentLost(allocation.Location.URL()) {
+		return
+	}
+	tasks := taskGroup.Tasks
+	for _, shard := range tasks[len(tasks)-1].OutputShards {
+		if err := sendDeleteRequest(allocation.Location.URL(), &pb.DeleteDatasetShardRequest{
+			Name: shard.Name(),
+		}); err != nil {
+			println("Purging dataset error:", err.Error())
+		}
+	}
+}
+
+// inputCount is the number of input rows processed by the first task.
+func inputCount(taskGroup *plan.TaskGroup, status *pb.FlowExecutionStatus_TaskGroup_Execution) (count int64) {
+	if status == nil || status.ExecutionStat == nil {
+		return 0
+	}
+	firstTask := taskGroup.Tasks[0]
+	for _, stat := range status.ExecutionStat.Stats {
+		if stat.StepId == int32(firstTask.Step.Id) && stat.TaskId == int32(firstTask.Id) {
+			count += stat.InputCounter
+		}
+	}
+	return
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
+}
@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/gleamold/distributed/driver/scheduler/market"
+	"github.com/chrislusf/gleamold/distributed/plan"
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/pb"
+)
+
+// planSiblings plans a flow with 4 task groups in the same step group,
+// and returns the first one of them.
+func planSiblings(t *testing.T) (*Scheduler, *plan.TaskGroup) {
+	f := flow.New()
+	f.Slices([][]interface{}{{3}, {1}, {2}}).Partition(4).Map("m").Printlnf("%d")
+
+	_, taskGroups := plan.GroupTasks(f)
+	taskGroup := taskGroups[2]
+	if n := len(taskGroup.ParentStepGroup.TaskGroups); n != 4 {
+		t.Fatalf("planned %d task groups in the step group, expected 4", n)
+	}
+
+	s := NewScheduler("", &SchedulerOption{Speculation: true})
+	s.SetTaskGroups(taskGroups, nil)
+	return s, taskGroup
+}
+
+// completeSiblings sets the progress of the other task groups in the step group.
+func completeSiblings(s *Scheduler, taskGroup *plan.TaskGroup, durations []time.Duration, rate float64) {
+	var i int
+	for _, sibling := range taskGroup.ParentStepGroup.TaskGroups {
+		if sibling == taskGroup || i >= len(durations) {
+			continue
+		}
+		s.getExecution(sibling).progress = progress{
+			isCompleted: true,
+			duration:    durations[i],
+			rate:        rate,
+		}
+		i++
+	}
+}
+
+func TestIsStraggling(t *testing.T) {
+	seconds := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		rate      float64 // input rows per second of the completed siblings
+		elapsed   time.Duration
+		inputRows int64
+		expected  bool
+	}{
+		{"too few siblings completed", seconds[:2], 0, time.Minute, 0, false},
+		{"not slower than the median", seconds, 0, 2900 * time.Millisecond, 0, false},
+		{"slower than the median", seconds, 0, 3100 * time.Millisecond, 0, true},
+		{"slower and processing slowly", seconds, 100, 4 * time.Second, 40, true},
+		{"slower but processing more inputs", seconds, 100, 4 * time.Second, 400, false},
+	}
+
+	for _, test := range tests {
+		s, taskGroup := planSiblings(t)
+		completeSiblings(s, taskGroup, test.durations, test.rate)
+
+		firstTask := taskGroup.Tasks[0]
+		exe := s.getExecution(taskGroup)
+		exe.progress = progress{
+			status: &pb.FlowExecutionStatus_TaskGroup_Execution{
+				ExecutionStat: &pb.ExecutionStat{
+					Stats: []*pb.InstructionStat{{
+						StepId:       int32(firstTask.Step.Id),
+						TaskId:       int32(firstTask.Id),
+						InputCounter: test.inputRows,
+					}},
+				},
+			},
+			startedAt: time.Now().Add(-test.elapsed),
+		}
+
+		if actual := s.isStraggling(exe); actual != test.expected {
+			t.Errorf("%s: isStraggling is %v, expected %v", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestIsStragglingCompleted(t *testing.T) {
+	s, taskGroup := planSiblings(t)
+	completeSiblings(s, taskGroup, []time.Duration{time.Second, time.Second, time.Second}, 0)
+
+	exe := s.getExecution(taskGroup)
+	exe.progress = progress{startedAt: time.Now().Add(-time.Minute), isCompleted: true}
+	if s.isStraggling(exe) {
+		t.Errorf("a completed task group is straggling")
+	}
+}
+
+func testAllocation(port int32) *pb.Allocation {
+	return &pb.Allocation{
+		Location:  &pb.Location{Server: "127.0.0.1", Port: port},
+		Allocated: &pb.ComputeResource{MemoryMb: 1024},
+	}
+}
+
+// speculateUntilDuplicated runs the task group on the primary allocation,
+// which is straggling, and duplicates it to the only other allocation.
+// The primary completes if primaryWins, otherwise the duplicate completes.
+func speculateUntilDuplicated(t *testing.T, primaryWins bool) (s *Scheduler, taskGroup *plan.TaskGroup,
+	status *pb.FlowExecutionStatus_TaskGroup, err error, cancelled map[int32]bool) {
+
+	defer func(interval time.Duration) {
+		SpeculationInterval = interval
+	}(SpeculationInterval)
+	SpeculationInterval = 10 * time.Millisecond
+
+	s, taskGroup = planSiblings(t)
+	completeSiblings(s, taskGroup, []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}, 0)
+	primary, duplicate := testAllocation(1), testAllocation(2)
+	s.Market.AddSupply(market.Supply{Object: duplicate})
+
+	duplicatedChan := make(chan bool)
+	cancelledChan := make(chan int32, 2)
+	status = &pb.FlowExecutionStatus_TaskGroup{}
+	err = s.speculate(context.Background(), status, s.getExecution(taskGroup), 0, primary, 1,
+		func(ctx context.Context, exeStatus *pb.FlowExecutionStatus_TaskGroup_Execution, allocation *pb.Allocation) error {
+			if allocation == duplicate {
+				close(duplicatedChan)
+			} else {
+				<-duplicatedChan
+			}
+			if (allocation == primary) == primaryWins {
+				return nil
+			}
+			<-ctx.Done()
+			cancelledChan <- allocation.Location.Port
+			return ctx.Err()
+		})
+	close(cancelledChan)
+
+	cancelled = make(map[int32]bool)
+	for port := range cancelledChan {
+		cancelled[port] = true
+	}
+	return
+}
+
+func TestSpeculateCancelsDuplicate(t *testing.T) {
+	s, taskGroup, status, err, cancelled := speculateUntilDuplicated(t, true)
+	if err != nil {
+		t.Fatalf("speculative execution failed: %v", err)
+	}
+	if !cancelled[2] {
+		t.Errorf("duplicate is not cancelled after the primary completed")
+	}
+	if len(status.Executions) != 1 || status.Executions[0].Error != nil {
+		t.Errorf("recorded executions %+v, expected only the completed one", status.Executions)
+	}
+	if allocation := s.getExecution(taskGroup).allocation; allocation != nil {
+		t.Errorf("output moved to %s, expected to stay on the primary", allocation.Location.URL())
+	}
+}
+
+func TestSpeculateCancelsPrimary(t *testing.T) {
+	s, taskGroup, status, err, cancelled := speculateUntilDuplicated(t, false)
+	if err != nil {
+		t.Fatalf("speculative execution failed: %v", err)
+	}
+	if !cancelled[1] {
+		t.Errorf("primary is not cancelled after the duplicate completed")
+	}
+	if len(status.Executions) != 1 || status.Executions[0].Error != nil {
+		t.Errorf("recorded executions %+v, expected only the completed one", status.Executions)
+	}
+	if allocation := s.getExecution(taskGroup).allocation; allocation == nil || allocation.Location.Port != 2 {
+		t.Errorf("output is not moved to the duplicate")
+	}
+}
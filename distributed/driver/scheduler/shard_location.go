@@ -42,6 +42,16 @@ func (l *DatasetShardLocator) SetShardLocation(name string, location pb.DataLoca
 	l.waitForAllInputs.Broadcast()
 }
 
+// RemoveShardLocation makes the readers wait until the shard is registered again.
+func (l *DatasetShardLocator) RemoveShardLocation(name string) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.datasetShard2LocationLock.Lock()
+	defer l.datasetShard2LocationLock.Unlock()
+	delete(l.datasetShard2Location, name)
+}
+
 func (l *DatasetShardLocator) isDatasetShardRegistered(shard *flow.DatasetShard) bool {
 
 	if _, hasValue := l.GetShardLocation(shard.Name()); !hasValue {
@@ -18,6 +18,7 @@ import (
 	exe "github.com/chrislusf/gleamold/distributed/executor"
 	m "github.com/chrislusf/gleamold/distributed/master"
 	"github.com/chrislusf/gleamold/distributed/netchan"
+	"github.com/chrislusf/gleamold/instruction"
 	"github.com/chrislusf/gleamold/pb"
 	"github.com/chrislusf/gleamold/util"
 	"github.com/chrislusf/gleamold/util/on_interrupt"
@@ -79,6 +80,11 @@ func main() {
 			log.Fatal("unmarshaling instructions error: ", err)
 		}
 
+		// executors run inside the agent directory, spill sorted runs there
+		if dir, err := os.Getwd(); err == nil {
+			instruction.SpillDir = dir
+		}
+
 		if instructionSet.IsProfiling {
 			f, err := os.Create(fmt.Sprintf("exe-%d-%s.pprof", instructionSet.GetFlowHashCode(), strings.Join(instructionSet.InstructionNames(), "-")))
 			if err != nil {
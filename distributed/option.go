@@ -16,6 +16,7 @@ type DistributedOption struct {
 	TaskMemoryMB  int
 	FlowBid       float64
 	Module        string
+	Speculation   bool
 }
 
 func Option() *DistributedOption {
@@ -36,6 +37,7 @@ func (o *DistributedOption) GetFlowRunner() flow.FlowRunner {
 		TaskMemoryMB:  o.TaskMemoryMB,
 		FlowBid:       o.FlowBid,
 		Module:        o.Module,
+		Speculation:   o.Speculation,
 	})
 }
 
@@ -49,6 +51,15 @@ func (o *DistributedOption) SetMaster(master string) *DistributedOption {
 	return o
 }
 
+// EnableSpeculation runs a duplicate of a task group on another agent,
+// if it is much slower than the other task groups of the same step group.
+// Only the task groups with all inputs and outputs on disk are duplicated.
+// The first one to complete is kept, and the other one is stopped.
+func (o *DistributedOption) EnableSpeculation() *DistributedOption {
+	o.Speculation = true
+	return o
+}
+
 // WithFile sends any related file over to gleamold agents
 // so the task can still access these files on gleamold agents.
 // The files are placed on the executed task's current working directory.
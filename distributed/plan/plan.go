@@ -1,6 +1,7 @@
 package plan
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -65,6 +66,15 @@ func (t *TaskGroup) AddParent(parent *TaskGroup) *TaskGroup {
 	return t
 }
 
+func (t *TaskGroup) hasParent(parent *TaskGroup) bool {
+	for _, p := range t.Parents {
+		if p == parent {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *TaskGroup) String() string {
 	var steps []string
 	for _, task := range t.Tasks {
@@ -93,11 +103,53 @@ func (t *TaskGroup) RequiredResources() *pb.ComputeResource {
 }
 
 func (t *TaskGroup) MarkStop(err error) {
+	t.ParentStepGroup.Lock()
+	defer t.ParentStepGroup.Unlock()
+
 	t.StopAt = time.Now()
 	t.Error = err
 	t.ParentStepGroup.waitForAllTasks.Broadcast()
 }
 
+// MarkRestart makes the tasks waiting for this task group wait again,
+// until it is re-executed.
+func (t *TaskGroup) MarkRestart() {
+	t.ParentStepGroup.Lock()
+	defer t.ParentStepGroup.Unlock()
+
+	t.StopAt = time.Time{}
+	t.Error = nil
+}
+
+// WaitForCompletion waits until the task group stops, and returns its error.
+// It returns the error of the context if the context is done first.
+func (t *TaskGroup) WaitForCompletion(ctx context.Context) error {
+	s := t.ParentStepGroup
+
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Lock()
+			s.waitForAllTasks.Broadcast()
+			s.Unlock()
+		case <-stopChan:
+		}
+	}()
+
+	s.Lock()
+	defer s.Unlock()
+
+	for t.StopAt.IsZero() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.waitForAllTasks.Wait()
+	}
+	return t.Error
+}
+
 func (s *StepGroup) WaitForAllTasksToComplete() {
 	s.Lock()
 	defer s.Unlock()
@@ -25,9 +25,29 @@ func translateToTaskGroups(stepId2StepGroup []*StepGroup) (ret []*TaskGroup) {
 			ret = append(ret, tg)
 		}
 	}
+	linkParentTaskGroups(ret)
 	return
 }
 
+// a task group depends on the task groups writing its input shards
+func linkParentTaskGroups(taskGroups []*TaskGroup) {
+	shard2TaskGroup := make(map[*flow.DatasetShard]*TaskGroup)
+	for _, tg := range taskGroups {
+		for _, shard := range tg.Tasks[len(tg.Tasks)-1].OutputShards {
+			shard2TaskGroup[shard] = tg
+		}
+	}
+	for _, tg := range taskGroups {
+		for _, shard := range tg.Tasks[0].InputShards {
+			parent, found := shard2TaskGroup[shard]
+			if !found || tg.hasParent(parent) {
+				continue
+			}
+			tg.AddParent(parent)
+		}
+	}
+}
+
 func assertSameNumberOfTasks(steps []*flow.Step) {
 	if len(steps) == 0 {
 		return
@@ -1,7 +1,10 @@
 package plan
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/chrislusf/gleamold/flow"
 	"github.com/chrislusf/gleamold/pb"
@@ -48,3 +51,62 @@ func PrintInstructionSet(instructions *pb.InstructionSet) {
 		println(ins.String())
 	}
 }
+
+func TestTaskGroupParents(t *testing.T) {
+
+	f := flow.New()
+	f.Ints([]int{3, 1, 2, 5, 4}).Partition(3).LocalSort().MergeSortedTo(1).Printlnf("%d")
+
+	_, taskGroups := GroupTasks(f)
+
+	for _, taskGroup := range taskGroups {
+		for _, shard := range taskGroup.Tasks[0].InputShards {
+			var writers int
+			for _, parent := range taskGroup.Parents {
+				for _, output := range parent.Tasks[len(parent.Tasks)-1].OutputShards {
+					if output == shard {
+						writers++
+					}
+				}
+			}
+			if writers != 1 {
+				t.Errorf("task group %s input %s has %d parent writers", taskGroup, shard.Name(), writers)
+			}
+		}
+	}
+
+	last := taskGroups[len(taskGroups)-1]
+	if len(last.Parents) != 1 || len(last.Parents[0].Parents) != 3 {
+		t.Errorf("unexpected parents of %s: %v", last, last.Parents)
+	}
+}
+
+func TestTaskGroupWaitForCompletion(t *testing.T) {
+
+	taskGroup := NewTaskGroup()
+	taskGroup.ParentStepGroup = NewStepGroup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := taskGroup.WaitForCompletion(ctx); err != context.Canceled {
+		t.Errorf("waiting with a cancelled context returned %v", err)
+	}
+
+	failure := errors.New("failed")
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		taskGroup.MarkStop(failure)
+	}()
+	if err := taskGroup.WaitForCompletion(context.Background()); err != failure {
+		t.Errorf("waiting for a failed task group returned %v", err)
+	}
+
+	taskGroup.MarkRestart()
+	taskGroup.MarkStop(nil)
+	if err := taskGroup.WaitForCompletion(context.Background()); err != nil {
+		t.Errorf("waiting for a completed task group returned %v", err)
+	}
+}
@@ -19,8 +19,9 @@ func (o *DistributedPlanner) GetFlowRunner() flow.FlowRunner {
 	return o
 }
 
-// driver runs on local, controlling all tasks
-func (fcd *DistributedPlanner) RunFlowContext(ctx context.Context, fc *flow.Flow) {
+// RunFlowContext only prints out the execution plan.
+// Nothing is executed, so there is no result.
+func (fcd *DistributedPlanner) RunFlowContext(ctx context.Context, fc *flow.Flow) (*flow.RunResult, error) {
 
 	stepGroups, taskGroups := plan.GroupTasks(fc)
 
@@ -59,4 +60,5 @@ func (fcd *DistributedPlanner) RunFlowContext(ctx context.Context, fc *flow.Flow
 		}
 	}
 
+	return nil, nil
 }
@@ -58,7 +58,7 @@ func gleamSortStandalone(fileName string, partition int) {
        function(line)
          return string.sub(line, 1, 10), string.sub(line, 13)
        end
-   `).Partition(partition).Sort().Printlnf("%s  %s").Run()
+   `).SortTo(partition).MergeTo(1).Printlnf("%s  %s").Run()
 }
 
 func linuxSortDistributed(fileName string, partition int) {
@@ -104,14 +104,15 @@ func gleamSortDistributed(fileName string, size int64, partition int, isPureGoMa
 	}
 
 	if isInMemory {
-		f = f.Partition(partition).Sort()
+		f = f.SortTo(partition)
 	} else {
 		f = f.OnDisk(func(d *flow.Dataset) *flow.Dataset {
-			return d.Partition(partition).Sort()
+			return d.SortTo(partition)
 		})
 	}
 
-	f = f.Printlnf("%s  %s")
+	// the shards are globally ordered, so concatenating them keeps the order
+	f = f.MergeTo(1).Printlnf("%s  %s")
 
 	// f.Run(distributed.Planner())
 	// return
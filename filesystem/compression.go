@@ -0,0 +1,246 @@
+package filesystem
+
+// this file adds transparent decompression when opening files,
+// and the matching compression when creating files
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is a compression format of files.
+type Compression string
+
+const (
+	NoCompression Compression = ""
+	Gzip          Compression = "gzip"
+	Bzip2         Compression = "bzip2"
+	Zstd          Compression = "zstd"
+	// Snappy is the snappy framing format, not the raw block format.
+	Snappy Compression = "snappy"
+)
+
+var compressionFormats = []struct {
+	compression Compression
+	extensions  []string
+	isHeader    func(header []byte) bool
+}{
+	{Gzip, []string{".gz", ".gzip"}, hasMagic([]byte{0x1f, 0x8b})},
+	{Bzip2, []string{".bz2", ".bzip2"}, isBzip2Header},
+	{Zstd, []string{".zst", ".zstd"}, hasMagic([]byte{0x28, 0xb5, 0x2f, 0xfd})},
+	{Snappy, []string{".sz", ".snappy"}, hasMagic([]byte("\xff\x06\x00\x00sNaPpY"))},
+}
+
+// compressionHeaderSize is the number of bytes read to detect the compression.
+const compressionHeaderSize = 10
+
+func hasMagic(magic []byte) func(header []byte) bool {
+	return func(header []byte) bool {
+		return bytes.HasPrefix(header, magic)
+	}
+}
+
+// isBzip2Header checks "BZh", the block size from '1' to '9',
+// and the magic of the first block, or of the end of an empty stream.
+func isBzip2Header(header []byte) bool {
+	if len(header) < compressionHeaderSize || !bytes.HasPrefix(header, []byte("BZh")) ||
+		header[3] < '1' || header[3] > '9' {
+		return false
+	}
+	return bytes.Equal(header[4:10], []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}) ||
+		bytes.Equal(header[4:10], []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90})
+}
+
+// CompressionOf returns the compression format by the file extension.
+func CompressionOf(filepath string) Compression {
+	for _, format := range compressionFormats {
+		for _, extension := range format.extensions {
+			if strings.HasSuffix(filepath, extension) {
+				return format.compression
+			}
+		}
+	}
+	return NoCompression
+}
+
+// Extension returns the usual file extension of the compression format.
+func (c Compression) Extension() string {
+	for _, format := range compressionFormats {
+		if format.compression == c {
+			return format.extensions[0]
+		}
+	}
+	return ""
+}
+
+// IsCompressed returns true if the file is compressed,
+// by the file extension or the magic bytes at the beginning.
+// Only the file extension is checked for the files not on the local disk.
+// A compressed file can not be read from the middle.
+func IsCompressed(filepath string) bool {
+	fs, err := fileSystemOf(filepath)
+	if err != nil {
+		return false
+	}
+	if !isLocal(fs) {
+		return CompressionOf(filepath) != NoCompression
+	}
+	fileLocation := &FileLocation{filepath}
+	if fs.IsDir(fileLocation) {
+		return false
+	}
+	file, err := fs.Open(fileLocation)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	return detectCompression(fs, filepath, file) != NoCompression
+}
+
+// detectCompression checks the file extension, and then the magic bytes
+// of the local files. Reading the magic bytes of a remote file costs
+// another request, so the remote files need the file extension.
+func detectCompression(fs VirtualFileSystem, filepath string, file VirtualFile) Compression {
+	if compression := CompressionOf(filepath); compression != NoCompression || !isLocal(fs) {
+		return compression
+	}
+	header := make([]byte, compressionHeaderSize)
+	n, _ := file.ReadAt(header, 0)
+	for _, format := range compressionFormats {
+		if format.isHeader(header[:n]) {
+			return format.compression
+		}
+	}
+	return NoCompression
+}
+
+func isLocal(fs VirtualFileSystem) bool {
+	_, ok := fs.(*LocalFileSystem)
+	return ok
+}
+
+// decompress wraps the file with the decompressor of the compression format.
+func decompress(fs VirtualFileSystem, filepath string, file VirtualFile) (VirtualFile, error) {
+	var reader io.Reader
+	var closer io.Closer
+	switch compression := detectCompression(fs, filepath, file); compression {
+	case NoCompression:
+		return file, nil
+	case Gzip:
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		reader, closer = gzipReader, gzipReader
+	case Bzip2:
+		reader = bzip2.NewReader(file)
+	case Zstd:
+		zstdReader, err := zstd.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		reader, closer = zstdReader, zstdReader.IOReadCloser()
+	case Snappy:
+		reader = snappy.NewReader(file)
+	default:
+		return nil, fmt.Errorf("Unknown compression %s of %s", compression, filepath)
+	}
+	return &decompressedFile{Reader: reader, decompressor: closer, file: file, filepath: filepath}, nil
+}
+
+// decompressedFile can only be read sequentially.
+type decompressedFile struct {
+	io.Reader
+	decompressor io.Closer
+	file         VirtualFile
+	filepath     string
+}
+
+func (f *decompressedFile) ReadAt(p []byte, off int64) (int, error) {
+	return 0, fmt.Errorf("Can not read compressed file %s at %d", f.filepath, off)
+}
+
+func (f *decompressedFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, fmt.Errorf("Can not seek compressed file %s", f.filepath)
+}
+
+func (f *decompressedFile) Close() error {
+	if f.decompressor != nil {
+		f.decompressor.Close()
+	}
+	return f.file.Close()
+}
+
+// NewCompressedWriter compresses the written bytes into the writer.
+// Closing the returned writer also closes the writer.
+// Bzip2 compression runs the external bzip2 command, which should be
+// installed where the files are written, since Go can only decompress bzip2.
+func NewCompressedWriter(writer io.WriteCloser, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case NoCompression:
+		return writer, nil
+	case Gzip:
+		return &compressedWriter{gzip.NewWriter(writer), writer}, nil
+	case Zstd:
+		zstdWriter, err := zstd.NewWriter(writer)
+		if err != nil {
+			return nil, err
+		}
+		return &compressedWriter{zstdWriter, writer}, nil
+	case Snappy:
+		return &compressedWriter{snappy.NewBufferedWriter(writer), writer}, nil
+	case Bzip2:
+		return newCommandWriter(writer, "bzip2", "-c")
+	}
+	return nil, fmt.Errorf("Unknown compression %s", compression)
+}
+
+type compressedWriter struct {
+	io.WriteCloser
+	writer io.WriteCloser
+}
+
+func (w *compressedWriter) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		w.writer.Close()
+		return err
+	}
+	return w.writer.Close()
+}
+
+// commandWriter pipes the written bytes through the command into the writer.
+type commandWriter struct {
+	io.WriteCloser
+	command *exec.Cmd
+	writer  io.WriteCloser
+}
+
+func newCommandWriter(writer io.WriteCloser, name string, args ...string) (*commandWriter, error) {
+	command := exec.Command(name, args...)
+	command.Stdout = writer
+	stdin, err := command.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = command.Start(); err != nil {
+		return nil, fmt.Errorf("Failed to start %s: %v", name, err)
+	}
+	return &commandWriter{stdin, command, writer}, nil
+}
+
+func (w *commandWriter) Close() error {
+	w.WriteCloser.Close()
+	if err := w.command.Wait(); err != nil {
+		w.writer.Close()
+		return fmt.Errorf("Failed to compress with %s: %v", w.command.Path, err)
+	}
+	return w.writer.Close()
+}
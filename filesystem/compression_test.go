@@ -0,0 +1,117 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "compression")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := "hello\nworld\n"
+	for _, compression := range []Compression{Gzip, Bzip2, Zstd, Snappy} {
+		// detected by the file extension, or by the magic bytes without it
+		withExtension := dir + "/data" + compression.Extension()
+		withoutExtension := dir + "/data-" + string(compression)
+		for _, filePath := range []string{withExtension, withoutExtension} {
+			file, err := Create(filePath)
+			if err != nil {
+				t.Fatalf("Failed to create %s: %v", filePath, err)
+			}
+			writer, err := NewCompressedWriter(file, compression)
+			if err != nil {
+				t.Fatalf("Failed to compress %s: %v", filePath, err)
+			}
+			io.WriteString(writer, content)
+			if err = writer.Close(); err != nil {
+				t.Fatalf("Failed to close %s: %v", filePath, err)
+			}
+
+			if !IsCompressed(filePath) {
+				t.Errorf("Expect %s to be compressed", filePath)
+			}
+			reader, err := Open(filePath)
+			if err != nil {
+				t.Fatalf("Failed to open %s: %v", filePath, err)
+			}
+			data, err := ioutil.ReadAll(reader)
+			reader.Close()
+			if err != nil || string(data) != content {
+				t.Errorf("Expect %s to read %q, but got %q: %v", filePath, content, data, err)
+			}
+		}
+	}
+
+	if CompressionOf(dir+"/data.txt") != NoCompression || IsCompressed(dir) {
+		t.Errorf("Expect no compression for plain files")
+	}
+}
+
+func TestDetectCompressionByHeader(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "compression")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		content  string
+		expected Compression
+	}{
+		{"BZh91AY&SYxxxx", Bzip2},
+		{"BZh1\x17\x72\x45\x38\x50\x90xxxx", Bzip2},
+		{"BZhello world\n", NoCompression},
+		{"BZh9 is not bzip2\n", NoCompression},
+		{"BZh", NoCompression},
+		{"\x1f\x8bxxxx", Gzip},
+		{"hello world\n", NoCompression},
+	}
+	for i, test := range tests {
+		filePath := fmt.Sprintf("%s/data-%d", dir, i)
+		if err := ioutil.WriteFile(filePath, []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", filePath, err)
+		}
+		file, err := os.Open(filePath)
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", filePath, err)
+		}
+		compression := detectCompression(&LocalFileSystem{}, filePath, file)
+		file.Close()
+		if compression != test.expected {
+			t.Errorf("Expect %q to be %q, but got %q", test.content, test.expected, compression)
+		}
+	}
+}
+
+// unreadableFile fails the test if it is read.
+type unreadableFile struct {
+	VirtualFile
+	t *testing.T
+}
+
+func (f *unreadableFile) ReadAt(p []byte, off int64) (int, error) {
+	f.t.Errorf("Remote file is read to detect the compression")
+	return 0, io.EOF
+}
+
+func TestDetectCompressionOfRemoteFile(t *testing.T) {
+	file := &unreadableFile{t: t}
+	if compression := detectCompression(&S3FileSystem{}, "s3://bucket/data.bz2", file); compression != Bzip2 {
+		t.Errorf("Expect bzip2 by the file extension, but got %q", compression)
+	}
+	if compression := detectCompression(&S3FileSystem{}, "s3://bucket/data", file); compression != NoCompression {
+		t.Errorf("Expect no compression without the file extension, but got %q", compression)
+	}
+	if !IsCompressed("s3://bucket/data.gz") || IsCompressed("s3://bucket/data") {
+		t.Errorf("Expect remote files to be compressed by the file extension")
+	}
+}
@@ -5,6 +5,7 @@ package filesystem
 import (
 	"fmt"
 	"io"
+	"time"
 )
 
 type OptionName string
@@ -18,8 +19,16 @@ type FileLocation struct {
 }
 
 type VirtualFile interface {
-	// io.ReaderAt
 	io.ReadCloser
+	io.ReaderAt
+	io.Seeker
+}
+
+// FileInfo describes a file or a directory.
+type FileInfo struct {
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
 }
 
 type VirtualFileSystem interface {
@@ -27,6 +36,13 @@ type VirtualFileSystem interface {
 	Open(*FileLocation) (VirtualFile, error)
 	List(*FileLocation) ([]*FileLocation, error)
 	IsDir(*FileLocation) bool
+	Stat(*FileLocation) (*FileInfo, error)
+	// Create creates or truncates the file, and the missing parent directories.
+	// The file is complete only after the writer is closed without error.
+	Create(*FileLocation) (io.WriteCloser, error)
+	// Rename replaces the target file if it exists.
+	Rename(from, to *FileLocation) error
+	Delete(*FileLocation) error
 }
 
 var (
@@ -41,32 +57,87 @@ func Set(name OptionName, value string) {
 	Option[name] = value
 }
 
+// Open opens the file for reading.
+// A compressed file is decompressed, and can only be read sequentially.
 func Open(filepath string) (VirtualFile, error) {
-	fileLocation := &FileLocation{filepath}
-	for _, fs := range fileSystems {
-		if fs.Accept(fileLocation) {
-			return fs.Open(fileLocation)
-		}
+	fs, err := fileSystemOf(filepath)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("Unknown file %s", filepath)
+	file, err := fs.Open(&FileLocation{filepath})
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := decompress(fs, filepath, file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Failed to decompress %s: %v", filepath, err)
+	}
+	return decompressed, nil
 }
 
 func List(filepath string) ([]*FileLocation, error) {
-	fileLocation := &FileLocation{filepath}
-	for _, fs := range fileSystems {
-		if fs.Accept(fileLocation) {
-			return fs.List(fileLocation)
-		}
+	fs, err := fileSystemOf(filepath)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("Unknown file %s", filepath)
+	return fs.List(&FileLocation{filepath})
 }
 
 func IsDir(filepath string) bool {
+	fs, err := fileSystemOf(filepath)
+	if err != nil {
+		return false
+	}
+	return fs.IsDir(&FileLocation{filepath})
+}
+
+// Stat returns the size and modification time of the file.
+func Stat(filepath string) (*FileInfo, error) {
+	fs, err := fileSystemOf(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(&FileLocation{filepath})
+}
+
+// Create creates or truncates the file for writing.
+func Create(filepath string) (io.WriteCloser, error) {
+	fs, err := fileSystemOf(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Create(&FileLocation{filepath})
+}
+
+// Rename moves the file within the same file system.
+func Rename(from, to string) error {
+	fs, err := fileSystemOf(from)
+	if err != nil {
+		return err
+	}
+	toLocation := &FileLocation{to}
+	if !fs.Accept(toLocation) {
+		return fmt.Errorf("Can not rename %s to %s on another file system", from, to)
+	}
+	return fs.Rename(&FileLocation{from}, toLocation)
+}
+
+// Delete removes the file.
+func Delete(filepath string) error {
+	fs, err := fileSystemOf(filepath)
+	if err != nil {
+		return err
+	}
+	return fs.Delete(&FileLocation{filepath})
+}
+
+func fileSystemOf(filepath string) (VirtualFileSystem, error) {
 	fileLocation := &FileLocation{filepath}
 	for _, fs := range fileSystems {
 		if fs.Accept(fileLocation) {
-			return fs.IsDir(fileLocation)
+			return fs, nil
 		}
 	}
-	return false
+	return nil, fmt.Errorf("Unknown file %s", filepath)
 }
@@ -2,6 +2,7 @@ package filesystem
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -24,22 +25,15 @@ func (fs *HdfsFileSystem) Accept(fl *FileLocation) bool {
 }
 
 func (fs *HdfsFileSystem) Open(fl *FileLocation) (VirtualFile, error) {
-	namenode, path, err := splitLocationToParts(fl.Location)
+	client, path, err := newHdfsClient(fl)
 	if err != nil {
 		return nil, err
 	}
-	if namenode == "" {
-		namenode = os.Getenv("HADOOP_NAMENODE")
-	}
-
-	client, err := hdfs.New(namenode)
+	file, err := client.Open(path)
 	if err != nil {
-		log.Fatalf("failed to create client to %s:%v\n", namenode, err)
+		return nil, err
 	}
-
-	file, err := client.Open(path)
-
-	return file, err
+	return file, nil
 }
 
 // List generates a full list of file locations under the given
@@ -91,6 +85,66 @@ func (fs *HdfsFileSystem) IsDir(fl *FileLocation) bool {
 	return fi.IsDir()
 }
 
+func (fs *HdfsFileSystem) Stat(fl *FileLocation) (*FileInfo, error) {
+	client, path, err := newHdfsClient(fl)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := client.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{Size: fi.Size(), ModTime: fi.ModTime(), IsDir: fi.IsDir()}, nil
+}
+
+func (fs *HdfsFileSystem) Create(fl *FileLocation) (io.WriteCloser, error) {
+	client, path, err := newHdfsClient(fl)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.MkdirAll(path[:strings.LastIndex(path, "/")+1], 0755); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of %s:%v", fl.Location, err)
+	}
+	client.Remove(path)
+	return client.Create(path)
+}
+
+func (fs *HdfsFileSystem) Rename(from, to *FileLocation) error {
+	client, fromPath, err := newHdfsClient(from)
+	if err != nil {
+		return err
+	}
+	_, toPath, err := splitLocationToParts(to.Location)
+	if err != nil {
+		return err
+	}
+	return client.Rename(fromPath, toPath)
+}
+
+func (fs *HdfsFileSystem) Delete(fl *FileLocation) error {
+	client, path, err := newHdfsClient(fl)
+	if err != nil {
+		return err
+	}
+	return client.Remove(path)
+}
+
+func newHdfsClient(fl *FileLocation) (client *hdfs.Client, path string, err error) {
+	namenode, path, err := splitLocationToParts(fl.Location)
+	if err != nil {
+		return nil, "", err
+	}
+	if namenode == "" {
+		namenode = os.Getenv("HADOOP_NAMENODE")
+	}
+
+	client, err = hdfs.New(namenode)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create client to %s:%v", namenode, err)
+	}
+	return client, path, nil
+}
+
 func splitLocationToParts(location string) (namenode, path string, err error) {
 	hdfsPrefix := "hdfs://"
 	if !strings.HasPrefix(location, hdfsPrefix) {
@@ -1,9 +1,11 @@
 package filesystem
 
 import (
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -51,3 +53,26 @@ func (fs *LocalFileSystem) IsDir(fl *FileLocation) bool {
 	}
 	return false
 }
+
+func (fs *LocalFileSystem) Create(fl *FileLocation) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(fl.Location), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(fl.Location)
+}
+
+func (fs *LocalFileSystem) Rename(from, to *FileLocation) error {
+	return os.Rename(from.Location, to.Location)
+}
+
+func (fs *LocalFileSystem) Delete(fl *FileLocation) error {
+	return os.Remove(fl.Location)
+}
+
+func (fs *LocalFileSystem) Stat(fl *FileLocation) (*FileInfo, error) {
+	fi, err := os.Stat(fl.Location)
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{Size: fi.Size(), ModTime: fi.ModTime(), IsDir: fi.IsDir()}, nil
+}
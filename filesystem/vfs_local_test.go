@@ -0,0 +1,60 @@
+package filesystem
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLocalFileSystem(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "vfs")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tempPath, filePath := dir+"/a/b/.data.tmp", dir+"/a/b/data"
+
+	writer, err := Create(tempPath)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", tempPath, err)
+	}
+	io.WriteString(writer, "0123456789")
+	if err = writer.Close(); err != nil {
+		t.Fatalf("Failed to close %s: %v", tempPath, err)
+	}
+	if err = Rename(tempPath, filePath); err != nil {
+		t.Fatalf("Failed to rename %s: %v", tempPath, err)
+	}
+
+	fileInfo, err := Stat(filePath)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", filePath, err)
+	}
+	if fileInfo.Size != 10 || fileInfo.IsDir {
+		t.Errorf("Expect a file of 10 bytes, but got %+v", fileInfo)
+	}
+
+	file, err := Open(filePath)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", filePath, err)
+	}
+	buf := make([]byte, 3)
+	if n, _ := file.ReadAt(buf, 4); string(buf[:n]) != "456" {
+		t.Errorf("Expect ReadAt to read 456, but got %s", buf[:n])
+	}
+	file.Seek(7, io.SeekStart)
+	if rest, _ := ioutil.ReadAll(file); string(rest) != "789" {
+		t.Errorf("Expect reading after Seek to read 789, but got %s", rest)
+	}
+	file.Close()
+
+	if err = Delete(filePath); err != nil {
+		t.Fatalf("Failed to delete %s: %v", filePath, err)
+	}
+	if _, err = Stat(filePath); err == nil {
+		t.Errorf("Expect %s to be deleted", filePath)
+	}
+}
@@ -2,17 +2,36 @@ package filesystem
 
 import (
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
+// The options can also be set by the environment variables of the upper case names,
+// e.g., AWS_REGION, since the executors do not share the options set in the driver.
+// Without the access key, the default AWS credentials are used.
+// Set the endpoint and the path style for S3 compatible stores, e.g., MinIO.
 const (
-	AWS_ACCESS_KEY = OptionName("aws_access_key")
-	AWS_SECRET_KEY = OptionName("aws_secret_key")
+	AWS_ACCESS_KEY          = OptionName("aws_access_key")
+	AWS_SECRET_KEY          = OptionName("aws_secret_key")
+	AWS_REGION              = OptionName("aws_region")
+	AWS_ENDPOINT            = OptionName("aws_endpoint")
+	AWS_S3_FORCE_PATH_STYLE = OptionName("aws_s3_force_path_style")
+	AWS_DISABLE_SSL         = OptionName("aws_disable_ssl")
+)
+
+var (
+	s3Sessions     = make(map[string]*session.Session)
+	s3SessionsLock sync.Mutex
 )
 
 type S3FileSystem struct {
@@ -22,50 +41,340 @@ func (fs *S3FileSystem) Accept(fl *FileLocation) bool {
 	return strings.HasPrefix(fl.Location, "s3://")
 }
 
+// Open reads the object lazily, with a ranged request from the current position.
 func (fs *S3FileSystem) Open(fl *FileLocation) (VirtualFile, error) {
-	sess, err := session.NewSession(aws.NewConfig().WithCredentials(
-		credentials.NewStaticCredentials(Option[AWS_ACCESS_KEY], Option[AWS_SECRET_KEY], ""),
-	))
+	svc, bucketName, objectKey, err := newS3ClientFor(fl)
 	if err != nil {
-		fmt.Println("failed to create session,", err)
 		return nil, err
 	}
 
-	svc := s3.New(sess)
+	resp, err := svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucketName), // Required
+		Key:    aws.String(objectKey),  // Required
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	bucketName, objectKey, err := splitS3LocationToParts(fl.Location)
+	return &s3File{
+		svc:    svc,
+		bucket: bucketName,
+		key:    objectKey,
+		size:   aws.Int64Value(resp.ContentLength),
+	}, nil
+}
 
+// List lists the objects and the sub directories directly under the location,
+// which is treated as a directory, i.e., a key prefix ending with "/".
+func (fs *S3FileSystem) List(fl *FileLocation) (fileLocations []*FileLocation, err error) {
+	svc, bucketName, prefix, err := newS3ClientFor(fl)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to split S3 location to parts %s: %v", fl.Location, err)
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	location := func(key string) *FileLocation {
+		return &FileLocation{"s3://" + bucketName + "/" + strings.TrimSuffix(key, "/")}
 	}
 
-	params := &s3.GetObjectInput{
-		Bucket: aws.String(bucketName), // Required
-		Key:    aws.String(objectKey),  // Required
+	err = svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, commonPrefix := range page.CommonPrefixes {
+			fileLocations = append(fileLocations, location(aws.StringValue(commonPrefix.Prefix)))
+		}
+		for _, object := range page.Contents {
+			// skip the directory marker
+			if key := aws.StringValue(object.Key); key != prefix {
+				fileLocations = append(fileLocations, location(key))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list %s: %v", fl.Location, err)
 	}
-	resp, err := svc.GetObject(params)
+	return fileLocations, nil
+}
 
+// IsDir returns true for a bucket, or a key prefix of other objects.
+func (fs *S3FileSystem) IsDir(fl *FileLocation) bool {
+	svc, bucketName, objectKey, err := newS3ClientFor(fl)
+	if err != nil {
+		return false
+	}
+	if objectKey == "" || strings.HasSuffix(objectKey, "/") {
+		return true
+	}
+	if _, err = svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	}); err == nil {
+		return false
+	}
+	resp, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucketName),
+		Prefix:  aws.String(objectKey + "/"),
+		MaxKeys: aws.Int64(1),
+	})
+	return err == nil && len(resp.Contents) > 0
+}
+
+func (fs *S3FileSystem) Stat(fl *FileLocation) (*FileInfo, error) {
+	svc, bucketName, objectKey, err := newS3ClientFor(fl)
 	if err != nil {
 		return nil, err
 	}
+	resp, err := svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+	fileInfo := &FileInfo{Size: aws.Int64Value(resp.ContentLength)}
+	if resp.LastModified != nil {
+		fileInfo.ModTime = *resp.LastModified
+	}
+	return fileInfo, nil
+}
 
-	return resp.Body, err
+// Create uploads the object in parts while it is being written.
+// The object only appears after the writer is closed.
+func (fs *S3FileSystem) Create(fl *FileLocation) (io.WriteCloser, error) {
+	sess, err := newS3Session()
+	if err != nil {
+		return nil, err
+	}
+	bucketName, objectKey, err := splitS3LocationToParts(fl.Location)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to split S3 location to parts %s: %v", fl.Location, err)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	writer := &s3Writer{PipeWriter: pipeWriter, done: make(chan error, 1)}
+	go func() {
+		_, err := s3manager.NewUploader(sess).Upload(&s3manager.UploadInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(objectKey),
+			Body:   pipeReader,
+		})
+		if err != nil {
+			// fail the pending and later writes if the upload stopped early
+			err = fmt.Errorf("Failed to upload %s: %v", fl.Location, err)
+			pipeReader.CloseWithError(err)
+		}
+		writer.done <- err
+	}()
+	return writer, nil
 }
 
-func (fs *S3FileSystem) List(fl *FileLocation) (fileLocations []*FileLocation, err error) {
-	return nil, fmt.Errorf("S3 Listing is not supported yet.")
+// Rename copies the object and deletes the original one,
+// since S3 can not move objects.
+func (fs *S3FileSystem) Rename(from, to *FileLocation) error {
+	svc, fromBucket, fromKey, err := newS3ClientFor(from)
+	if err != nil {
+		return err
+	}
+	toBucket, toKey, err := splitS3LocationToParts(to.Location)
+	if err != nil {
+		return fmt.Errorf("Failed to split S3 location to parts %s: %v", to.Location, err)
+	}
+	if _, err = svc.CopyObject(&s3.CopyObjectInput{
+		Bucket:     aws.String(toBucket),
+		Key:        aws.String(toKey),
+		CopySource: aws.String(url.PathEscape(fromBucket + "/" + fromKey)),
+	}); err != nil {
+		return fmt.Errorf("Failed to copy %s to %s: %v", from.Location, to.Location, err)
+	}
+	_, err = svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(fromBucket),
+		Key:    aws.String(fromKey),
+	})
+	return err
 }
 
-func (fs *S3FileSystem) IsDir(fl *FileLocation) bool {
-	return false
+func (fs *S3FileSystem) Delete(fl *FileLocation) error {
+	svc, bucketName, objectKey, err := newS3ClientFor(fl)
+	if err != nil {
+		return err
+	}
+	_, err = svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	return err
+}
+
+// newS3Session reuses the session for the same options.
+func newS3Session() (*session.Session, error) {
+	accessKey, secretKey := s3Option(AWS_ACCESS_KEY), s3Option(AWS_SECRET_KEY)
+	region, endpoint := s3Option(AWS_REGION), s3Option(AWS_ENDPOINT)
+	forcePathStyle, disableSSL := s3Option(AWS_S3_FORCE_PATH_STYLE) == "true", s3Option(AWS_DISABLE_SSL) == "true"
+
+	sessionKey := strings.Join([]string{accessKey, secretKey, region, endpoint,
+		strconv.FormatBool(forcePathStyle), strconv.FormatBool(disableSSL)}, "\x00")
+	s3SessionsLock.Lock()
+	defer s3SessionsLock.Unlock()
+	if sess, found := s3Sessions[sessionKey]; found {
+		return sess, nil
+	}
+
+	config := aws.NewConfig()
+	if accessKey != "" {
+		config = config.WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, ""))
+	}
+	if region != "" {
+		config = config.WithRegion(region)
+	}
+	if endpoint != "" {
+		config = config.WithEndpoint(endpoint)
+	}
+	if forcePathStyle {
+		config = config.WithS3ForcePathStyle(true)
+	}
+	if disableSSL {
+		config = config.WithDisableSSL(true)
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %v", err)
+	}
+	s3Sessions[sessionKey] = sess
+	return sess, nil
+}
+
+func s3Option(name OptionName) string {
+	if value, found := Option[name]; found {
+		return value
+	}
+	return os.Getenv(strings.ToUpper(string(name)))
+}
+
+func newS3Client() (*s3.S3, error) {
+	sess, err := newS3Session()
+	if err != nil {
+		return nil, err
+	}
+	return s3.New(sess), nil
+}
+
+func newS3ClientFor(fl *FileLocation) (svc *s3.S3, bucketName, objectKey string, err error) {
+	bucketName, objectKey, err = splitS3LocationToParts(fl.Location)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("Failed to split S3 location to parts %s: %v", fl.Location, err)
+	}
+	svc, err = newS3Client()
+	return
 }
 
 func splitS3LocationToParts(location string) (bucketName, objectKey string, err error) {
 	s3Prefix := "s3://"
 	if !strings.HasPrefix(location, s3Prefix) {
-		return "", "", fmt.Errorf("parameter %s should start with hdfs://", location)
+		return "", "", fmt.Errorf("parameter %s should start with s3://", location)
 	}
 
 	parts := strings.SplitN(location[len(s3Prefix):], "/", 2)
+	if len(parts) < 2 {
+		return parts[0], "", nil
+	}
 	return parts[0], parts[1], nil
 }
+
+// s3Writer streams the written bytes to the uploader.
+type s3Writer struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (w *s3Writer) Close() error {
+	w.PipeWriter.Close()
+	return <-w.done
+}
+
+// s3File reads the object sequentially from one ranged response body,
+// and sends new ranged requests after seeking or for ReadAt.
+type s3File struct {
+	svc    *s3.S3
+	bucket string
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (f *s3File) Read(p []byte) (n int, err error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		if f.body, err = f.getRange(f.offset, f.size-1); err != nil {
+			return 0, err
+		}
+	}
+	n, err = f.body.Read(p)
+	f.offset += int64(n)
+	return
+}
+
+func (f *s3File) ReadAt(p []byte, off int64) (n int, err error) {
+	if off >= f.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p))
+	if end > f.size {
+		end = f.size
+	}
+	body, err := f.getRange(off, end-1)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	n, err = io.ReadFull(body, p[:end-off])
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return
+}
+
+func (f *s3File) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	}
+	if offset < 0 {
+		return f.offset, fmt.Errorf("Seek to negative position %d in s3://%s/%s", offset, f.bucket, f.key)
+	}
+	if offset != f.offset && f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *s3File) Close() error {
+	if f.body == nil {
+		return nil
+	}
+	err := f.body.Close()
+	f.body = nil
+	return err
+}
+
+func (f *s3File) getRange(start, end int64) (io.ReadCloser, error) {
+	resp, err := f.svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
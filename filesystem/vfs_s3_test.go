@@ -0,0 +1,294 @@
+package filesystem
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// s3Stub serves the objects of one bucket with the S3 REST API,
+// with at most 2 keys or prefixes in a page of the listing.
+type s3Stub struct {
+	sync.Mutex
+	bucket   string
+	objects  map[string]string
+	requests []string
+}
+
+const s3StubPageSize = 2
+
+type s3StubListResult struct {
+	XMLName               xml.Name         `xml:"ListBucketResult"`
+	Name                  string           `xml:"Name"`
+	Prefix                string           `xml:"Prefix"`
+	KeyCount              int              `xml:"KeyCount"`
+	IsTruncated           bool             `xml:"IsTruncated"`
+	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+	Contents              []s3StubObject   `xml:"Contents"`
+	CommonPrefixes        []s3StubPrefixes `xml:"CommonPrefixes"`
+}
+
+type s3StubObject struct {
+	Key  string `xml:"Key"`
+	Size int    `xml:"Size"`
+}
+
+type s3StubPrefixes struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	s.requests = append(s.requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+r.Header.Get("Range")))
+	s.Unlock()
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if parts[0] != s.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if len(parts) == 1 || parts[1] == "" {
+		s.list(w, r)
+		return
+	}
+	content, found := s.objects[parts[1]]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Last-Modified", time.Unix(0, 0).UTC().Format(http.TimeFormat))
+	start, end := 0, len(content)-1
+	if byteRange := r.Header.Get("Range"); byteRange != "" {
+		fmt.Sscanf(byteRange, "bytes=%d-%d", &start, &end)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(content)))
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+	if r.Method == http.MethodHead {
+		return
+	}
+	if r.Header.Get("Range") != "" {
+		w.WriteHeader(http.StatusPartialContent)
+	}
+	io.WriteString(w, content[start:end+1])
+}
+
+func (s *s3Stub) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+
+	var names []string
+	isPrefix := make(map[string]bool)
+	for key := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			commonPrefix := key[:len(prefix)+i+1]
+			if !isPrefix[commonPrefix] {
+				isPrefix[commonPrefix] = true
+				names = append(names, commonPrefix)
+			}
+			continue
+		}
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	start, _ := strconv.Atoi(query.Get("continuation-token"))
+	end := start + s3StubPageSize
+	if maxKeys, err := strconv.Atoi(query.Get("max-keys")); err == nil && start+maxKeys < end {
+		end = start + maxKeys
+	}
+	if end > len(names) {
+		end = len(names)
+	}
+
+	result := s3StubListResult{Name: s.bucket, Prefix: prefix, KeyCount: end - start}
+	if end < len(names) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	}
+	for _, name := range names[start:end] {
+		if isPrefix[name] {
+			result.CommonPrefixes = append(result.CommonPrefixes, s3StubPrefixes{name})
+		} else {
+			result.Contents = append(result.Contents, s3StubObject{name, len(s.objects[name])})
+		}
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+func (s *s3Stub) getRequests() []string {
+	s.Lock()
+	defer s.Unlock()
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+var s3StubOptions = []OptionName{AWS_ACCESS_KEY, AWS_SECRET_KEY, AWS_REGION,
+	AWS_ENDPOINT, AWS_S3_FORCE_PATH_STYLE, AWS_DISABLE_SSL}
+
+// startS3Stub serves the objects, and points the S3 options to the stub.
+func startS3Stub(objects map[string]string) (*s3Stub, func()) {
+	stub := &s3Stub{bucket: "bucket", objects: objects}
+	server := httptest.NewServer(stub)
+	Set(AWS_ACCESS_KEY, "key")
+	Set(AWS_SECRET_KEY, "secret")
+	Set(AWS_REGION, "us-east-1")
+	Set(AWS_ENDPOINT, server.URL)
+	Set(AWS_S3_FORCE_PATH_STYLE, "true")
+	Set(AWS_DISABLE_SSL, "true")
+	return stub, func() {
+		server.Close()
+		for _, name := range s3StubOptions {
+			delete(Option, name)
+		}
+	}
+}
+
+func TestS3List(t *testing.T) {
+	_, stop := startS3Stub(map[string]string{
+		"logs/":          "",
+		"logs/a.csv":     "a",
+		"logs/b.csv":     "b",
+		"logs/c.csv":     "c",
+		"logs/d/e.csv":   "e",
+		"logs/f/g/h.csv": "h",
+		"other.csv":      "o",
+	})
+	defer stop()
+
+	for _, location := range []string{"s3://bucket/logs", "s3://bucket/logs/"} {
+		fileLocations, err := List(location)
+		if err != nil {
+			t.Fatalf("Failed to list %s: %v", location, err)
+		}
+		var actual []string
+		for _, fl := range fileLocations {
+			actual = append(actual, fl.Location)
+		}
+		sort.Strings(actual)
+		expected := []string{"s3://bucket/logs/a.csv", "s3://bucket/logs/b.csv",
+			"s3://bucket/logs/c.csv", "s3://bucket/logs/d", "s3://bucket/logs/f"}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expect %s to list %v in pages, but got %v", location, expected, actual)
+		}
+	}
+}
+
+func TestS3IsDir(t *testing.T) {
+	_, stop := startS3Stub(map[string]string{
+		"logs/a.csv":   "a",
+		"logs/d/e.csv": "e",
+	})
+	defer stop()
+
+	tests := []struct {
+		location string
+		isDir    bool
+	}{
+		{"s3://bucket", true},
+		{"s3://bucket/", true},
+		{"s3://bucket/logs", true},
+		{"s3://bucket/logs/d", true},
+		{"s3://bucket/logs/a.csv", false},
+		{"s3://bucket/logs/missing", false},
+		{"s3://bucket/log", false},
+	}
+	for _, test := range tests {
+		if isDir := IsDir(test.location); isDir != test.isDir {
+			t.Errorf("Expect IsDir(%s) to be %v, but got %v", test.location, test.isDir, isDir)
+		}
+	}
+}
+
+func TestS3ReadAt(t *testing.T) {
+	stub, stop := startS3Stub(map[string]string{
+		"data.txt": "0123456789",
+	})
+	defer stop()
+
+	file, err := Open("s3://bucket/data.txt")
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	defer file.Close()
+	stub.getRequests()
+
+	buf := make([]byte, 3)
+	if n, err := file.ReadAt(buf, 4); n != 3 || err != nil || string(buf) != "456" {
+		t.Errorf("Expect ReadAt to read 456, but got %q: %v", buf[:n], err)
+	}
+	if n, err := file.ReadAt(buf, 8); n != 2 || err != io.EOF || string(buf[:n]) != "89" {
+		t.Errorf("Expect ReadAt at the end to read 89 and EOF, but got %q: %v", buf[:n], err)
+	}
+	if n, err := file.ReadAt(buf, 10); n != 0 || err != io.EOF {
+		t.Errorf("Expect ReadAt after the end to return EOF, but got %d: %v", n, err)
+	}
+	expected := []string{"GET /bucket/data.txt bytes=4-6", "GET /bucket/data.txt bytes=8-9"}
+	if requests := stub.getRequests(); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expect ranged requests %v, but got %v", expected, requests)
+	}
+
+	file.Seek(7, io.SeekStart)
+	if rest, err := ioutil.ReadAll(file); string(rest) != "789" {
+		t.Errorf("Expect reading after Seek to read 789, but got %q: %v", rest, err)
+	}
+	expected = []string{"GET /bucket/data.txt bytes=7-9"}
+	if requests := stub.getRequests(); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expect ranged requests %v, but got %v", expected, requests)
+	}
+}
+
+func TestS3SessionOptions(t *testing.T) {
+	for _, name := range s3StubOptions {
+		envName := strings.ToUpper(string(name))
+		defer os.Setenv(envName, os.Getenv(envName))
+		os.Unsetenv(envName)
+	}
+
+	// the options not set in the driver are read from the environment variables
+	os.Setenv("AWS_ENDPOINT", "http://localhost:9000")
+	os.Setenv("AWS_REGION", "us-east-1")
+	os.Setenv("AWS_S3_FORCE_PATH_STYLE", "true")
+	Set(AWS_REGION, "eu-west-1")
+	defer delete(Option, AWS_REGION)
+
+	sess, err := newS3Session()
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if endpoint := aws.StringValue(sess.Config.Endpoint); endpoint != "http://localhost:9000" {
+		t.Errorf("Expect the endpoint from AWS_ENDPOINT, but got %s", endpoint)
+	}
+	if region := aws.StringValue(sess.Config.Region); region != "eu-west-1" {
+		t.Errorf("Expect the region option to override AWS_REGION, but got %s", region)
+	}
+	if !aws.BoolValue(sess.Config.S3ForcePathStyle) || aws.BoolValue(sess.Config.DisableSSL) {
+		t.Errorf("Expect the path style, and SSL enabled")
+	}
+
+	if same, _ := newS3Session(); same != sess {
+		t.Errorf("Expect the session to be reused for the same options")
+	}
+	os.Setenv("AWS_DISABLE_SSL", "true")
+	other, _ := newS3Session()
+	if other == sess || !aws.BoolValue(other.Config.DisableSSL) {
+		t.Errorf("Expect a new session without SSL after the options changed")
+	}
+}
@@ -26,11 +26,16 @@ func New() (fc *Flow) {
 	return
 }
 
-func (fc *Flow) Run(options ...FlowOption) {
-	fc.RunContext(context.Background(), options...)
+// Run starts the whole flow and waits until it finishes.
+// The error is a *RunError if any task failed.
+func (fc *Flow) Run(options ...FlowOption) (*RunResult, error) {
+	return fc.RunContext(context.Background(), options...)
 }
 
-func (fc *Flow) RunContext(ctx context.Context, options ...FlowOption) {
+// RunContext is the same as Run, but can be cancelled by the context.
+// If there are multiple options, the flow is run by each of them in turn,
+// and it stops at the first failure.
+func (fc *Flow) RunContext(ctx context.Context, options ...FlowOption) (result *RunResult, err error) {
 
 	if !gio.HasInitalized && fc.hasPureGoMapperReducer {
 		println("gio.Init() is required right after main() if pure go mapper or reducer is used.")
@@ -38,12 +43,14 @@ func (fc *Flow) RunContext(ctx context.Context, options ...FlowOption) {
 	}
 
 	if len(options) == 0 {
-		Local.RunFlowContext(ctx, fc)
-	} else {
-		for _, option := range options {
-			option.GetFlowRunner().RunFlowContext(ctx, fc)
+		return Local.RunFlowContext(ctx, fc)
+	}
+	for _, option := range options {
+		if result, err = option.GetFlowRunner().RunFlowContext(ctx, fc); err != nil {
+			return result, err
 		}
 	}
+	return result, nil
 }
 
 func (fc *Flow) newNextDataset(shardSize int) (ret *Dataset) {
@@ -33,13 +33,13 @@ func (d *Dataset) Init(scriptPart string) *Dataset {
 }
 
 // Run starts the whole flow. This is a convenient method, same as *Flow.Run()
-func (d *Dataset) Run(option ...FlowOption) {
-	d.RunContext(context.Background(), option...)
+func (d *Dataset) Run(option ...FlowOption) (*RunResult, error) {
+	return d.RunContext(context.Background(), option...)
 }
 
 // Run starts the whole flow. This is a convenient method, same as *Flow.RunContext()
-func (d *Dataset) RunContext(ctx context.Context, option ...FlowOption) {
-	d.Flow.RunContext(ctx, option...)
+func (d *Dataset) RunContext(ctx context.Context, option ...FlowOption) (*RunResult, error) {
+	return d.Flow.RunContext(ctx, option...)
 }
 
 func (d *Dataset) setupShard(n int) {
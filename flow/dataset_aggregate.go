@@ -0,0 +1,109 @@
+package flow
+
+import (
+	"github.com/chrislusf/gleamold/instruction"
+)
+
+// Aggregator computes one value for each group of rows in Aggregate.
+type Aggregator struct {
+	aggregator instruction.Aggregator
+}
+
+// Count counts the rows in each group.
+func Count() Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateCount}}
+}
+
+// Sum adds up the numbers in field index. The sum is an int64,
+// or a float64 if any of the numbers is a float.
+func Sum(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateSum, Index: index}}
+}
+
+// Min picks the smallest value in field index.
+func Min(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateMin, Index: index}}
+}
+
+// Max picks the largest value in field index.
+func Max(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateMax, Index: index}}
+}
+
+// Avg computes the float64 average of the numbers in field index.
+func Avg(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateAvg, Index: index}}
+}
+
+// CountDistinct counts the unique values in field index.
+func CountDistinct(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateCountDistinct, Index: index}}
+}
+
+// First picks the value in field index of the row with the earliest timestamp.
+func First(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateFirst, Index: index}}
+}
+
+// Last picks the value in field index of the row with the latest timestamp.
+func Last(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateLast, Index: index}}
+}
+
+// CountNotNull counts the rows with a non-nil value in field index.
+func CountNotNull(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateCountNotNull, Index: index}}
+}
+
+// Concat joins the values in field index with commas, like GROUP_CONCAT in SQL.
+func Concat(index int) Aggregator {
+	return Aggregator{instruction.Aggregator{Type: instruction.AggregateConcat, Index: index}}
+}
+
+// Aggregate groups the rows by the key fields, and computes the aggregators
+// for each group in Go, without any Lua or registered reducer.
+// Each result row has the keys followed by one value for each aggregator.
+// Nil values are skipped by all aggregators except Count.
+// The rows are hash partitioned by the key fields first, so the result
+// keeps the same number of shards as the input dataset.
+// If keys is nil, all rows are aggregated into one row in one shard.
+// example usage: Aggregate(Field(1), Count(), Sum(2), Avg(3)).
+func (d *Dataset) Aggregate(keys *SortOption, aggs ...Aggregator) *Dataset {
+	return d.AggregateTo(len(d.Shards), keys, aggs...)
+}
+
+// AggregateTo is the same as Aggregate, but the result has shardCount shards.
+func (d *Dataset) AggregateTo(shardCount int, keys *SortOption, aggs ...Aggregator) *Dataset {
+	if keys == nil {
+		return d.MergeTo(1).LocalAggregate(nil, aggs...)
+	}
+
+	if shardCount == 1 {
+		return d.LocalSort(keys).MergeSortedTo(1, keys).LocalAggregate(keys, aggs...)
+	}
+	return d.Partition(shardCount, keys).LocalSort(keys).LocalAggregate(keys, aggs...)
+}
+
+// LocalAggregate aggregates each group of rows with the same keys within
+// each shard. The rows should already be locally sorted by the keys.
+func (d *Dataset) LocalAggregate(keys *SortOption, aggs ...Aggregator) *Dataset {
+	var indexes []int
+	if keys != nil {
+		indexes = keys.Indexes()
+	}
+	var aggregators []instruction.Aggregator
+	for _, agg := range aggs {
+		aggregators = append(aggregators, agg.aggregator)
+	}
+
+	ret, step := add1ShardTo1Step(d)
+	if keys != nil {
+		keyFields := keys.leadingFields()
+		ret.IsLocalSorted = keyFields.orderByList
+		if intArrayEquals(d.IsPartitionedBy, indexes) {
+			ret.IsPartitionedBy = keyFields.Indexes()
+		}
+	}
+	step.SetInstruction(instruction.NewLocalAggregate(indexes, aggregators))
+	return ret
+}
@@ -1,12 +1,24 @@
 package flow
 
 // GroupBy e.g. GroupBy(Field(1,2,3)) group data by field 1,2,3
+// The rows are hash partitioned by the key fields first, so the result
+// keeps the same number of shards as the input dataset.
 func (d *Dataset) GroupBy(sortOptions ...*SortOption) *Dataset {
+	return d.GroupByTo(len(d.Shards), sortOptions...)
+}
+
+// GroupByTo is the same as GroupBy, but the result has shardCount shards.
+func (d *Dataset) GroupByTo(shardCount int, sortOptions ...*SortOption) *Dataset {
 	sortOption := concat(sortOptions)
 
-	ret := d.LocalSort(sortOption).LocalGroupBy(sortOption)
-	if len(d.Shards) > 1 {
-		ret = ret.MergeSortedTo(1, sortOption).LocalGroupBy(sortOption)
+	var ret *Dataset
+	if shardCount == 1 {
+		ret = d.LocalSort(sortOption).LocalGroupBy(sortOption)
+		if len(d.Shards) > 1 {
+			ret = ret.MergeSortedTo(1, sortOption).LocalGroupBy(sortOption)
+		}
+	} else {
+		ret = d.Partition(shardCount, sortOption).LocalSort(sortOption).LocalGroupBy(sortOption)
 	}
 	ret.IsLocalSorted = sortOption.orderByList
 	return ret
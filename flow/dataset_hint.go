@@ -26,6 +26,14 @@ func PartitionSize(n int64) DasetsetHint {
 	}
 }
 
+// NoCombiner hints that the reducer is not associative. ReduceBy and ReducerBy
+// then shuffle the rows as they are, without partially reducing them first.
+func NoCombiner() DasetsetHint {
+	return func(d *Dataset) {
+		d.Meta.NoCombiner = true
+	}
+}
+
 // OnDisk ensure the intermediate dataset are persisted to disk.
 // This allows executors to run not in parallel if executors are limited.
 func (d *Dataset) OnDisk(fn func(*Dataset) *Dataset) *Dataset {
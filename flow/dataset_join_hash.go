@@ -29,6 +29,10 @@ func (d *Dataset) Broadcast(shardCount int) *Dataset {
 	if shardCount == 1 && len(d.Shards) == shardCount {
 		return d
 	}
+	// only one shard is copied to all shards
+	if len(d.Shards) > 1 {
+		d = d.MergeTo(1)
+	}
 	ret := d.Flow.newNextDataset(shardCount)
 	step := d.Flow.AddOneToAllStep(d, ret)
 	step.SetInstruction(instruction.NewBroadcast())
@@ -0,0 +1,88 @@
+package flow
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chrislusf/gleamold/util"
+)
+
+func TestPartitionToOneShard(t *testing.T) {
+	f := New()
+	d := f.Slices([][]interface{}{{1}, {2}, {3}}).RoundRobin(3).Partition(1, Field(1))
+	if len(d.Shards) != 1 {
+		t.Errorf("Expect 1 shard, but got %d", len(d.Shards))
+	}
+}
+
+func TestJoinWithMoreShardsOnTheRight(t *testing.T) {
+	f := New()
+	left := f.Slices([][]interface{}{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}})
+	right := f.Slices([][]interface{}{{1, "x"}, {2, "y"}, {3, "z"}, {5, "w"}}).RoundRobin(3)
+
+	var lock sync.Mutex
+	var rows []string
+	left.LeftOuterJoin(right).Output(func(reader io.Reader) error {
+		for {
+			_, row, err := util.ReadRow(reader)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			var other []byte
+			if row[2] != nil {
+				other = row[2].([]byte)
+			}
+			lock.Lock()
+			rows = append(rows, fmt.Sprintf("%v %s %s", row[0], row[1], other))
+			lock.Unlock()
+		}
+	})
+	if _, err := f.Run(); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+
+	sort.Strings(rows)
+	expected := "1 a x,2 b y,3 c z,4 d "
+	if strings.Join(rows, ",") != expected {
+		t.Errorf("Expect %s, but got %v", expected, rows)
+	}
+}
+
+func TestHashJoinWithMoreShardsOnTheSmallerSide(t *testing.T) {
+	f := New()
+	bigger := f.Slices([][]interface{}{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}}).RoundRobin(2)
+	smaller := f.Slices([][]interface{}{{1, "x"}, {2, "y"}, {3, "z"}}).RoundRobin(3)
+
+	var lock sync.Mutex
+	var rows []string
+	bigger.HashJoin(smaller).Output(func(reader io.Reader) error {
+		for {
+			_, row, err := util.ReadRow(reader)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			lock.Lock()
+			rows = append(rows, fmt.Sprintf("%v %s %s", row[0], row[1], row[2]))
+			lock.Unlock()
+		}
+	})
+	if _, err := f.Run(); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+
+	sort.Strings(rows)
+	expected := "1 a x,2 b y,3 c z"
+	if strings.Join(rows, ",") != expected {
+		t.Errorf("Expect %s, but got %v", expected, rows)
+	}
+}
@@ -21,6 +21,16 @@ func (d *Dataset) Map(code string) *Dataset {
 // Mapper runs the mapper registered to the mapperId.
 // This is used to execute pure Go code.
 func (d *Dataset) Mapper(mapperId gio.MapperId) *Dataset {
+	return d.runMapper(mapperId, nil)
+}
+
+// MapperWithArgs runs the mapper registered to the mapperId by gio.RegisterMapperFactory(),
+// passing the args to the mapper factory on the executors, e.g., the encoded configuration of a sink.
+func (d *Dataset) MapperWithArgs(mapperId gio.MapperId, args []byte) *Dataset {
+	return d.runMapper(mapperId, args)
+}
+
+func (d *Dataset) runMapper(mapperId gio.MapperId, mapperArgs []byte) *Dataset {
 	d.Flow.hasPureGoMapperReducer = true
 
 	ret, step := add1ShardTo1Step(d)
@@ -31,6 +41,9 @@ func (d *Dataset) Mapper(mapperId gio.MapperId) *Dataset {
 	args = append(args, "./"+filepath.Base(os.Args[0]))
 	// args = append(args, os.Args[1:]...) // empty string in an arg can fail the execution
 	args = append(args, "-gleamold.mapper="+string(mapperId))
+	if mapperArgs != nil {
+		args = append(args, "-gleamold.mapperArgs="+gio.EncodeMapperArgs(mapperArgs))
+	}
 	commandLine := strings.Join(args, " ")
 	// println("args:", commandLine)
 	step.Command = script.NewShellScript().Pipe(commandLine).GetCommand()
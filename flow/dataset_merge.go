@@ -49,3 +49,22 @@ func (d *Dataset) MergeTo(partitionCount int) (ret *Dataset) {
 	step.SetInstruction(instruction.NewMergeTo())
 	return ret
 }
+
+// Union merges the rows of all the datasets, into the same number of shards as this dataset.
+func (d *Dataset) Union(others ...*Dataset) *Dataset {
+	if len(others) == 0 {
+		return d
+	}
+	shardCount := len(d.Shards)
+	inputs := []*Dataset{d}
+	for _, other := range others {
+		if len(other.Shards) != shardCount {
+			other = other.MergeTo(1).RoundRobin(shardCount)
+		}
+		inputs = append(inputs, other)
+	}
+	ret := d.Flow.newNextDataset(shardCount)
+	step := d.Flow.MergeDatasets1ShardTo1Step(inputs, ret)
+	step.SetInstruction(instruction.NewMergeTo())
+	return ret
+}
@@ -22,14 +22,11 @@ func (d *Dataset) Partition(shard int, sortOptions ...*SortOption) *Dataset {
 	sortOption := concat(sortOptions)
 
 	indexes := sortOption.Indexes()
-	if intArrayEquals(d.IsPartitionedBy, indexes) && shard == len(d.Shards) {
-		return d
-	}
-	if 1 == len(d.Shards) && shard == 1 {
+	if d.isPartitionedTo(shard, indexes) {
 		return d
 	}
 	ret := d.partition_scatter(shard, indexes)
-	if shard > 1 {
+	if len(d.Shards) > 1 {
 		ret = ret.partition_collect(shard, indexes)
 	}
 	ret.IsPartitionedBy = indexes
@@ -52,6 +49,66 @@ func (d *Dataset) partition_collect(shardCount int, indexes []int) (ret *Dataset
 	return
 }
 
+// RangePartitionSampleSize is the number of rows sampled from each input
+// shard to find the split points for RangePartition.
+var RangePartitionSampleSize = 1000
+
+// RangePartition sends rows into shardCount ordered partitions, so that
+// all rows in partition i sort before the rows in partition i+1.
+// This is divided into 4 steps:
+// 1. Each input shard samples its sort keys
+// 2. One task sorts all the samples and picks the split points
+// 3. Each record is sent to the local shard covering its key range
+// 4. The destination shard will collect its child shards and merge into one
+// The input dataset is read twice, by the sampling and by the scattering.
+func (d *Dataset) RangePartition(shardCount int, sortOptions ...*SortOption) *Dataset {
+	sortOption := concat(sortOptions)
+
+	if shardCount == 1 {
+		return d.MergeTo(1)
+	}
+	splitPoints := d.LocalSample(RangePartitionSampleSize, sortOption).
+		rangeSplitPoints(shardCount, sortOption.leadingFields()).
+		Broadcast(len(d.Shards))
+	return d.range_scatter(splitPoints, shardCount, sortOption).partition_collect(shardCount, nil)
+}
+
+// LocalSample randomly picks n rows from each shard, keeping only the sort keys.
+func (d *Dataset) LocalSample(n int, sortOptions ...*SortOption) *Dataset {
+	sortOption := concat(sortOptions)
+
+	ret, step := add1ShardTo1Step(d)
+	step.SetInstruction(instruction.NewLocalSample(n, sortOption.orderByList))
+	return ret
+}
+
+func (d *Dataset) rangeSplitPoints(shardCount int, sortOption *SortOption) (ret *Dataset) {
+	ret = d.Flow.newNextDataset(1)
+	step := d.Flow.AddAllToOneStep(d, ret)
+	step.SetInstruction(instruction.NewRangeSplitPoints(shardCount, sortOption.orderByList))
+	return
+}
+
+func (d *Dataset) range_scatter(splitPoints *Dataset, shardCount int, sortOption *SortOption) (ret *Dataset) {
+	ret = d.Flow.newNextDataset(len(d.Shards) * shardCount)
+	step := d.Flow.AddOneToEveryNStep(d, shardCount, ret)
+	fromDatasetToStep(splitPoints, step)
+	for i, task := range step.Tasks {
+		fromDatasetShardToTask(splitPoints.Shards[i], task)
+	}
+	step.SetInstruction(instruction.NewScatterRanges(sortOption.orderByList))
+	return
+}
+
+// isPartitionedTo checks whether the dataset is already hash partitioned
+// by the indexes into shardCount shards, so no shuffling is needed.
+func (d *Dataset) isPartitionedTo(shardCount int, indexes []int) bool {
+	if shardCount != len(d.Shards) {
+		return false
+	}
+	return shardCount == 1 || intArrayEquals(d.IsPartitionedBy, indexes)
+}
+
 func intArrayEquals(a []int, b []int) bool {
 	if len(a) != len(b) {
 		return false
@@ -30,26 +30,72 @@ func (d *Dataset) LocalReduce(code string) *Dataset {
 	return ret
 }
 
+// MaxCombinerKeys is the most number of keys a map-side combiner
+// holds in memory before flushing the partially reduced rows.
+var MaxCombinerKeys = 100000
+
+// ReduceBy runs the reducer code on rows with the same key fields.
+// The rows are hash partitioned by the key fields first, so the result
+// keeps the same number of shards as the input dataset.
+// The reducer should be associative. Before shuffling, the rows are
+// partially reduced within each shard by a hash based combiner,
+// unless the dataset is hinted with NoCombiner().
 func (d *Dataset) ReduceBy(code string, sortOptions ...*SortOption) (ret *Dataset) {
+	return d.ReduceByTo(len(d.Shards), code, sortOptions...)
+}
+
+// ReduceByTo is the same as ReduceBy, but the result has shardCount shards.
+// ReduceByTo(1, ...) merges all locally reduced shards into one shard.
+func (d *Dataset) ReduceByTo(shardCount int, code string, sortOptions ...*SortOption) (ret *Dataset) {
 	sortOption := concat(sortOptions)
 
-	ret = d.LocalSort(sortOption).LocalReduceBy(code, sortOption)
-	if len(d.Shards) > 1 {
-		ret = ret.MergeSortedTo(1, sortOption).LocalReduceBy(code, sortOption)
+	if shardCount == 1 {
+		ret = d.LocalSort(sortOption).LocalReduceBy(code, sortOption)
+		if len(d.Shards) > 1 {
+			ret = ret.MergeSortedTo(1, sortOption).LocalReduceBy(code, sortOption)
+		}
+		return ret
 	}
-	return ret
+	if d.isPartitionedTo(shardCount, sortOption.Indexes()) {
+		return d.LocalSort(sortOption).LocalReduceBy(code, sortOption)
+	}
+	if d.Meta.NoCombiner {
+		return d.Partition(shardCount, sortOption).LocalSort(sortOption).LocalReduceBy(code, sortOption)
+	}
+	keyFields := sortOption.leadingFields()
+	return d.LocalHashReduceBy(code, sortOption).
+		Partition(shardCount, keyFields).LocalSort(keyFields).LocalReduceBy(code, keyFields)
 }
 
 // ReducerBy runs the reducer registered to the reducerId.
 // This is used to execute pure Go code.
+// Same as ReduceBy, the result keeps the same number of shards,
+// and the reducer is also used as a combiner before shuffling,
+// unless the dataset is hinted with NoCombiner().
 func (d *Dataset) ReducerBy(reducerId gio.ReducerId, sortOptions ...*SortOption) (ret *Dataset) {
+	return d.ReducerByTo(len(d.Shards), reducerId, sortOptions...)
+}
+
+// ReducerByTo is the same as ReducerBy, but the result has shardCount shards.
+func (d *Dataset) ReducerByTo(shardCount int, reducerId gio.ReducerId, sortOptions ...*SortOption) (ret *Dataset) {
 	sortOption := concat(sortOptions)
 
-	ret = d.LocalSort(sortOption).LocalReducerBy(reducerId, sortOption)
-	if len(d.Shards) > 1 {
-		ret = ret.MergeSortedTo(1, sortOption).LocalReducerBy(reducerId, sortOption)
+	if shardCount == 1 {
+		ret = d.LocalSort(sortOption).LocalReducerBy(reducerId, sortOption)
+		if len(d.Shards) > 1 {
+			ret = ret.MergeSortedTo(1, sortOption).LocalReducerBy(reducerId, sortOption)
+		}
+		return ret
 	}
-	return ret
+	if d.isPartitionedTo(shardCount, sortOption.Indexes()) {
+		return d.LocalSort(sortOption).LocalReducerBy(reducerId, sortOption)
+	}
+	if d.Meta.NoCombiner {
+		return d.Partition(shardCount, sortOption).LocalSort(sortOption).LocalReducerBy(reducerId, sortOption)
+	}
+	keyFields := sortOption.leadingFields()
+	return d.LocalHashReducerBy(reducerId, sortOption).
+		Partition(shardCount, keyFields).LocalSort(keyFields).LocalReducerBy(reducerId, keyFields)
 }
 
 func (d *Dataset) LocalReduceBy(code string, sortOptions ...*SortOption) *Dataset {
@@ -63,6 +109,19 @@ func (d *Dataset) LocalReduceBy(code string, sortOptions ...*SortOption) *Datase
 	return ret
 }
 
+// LocalHashReduceBy partially reduces rows with the same key fields
+// within each shard, without sorting. The key fields are moved to the
+// front of each row, and the same keys may still appear multiple times.
+func (d *Dataset) LocalHashReduceBy(code string, sortOptions ...*SortOption) *Dataset {
+	sortOption := concat(sortOptions)
+
+	ret, step := add1ShardTo1Step(d)
+	step.Name = "LocalHashReduceBy"
+	step.Script = d.Flow.createScript()
+	step.Script.HashReduceBy(code, sortOption.Indexes(), MaxCombinerKeys)
+	return ret
+}
+
 func (d *Dataset) LocalReducerBy(reducerId gio.ReducerId, sortOptions ...*SortOption) *Dataset {
 	d.Flow.hasPureGoMapperReducer = true
 
@@ -72,10 +131,32 @@ func (d *Dataset) LocalReducerBy(reducerId gio.ReducerId, sortOptions ...*SortOp
 	step.Name = "LocalReducerBy"
 	step.IsPipe = false
 	step.IsGoCode = true
+	step.Command = reducerCommand(reducerId, sortOption.Indexes())
 
+	return ret
+}
+
+// LocalHashReducerBy is the same as LocalHashReduceBy, but runs the
+// reducer registered to the reducerId.
+func (d *Dataset) LocalHashReducerBy(reducerId gio.ReducerId, sortOptions ...*SortOption) *Dataset {
+	d.Flow.hasPureGoMapperReducer = true
+
+	sortOption := concat(sortOptions)
+
+	ret, step := add1ShardTo1Step(d)
+	step.Name = "LocalHashReducerBy"
+	step.IsPipe = false
+	step.IsGoCode = true
+	step.Command = reducerCommand(reducerId, sortOption.Indexes(),
+		"-gleamold.combinerKeys="+strconv.Itoa(MaxCombinerKeys))
+
+	return ret
+}
+
+func reducerCommand(reducerId gio.ReducerId, indexes []int, extraArgs ...string) *script.Command {
 	// add key indexes for reducer command line option
 	keyPositions := []string{}
-	for _, keyPosition := range sortOption.Indexes() {
+	for _, keyPosition := range indexes {
 		keyPositions = append(keyPositions, strconv.Itoa(keyPosition))
 	}
 
@@ -84,9 +165,8 @@ func (d *Dataset) LocalReducerBy(reducerId gio.ReducerId, sortOptions ...*SortOp
 	args = append(args, os.Args[1:]...)
 	args = append(args, "-gleamold.reducer="+string(reducerId))
 	args = append(args, "-gleamold.keyFields="+strings.Join(keyPositions, ","))
+	args = append(args, extraArgs...)
 	commandLine := strings.Join(args, " ")
 
-	step.Command = script.NewShellScript().Pipe(commandLine).GetCommand()
-
-	return ret
+	return script.NewShellScript().Pipe(commandLine).GetCommand()
 }
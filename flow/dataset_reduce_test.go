@@ -0,0 +1,30 @@
+package flow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReduceByCombiner(t *testing.T) {
+	tests := []struct {
+		hints    []DasetsetHint
+		expected string
+	}{
+		{nil, "LocalHashReduceBy ScatterPartitions CollectPartitions LocalSort LocalReduceBy"},
+		{[]DasetsetHint{NoCombiner()}, "ScatterPartitions CollectPartitions LocalSort LocalReduceBy"},
+	}
+	for _, test := range tests {
+		f := New()
+		f.Slices([][]interface{}{{"a", 1}, {"b", 2}, {"a", 3}}).RoundRobin(2).
+			Hint(test.hints...).
+			ReduceBy(`function(x, y) return x + y end`)
+
+		var steps []string
+		for _, step := range f.Steps[2:] {
+			steps = append(steps, step.Name)
+		}
+		if strings.Join(steps, " ") != test.expected {
+			t.Errorf("Expect steps %s, but got %v", test.expected, steps)
+		}
+	}
+}
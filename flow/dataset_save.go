@@ -0,0 +1,61 @@
+package flow
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/chrislusf/gleamold/gio"
+	"github.com/chrislusf/gleamold/instruction"
+)
+
+// Sinker writes a dataset out, the reverse of Sourcer.
+// A sinker usually writes each shard on the executors,
+// via a gio.ShardWriter added by Dataset.WriteShards().
+type Sinker interface {
+	Save(*Dataset) *Dataset
+}
+
+// Write writes the dataset via the sinker, and returns the dataset.
+func (d *Dataset) Write(s Sinker) *Dataset {
+	return s.Save(d)
+}
+
+// WriteShards writes each shard on the executors by the gio.ShardWriter
+// registered to the writerId by gio.RegisterShardWriter(), and opened with the args.
+// The writer of each shard is committed after all its rows are written, or aborted if any fails.
+func (d *Dataset) WriteShards(writerId gio.MapperId, args []byte) *Dataset {
+	ret := d.MapperWithArgs(writerId, args)
+	ret.Step.Name = "WriteShards"
+	// the writers emit nothing, but the flow only runs the steps leading to an output
+	ret.Output(func(reader io.Reader) error {
+		_, err := io.Copy(ioutil.Discard, reader)
+		return err
+	})
+	return d
+}
+
+// SaveTextFile writes each shard into one file as tab-separated lines.
+// The files are written by the executors, not collected to the driver.
+// The pathPattern is formatted with the shard id, e.g., "/data/out/part-%05d.txt",
+// or it is a directory for files named as part-00000, part-00001, etc.
+// The path can be on any writable file system, e.g., local files or hdfs://.
+// Each file is written to a temporary name, and renamed when completed.
+func (d *Dataset) SaveTextFile(pathPattern string) *Dataset {
+	return d.SaveAs("tsv", pathPattern)
+}
+
+// SaveAs is the same as SaveTextFile, but writes in the format, "tsv", "csv", or "jsonl".
+// The files are compressed by the file extension, e.g., ".gz", ".bz2", ".zst", or ".sz".
+func (d *Dataset) SaveAs(format, pathPattern string) *Dataset {
+	return d.SaveCompressedAs(format, "", pathPattern)
+}
+
+// SaveCompressedAs is the same as SaveAs, but compresses the files in the compression format,
+// "gzip", "bzip2", "zstd", or "snappy". If the pathPattern is a directory,
+// the files are named with the extension of the compression format, e.g., part-00000.gz.
+// Writing "bzip2" runs the bzip2 command, which should be installed on the agents.
+func (d *Dataset) SaveCompressedAs(format, compression, pathPattern string) *Dataset {
+	step := d.Flow.AddOneToOneStep(d, nil)
+	step.SetInstruction(instruction.NewSaveFile(format, compression, pathPattern, nil, d.Step.IsPipe))
+	return d
+}
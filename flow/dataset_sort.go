@@ -15,18 +15,28 @@ type pair struct {
 }
 
 // Distinct sort on specific fields and pick the unique ones.
+// The rows are hash partitioned by the fields first, so the result
+// keeps the same number of shards as the input dataset.
 // Required Memory: about same size as each partition.
 // example usage: Distinct(Field(1,2)) means
 // distinct on field 1 and 2.
 // TODO: optimize for low cardinality case.
 func (d *Dataset) Distinct(sortOptions ...*SortOption) *Dataset {
+	return d.DistinctTo(len(d.Shards), sortOptions...)
+}
+
+// DistinctTo is the same as Distinct, but the result has shardCount shards.
+func (d *Dataset) DistinctTo(shardCount int, sortOptions ...*SortOption) *Dataset {
 	sortOption := concat(sortOptions)
 
-	ret := d.LocalSort(sortOption).LocalDistinct(sortOption)
-	if len(d.Shards) > 1 {
-		ret = ret.MergeSortedTo(1, sortOption).LocalDistinct(sortOption)
+	if shardCount == 1 {
+		ret := d.LocalSort(sortOption).LocalDistinct(sortOption)
+		if len(d.Shards) > 1 {
+			ret = ret.MergeSortedTo(1, sortOption).LocalDistinct(sortOption)
+		}
+		return ret
 	}
-	return ret
+	return d.Partition(shardCount, sortOption).LocalSort(sortOption).LocalDistinct(sortOption)
 }
 
 // Sort sort on specific fields, default to the first field.
@@ -41,14 +51,28 @@ func (d *Dataset) Sort(sortOptions ...*SortOption) *Dataset {
 	return ret
 }
 
+// SortTo is the same as Sort, but the result has shardCount shards.
+// The rows are range partitioned, so each shard is locally sorted,
+// and every row in shard i sorts before the rows in shard i+1.
+// Required Memory: about same size as each partition.
+func (d *Dataset) SortTo(shardCount int, sortOptions ...*SortOption) *Dataset {
+	sortOption := concat(sortOptions)
+
+	if shardCount == 1 {
+		return d.Sort(sortOption)
+	}
+	return d.RangePartition(shardCount, sortOption).LocalSort(sortOption)
+}
+
 // Top streams through total n items, picking reverse ordered k items with O(n*log(k)) complexity.
 // Required Memory: about same size as n items in memory
 func (d *Dataset) Top(k int, sortOptions ...*SortOption) *Dataset {
 	sortOption := concat(sortOptions)
 
+	// each shard of the local top items is in the reverse order
 	ret := d.LocalTop(k, sortOption)
 	if len(d.Shards) > 1 {
-		ret = ret.MergeSortedTo(1, sortOption).LocalLimit(k, 0)
+		ret = ret.MergeSortedTo(1, sortOption.reverse()).LocalLimit(k, 0)
 	}
 	return ret
 }
@@ -85,7 +109,7 @@ func (d *Dataset) LocalTop(n int, sortOptions ...*SortOption) *Dataset {
 	}
 
 	ret, step := add1ShardTo1Step(d)
-	ret.IsLocalSorted = sortOption.orderByList
+	ret.IsLocalSorted = sortOption.reverse().orderByList
 	ret.IsPartitionedBy = d.IsPartitionedBy
 	step.SetInstruction(instruction.NewLocalTop(n, sortOption.orderByList))
 	return ret
@@ -37,6 +37,14 @@ func OrderBy(index int, ascending bool) *SortOption {
 
 // OrderBy chains a list of sorting order by
 func (o *SortOption) By(index int, ascending bool) *SortOption {
+	order := instruction.Descending
+	if ascending {
+		order = instruction.Ascending
+	}
+	o.orderByList = append(o.orderByList, instruction.OrderBy{
+		Index: index,
+		Order: order,
+	})
 	return o
 }
 
@@ -49,6 +57,32 @@ func (o *SortOption) Indexes() []int {
 	return ret
 }
 
+// leadingFields keeps the same orders, but on fields 1, 2, 3, ...
+// This is the row layout after the key fields are moved to the front,
+// e.g., by a reducer.
+func (o *SortOption) leadingFields() *SortOption {
+	ret := &SortOption{}
+	for i, x := range o.orderByList {
+		ret.orderByList = append(ret.orderByList, instruction.OrderBy{
+			Index: i + 1,
+			Order: x.Order,
+		})
+	}
+	return ret
+}
+
+// reverse keeps the same fields, but in the opposite orders.
+func (o *SortOption) reverse() *SortOption {
+	ret := &SortOption{}
+	for _, x := range o.orderByList {
+		ret.orderByList = append(ret.orderByList, instruction.OrderBy{
+			Index: x.Index,
+			Order: -x.Order,
+		})
+	}
+	return ret
+}
+
 func concat(sortOptions []*SortOption) *SortOption {
 	if len(sortOptions) == 0 {
 		return Field(1)
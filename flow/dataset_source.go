@@ -8,6 +8,7 @@ import (
 	"net"
 
 	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/chrislusf/gleamold/instruction"
 	"github.com/chrislusf/gleamold/pb"
 	"github.com/chrislusf/gleamold/util"
 )
@@ -93,8 +94,43 @@ func (fc *Flow) Source(f func(io.Writer) error) (ret *Dataset) {
 	return
 }
 
+// DefaultTextFileSplitSize is the usual size of the byte ranges for TextFileSplit().
+const DefaultTextFileSplitSize = 128 * 1024 * 1024
+
+// TextFileSplit reads the file content as lines like TextFile, but splits an uncompressed file
+// larger than splitSize into byte ranges, which are read in parallel by the executors,
+// one shard per range. So the file should be readable by all the executors,
+// e.g., on hdfs:// or s3://, or on a shared file system.
+// The file is not split if splitSize is 0.
+func (fc *Flow) TextFileSplit(fname string, splitSize int64) (ret *Dataset) {
+	if splitSize <= 0 || filesystem.IsCompressed(fname) {
+		return fc.TextFile(fname)
+	}
+	fileInfo, err := filesystem.Stat(fname)
+	if err != nil || fileInfo.Size <= splitSize {
+		return fc.TextFile(fname)
+	}
+
+	splitCount := int((fileInfo.Size + splitSize - 1) / splitSize)
+	splits := fc.Source(func(writer io.Writer) error {
+		for offset := int64(0); offset < fileInfo.Size; offset += splitSize {
+			if err := util.WriteRow(writer, util.Now(), fname, offset, splitSize); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	splits.Step.Name = "TextFileSplits"
+
+	ret, step := add1ShardTo1Step(splits.RoundRobin(splitCount))
+	step.SetInstruction(instruction.NewReadTextSplit())
+	return ret
+}
+
 // TextFile reads the file content as lines and feed into the flow.
 // The file can be a local file or hdfs://namenode:port/path/to/hdfs/file
+// A compressed file is decompressed by the file extension or the magic bytes.
+// The whole file is read on the driver, see TextFileSplit() to read a large file in parallel.
 func (fc *Flow) TextFile(fname string) (ret *Dataset) {
 	fn := func(writer io.Writer) error {
 		w := bufio.NewWriter(writer)
@@ -130,6 +166,8 @@ func (fc *Flow) Channel(ch chan interface{}) (ret *Dataset) {
 	step := fc.AddOneToOneStep(nil, ret)
 	step.IsOnDriverSide = true
 	step.Name = "Channel"
+	// the channel can only be read once
+	step.Meta.IsIdempotent = false
 	step.Function = func(readers []io.Reader, writers []io.Writer, stat *pb.InstructionStat) error {
 		for data := range ch {
 			stat.InputCounter++
@@ -0,0 +1,69 @@
+package flow
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chrislusf/gleamold/util"
+)
+
+func TestTextFileSplit(t *testing.T) {
+	file, err := ioutil.TempFile("", "text-")
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	var expected []string
+	for i := 0; i < 20; i++ {
+		line := fmt.Sprintf("line %02d", i)
+		expected = append(expected, line)
+		fmt.Fprintln(file, line)
+	}
+	file.Close()
+
+	tests := []struct {
+		splitSize int64
+		shards    int
+	}{
+		{0, 1},
+		{1024, 1},
+		{64, 3},
+	}
+	for _, test := range tests {
+		f := New()
+		d := f.TextFileSplit(file.Name(), test.splitSize)
+		if len(d.Shards) != test.shards {
+			t.Errorf("Expect %d shards for split size %d, but got %d", test.shards, test.splitSize, len(d.Shards))
+		}
+
+		var lock sync.Mutex
+		var lines []string
+		d.Output(func(reader io.Reader) error {
+			for {
+				_, row, err := util.ReadRow(reader)
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				lock.Lock()
+				lines = append(lines, string(row[0].([]byte)))
+				lock.Unlock()
+			}
+		})
+		if _, err := f.Run(); err != nil {
+			t.Fatalf("Failed to run: %v", err)
+		}
+		sort.Strings(lines)
+		if strings.Join(lines, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expect %v for split size %d, but got %v", expected, test.splitSize, lines)
+		}
+	}
+}
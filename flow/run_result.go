@@ -0,0 +1,130 @@
+package flow
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+// RunResult has the statistics of one flow execution.
+type RunResult struct {
+	StartTime time.Time
+	StopTime  time.Time
+	Steps     []*StepResult
+}
+
+// StepResult has the statistics of one step, summed up from all its tasks.
+type StepResult struct {
+	StepId        int
+	Name          string
+	StartTime     time.Time
+	StopTime      time.Time
+	InputCounter  int64
+	OutputCounter int64
+	Counters      map[string]int64      // the named counters added by the tasks
+	TaskStats     []*pb.InstructionStat // by task id, nil if the task reported nothing
+}
+
+// Counter returns the named counter summed up from all the steps.
+func (r *RunResult) Counter(name string) (value int64) {
+	for _, step := range r.Steps {
+		value += step.Counters[name]
+	}
+	return value
+}
+
+// TaskError is the failure of one task.
+type TaskError struct {
+	StepId   int
+	StepName string
+	TaskId   int
+	ExitCode int // exit code of the external process, -1 if not applicable
+	Err      error
+}
+
+func (e *TaskError) Error() string {
+	if e.ExitCode >= 0 {
+		return fmt.Sprintf("%s-%d task %d exit code %d: %v", e.StepName, e.StepId, e.TaskId, e.ExitCode, e.Err)
+	}
+	return fmt.Sprintf("%s-%d task %d: %v", e.StepName, e.StepId, e.TaskId, e.Err)
+}
+
+// RunError has all the failed tasks of one flow execution.
+type RunError struct {
+	Errors []*TaskError
+}
+
+func (e *RunError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d tasks failed", len(e.Errors))
+	for _, taskError := range e.Errors {
+		fmt.Fprintf(&buf, "\n  %s", taskError.Error())
+	}
+	return buf.String()
+}
+
+// CollectRunResult summarizes the statistics and timings of each task.
+func CollectRunResult(fc *Flow, startTime, stopTime time.Time) *RunResult {
+	result := &RunResult{
+		StartTime: startTime,
+		StopTime:  stopTime,
+	}
+	for _, step := range fc.Steps {
+		stepResult := &StepResult{
+			StepId:    step.Id,
+			Name:      step.Name,
+			StartTime: step.StartTime,
+		}
+		for _, task := range step.Tasks {
+			if stepResult.StartTime.IsZero() || (!task.StartTime.IsZero() && task.StartTime.Before(stepResult.StartTime)) {
+				stepResult.StartTime = task.StartTime
+			}
+			if task.StopTime.After(stepResult.StopTime) {
+				stepResult.StopTime = task.StopTime
+			}
+			stepResult.TaskStats = append(stepResult.TaskStats, task.Stat)
+			if task.Stat != nil {
+				stepResult.InputCounter += task.Stat.InputCounter
+				stepResult.OutputCounter += task.Stat.OutputCounter
+				for _, counter := range task.Stat.Counters {
+					if stepResult.Counters == nil {
+						stepResult.Counters = make(map[string]int64)
+					}
+					stepResult.Counters[counter.Name] += counter.Value
+				}
+			}
+		}
+		result.Steps = append(result.Steps, stepResult)
+	}
+	return result
+}
+
+// CollectRunError returns a *RunError if any task failed, otherwise nil.
+func CollectRunError(fc *Flow) error {
+	var taskErrors []*TaskError
+	for _, step := range fc.Steps {
+		for _, task := range step.Tasks {
+			if task.Error == nil {
+				continue
+			}
+			exitCode := -1
+			if execError, ok := task.Error.(*util.ExecError); ok {
+				exitCode = execError.ExitCode
+			}
+			taskErrors = append(taskErrors, &TaskError{
+				StepId:   step.Id,
+				StepName: step.Name,
+				TaskId:   task.Id,
+				ExitCode: exitCode,
+				Err:      task.Error,
+			})
+		}
+	}
+	if len(taskErrors) == 0 {
+		return nil
+	}
+	return &RunError{Errors: taskErrors}
+}
@@ -2,8 +2,11 @@ package flow
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"runtime"
 	"sync"
 	"time"
 
@@ -13,35 +16,61 @@ import (
 )
 
 type FlowRunner interface {
-	RunFlowContext(context.Context, *Flow)
+	RunFlowContext(context.Context, *Flow) (*RunResult, error)
 }
 
 type FlowOption interface {
 	GetFlowRunner() FlowRunner
 }
 
+// LocalOption runs the flow in the current process, limiting the
+// resources used by the tasks running at the same time.
+// The data for the tasks waiting for resources is buffered,
+// and spilled to files under BufferDir if it is too much.
+type LocalOption struct {
+	MaxProcesses   int    // max external processes, e.g., luajit, 0 means no limit
+	MaxMemoryMB    int64  // max estimated memory of the running tasks, 0 means no limit
+	BufferMemoryMB int64  // total memory to buffer the data to waiting tasks, default to 64MB
+	BufferDir      string // where the buffered data beyond BufferMemoryMB is spilled, default to os.TempDir()
+}
+
 type localDriver struct {
-	ctx context.Context
+	ctx       context.Context
+	option    *LocalOption
+	scheduler *localScheduler
 }
 
 var (
+	// Local runs at most one external process per cpu at the same time.
 	Local *localDriver
 )
 
 func init() {
-	Local = &localDriver{}
+	Local = &localDriver{
+		option: &LocalOption{MaxProcesses: runtime.NumCPU()},
+	}
+}
+
+func (o *LocalOption) GetFlowRunner() FlowRunner {
+	return &localDriver{option: o}
 }
 
 func (r *localDriver) GetFlowRunner() FlowRunner {
 	return r
 }
 
-func (r *localDriver) RunFlowContext(ctx context.Context, fc *Flow) {
-	r.ctx = ctx
+func (r *localDriver) RunFlowContext(ctx context.Context, fc *Flow) (*RunResult, error) {
+	run := &localDriver{
+		ctx:       ctx,
+		option:    r.option,
+		scheduler: newLocalScheduler(fc, r.option),
+	}
+	startTime := time.Now()
 	var wg sync.WaitGroup
 	wg.Add(1)
-	r.RunFlowAsync(&wg, fc)
+	run.RunFlowAsync(&wg, fc)
 	wg.Wait()
+	return CollectRunResult(fc, startTime, time.Now()), CollectRunError(fc)
 }
 
 func (r *localDriver) RunFlowAsync(wg *sync.WaitGroup, fc *Flow) {
@@ -85,8 +114,11 @@ func (r *localDriver) runDatasetShard(wg *sync.WaitGroup, shard *DatasetShard) {
 	shard.ReadyTime = time.Now()
 
 	var writers []io.Writer
-	for _, outgoingChan := range shard.OutgoingChans {
-		writers = append(writers, outgoingChan.Writer)
+	var closers []io.Closer
+	for i, outgoingChan := range shard.OutgoingChans {
+		writer := r.newShardWriter(wg, shard.ReadingTasks[i], outgoingChan)
+		writers = append(writers, writer)
+		closers = append(closers, writer)
 	}
 
 	util.BufWrites(writers, func(writers []io.Writer) {
@@ -97,9 +129,59 @@ func (r *localDriver) runDatasetShard(wg *sync.WaitGroup, shard *DatasetShard) {
 		shard.CloseTime = time.Now()
 	})
 
-	for _, outgoingChan := range shard.OutgoingChans {
-		outgoingChan.Writer.Close()
+	for _, closer := range closers {
+		closer.Close()
+	}
+}
+
+// newShardWriter buffers the data to a reading task when the resources are
+// limited, so that the running tasks are not blocked by the waiting ones.
+func (r *localDriver) newShardWriter(wg *sync.WaitGroup, task *Task, outgoingChan *util.Piper) io.WriteCloser {
+	if r.scheduler == nil {
+		return outgoingChan.Writer
 	}
+	return &shardWriter{driver: r, wg: wg, task: task, out: outgoingChan.Writer}
+}
+
+// shardWriter writes directly to the reading task if it has started by the
+// first write, or else to a SpillBuffer until the task catches up.
+type shardWriter struct {
+	driver *localDriver
+	wg     *sync.WaitGroup
+	task   *Task
+	out    io.WriteCloser
+	writer io.WriteCloser
+}
+
+func (w *shardWriter) Write(p []byte) (int, error) {
+	if w.writer == nil {
+		w.writer = w.out
+		if !w.driver.scheduler.isStarted(w.task) {
+			w.writer = w.newBuffer()
+		}
+	}
+	return w.writer.Write(p)
+}
+
+func (w *shardWriter) Close() error {
+	if w.writer == nil {
+		return w.out.Close()
+	}
+	return w.writer.Close()
+}
+
+func (w *shardWriter) newBuffer() *util.SpillBuffer {
+	s := w.driver.scheduler
+	buffer := util.NewSpillBuffer(s.bufferDir, s.buffers)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		io.Copy(w.out, buffer)
+		w.out.Close()
+		// remove any spilled data if the task stopped reading
+		io.Copy(ioutil.Discard, buffer)
+	}()
+	return buffer
 }
 
 func (r *localDriver) runStep(wg *sync.WaitGroup, step *Step) {
@@ -130,14 +212,31 @@ func (r *localDriver) runStep(wg *sync.WaitGroup, step *Step) {
 func (r *localDriver) runTask(wg *sync.WaitGroup, task *Task) {
 	defer wg.Done()
 
+	if r.scheduler != nil {
+		release := r.scheduler.start(task)
+		defer release()
+	}
+
+	task.StartTime = time.Now()
+	err := r.executeTask(wg, task)
+	task.StopTime = time.Now()
+	if err != nil {
+		task.Error = err
+		// drain the inputs so that the upstream tasks can finish
+		for _, inputChan := range task.InputChans {
+			io.Copy(ioutil.Discard, inputChan.Reader)
+		}
+	}
+}
+
+func (r *localDriver) executeTask(wg *sync.WaitGroup, task *Task) error {
 	// try to run Function first
 	// if failed, try to run shell scripts
 	// if failed, try to run lua scripts
 	if task.Step.Function != nil {
 		// each function should close its own Piper output writer
 		// and close it's own Piper input reader
-		task.Step.RunFunction(task)
-		return
+		return task.Step.RunFunction(task)
 	}
 
 	// get an exec.Command
@@ -151,8 +250,7 @@ func (r *localDriver) runTask(wg *sync.WaitGroup, task *Task) {
 		wg.Add(1)
 		prevIsPipe := task.InputShards[0].Dataset.Step.IsPipe
 		task.Stat = &pb.InstructionStat{}
-		util.Execute(r.ctx, wg, task.Stat, task.Step.Name, execCommand, reader, writer, prevIsPipe, task.Step.IsPipe, true, os.Stderr)
-	} else {
-		println("network type:", task.Step.NetworkType)
+		return util.Execute(r.ctx, wg, task.Stat, task.Step.Name, execCommand, reader, writer, prevIsPipe, task.Step.IsPipe, true, os.Stderr)
 	}
+	return fmt.Errorf("unsupported network type %d", task.Step.NetworkType)
 }
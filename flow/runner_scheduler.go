@@ -0,0 +1,150 @@
+package flow
+
+import (
+	"os"
+	"sync"
+
+	"github.com/chrislusf/gleamold/util"
+)
+
+// defaultBufferMemoryMB is the memory shared by the buffers to all the
+// reading tasks, before the data is spilled to disk.
+const defaultBufferMemoryMB = 64
+
+// localScheduler limits the external processes and the estimated memory
+// of the tasks running at the same time in the local runner.
+// A task only starts after all the tasks writing to its inputs have started.
+// Since the writes to the waiting tasks are buffered, the running tasks
+// can always finish and release their resources.
+type localScheduler struct {
+	sync.Mutex
+	cond         *sync.Cond
+	maxProcesses int
+	maxMemoryMB  int64
+	bufferDir    string
+	buffers      *util.SpillBudget
+	processes    int
+	memoryMB     int64
+	requirements map[*Task]*taskRequirement
+}
+
+type taskRequirement struct {
+	isProcess bool
+	memoryMB  int64
+	upstreams []*Task
+	isStarted bool
+}
+
+// newLocalScheduler returns nil if there are no limits.
+func newLocalScheduler(fc *Flow, option *LocalOption) *localScheduler {
+	if option == nil || (option.MaxProcesses <= 0 && option.MaxMemoryMB <= 0) {
+		return nil
+	}
+	s := &localScheduler{
+		maxProcesses: option.MaxProcesses,
+		maxMemoryMB:  option.MaxMemoryMB,
+		bufferDir:    option.BufferDir,
+		requirements: make(map[*Task]*taskRequirement),
+	}
+	if s.bufferDir == "" {
+		s.bufferDir = os.TempDir()
+	}
+	bufferMemoryMB := option.BufferMemoryMB
+	if bufferMemoryMB <= 0 {
+		bufferMemoryMB = defaultBufferMemoryMB
+	}
+	s.buffers = util.NewSpillBudget(bufferMemoryMB * 1024 * 1024)
+	s.cond = sync.NewCond(s)
+	for _, step := range fc.Steps {
+		for _, task := range step.Tasks {
+			s.requirements[task] = s.estimate(task)
+		}
+	}
+	return s
+}
+
+// estimate uses the instruction memory cost, or the partition size hint
+// for external processes.
+func (s *localScheduler) estimate(task *Task) *taskRequirement {
+	step := task.Step
+	var partitionSize int64
+	if step.OutputDataset != nil {
+		partitionSize = step.OutputDataset.GetPartitionSize()
+	}
+
+	req := &taskRequirement{}
+	if step.Function != nil {
+		if step.Instruction != nil {
+			req.memoryMB = step.Instruction.GetMemoryCostInMB(partitionSize)
+		}
+	} else {
+		req.isProcess = true
+		req.memoryMB = partitionSize
+	}
+	// a task needing more than the limit can still run alone
+	if s.maxMemoryMB > 0 && req.memoryMB > s.maxMemoryMB {
+		req.memoryMB = s.maxMemoryMB
+	}
+
+	for _, shard := range task.InputShards {
+		for _, t := range shard.Dataset.Step.Tasks {
+			for _, out := range t.OutputShards {
+				if out == shard {
+					req.upstreams = append(req.upstreams, t)
+				}
+			}
+		}
+	}
+	return req
+}
+
+// start waits until the task can run, and returns the function
+// to release the resources after the task is done.
+func (s *localScheduler) start(task *Task) (release func()) {
+	s.Lock()
+	defer s.Unlock()
+
+	req := s.requirements[task]
+	for !s.canStart(req) {
+		s.cond.Wait()
+	}
+	req.isStarted = true
+	if req.isProcess {
+		s.processes++
+	}
+	s.memoryMB += req.memoryMB
+	s.cond.Broadcast()
+
+	return func() {
+		s.Lock()
+		defer s.Unlock()
+		if req.isProcess {
+			s.processes--
+		}
+		s.memoryMB -= req.memoryMB
+		s.cond.Broadcast()
+	}
+}
+
+// isStarted checks whether the task has been admitted to run.
+func (s *localScheduler) isStarted(task *Task) bool {
+	s.Lock()
+	defer s.Unlock()
+	req, ok := s.requirements[task]
+	return ok && req.isStarted
+}
+
+func (s *localScheduler) canStart(req *taskRequirement) bool {
+	for _, upstream := range req.upstreams {
+		if !s.requirements[upstream].isStarted {
+			return false
+		}
+	}
+	if req.isProcess && s.maxProcesses > 0 && s.processes >= s.maxProcesses {
+		return false
+	}
+	if s.maxMemoryMB > 0 && req.memoryMB > 0 && s.memoryMB+req.memoryMB > s.maxMemoryMB {
+		return false
+	}
+	return true
+}
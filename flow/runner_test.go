@@ -0,0 +1,33 @@
+package flow
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestShardWriterBuffersOnlyForWaitingTasks(t *testing.T) {
+	f := New()
+	f.Slices([][]interface{}{{1}, {2}}).RoundRobin(2).LocalSort(Field(1))
+	shards := f.Steps[2].InputDatasets[0].Shards
+	r := &localDriver{scheduler: newLocalScheduler(f, &LocalOption{MaxProcesses: 1})}
+
+	for i, started := range []bool{true, false} {
+		task, piper := shards[i].ReadingTasks[0], shards[i].OutgoingChans[0]
+		r.scheduler.requirements[task].isStarted = started
+		var wg sync.WaitGroup
+		w := r.newShardWriter(&wg, task, piper).(*shardWriter)
+		go func() {
+			w.Write([]byte("data"))
+			w.Close()
+		}()
+		data, _ := ioutil.ReadAll(piper.Reader)
+		wg.Wait()
+		if string(data) != "data" {
+			t.Errorf("Expect data, but got %q", data)
+		}
+		if isBuffered := w.writer != w.out; isBuffered == started {
+			t.Errorf("Expect buffered %v for started %v", !started, started)
+		}
+	}
+}
@@ -51,7 +51,10 @@ func (step *Step) RunFunction(task *Task) error {
 		}
 	}()
 
-	task.Stat = &pb.InstructionStat{}
+	task.Stat = &pb.InstructionStat{
+		StepId: int32(task.Step.Id),
+		TaskId: int32(task.Id),
+	}
 	err := task.Step.Function(readers, writers, task.Stat)
 	if err != nil {
 		log.Printf("Failed to run task %s-%d: %v\n", task.Step.Name, task.Id, err)
@@ -41,8 +41,9 @@ const (
 )
 
 type DasetsetMetadata struct {
-	TotalSize int64
-	OnDisk    ModeIO
+	TotalSize  int64
+	OnDisk     ModeIO
+	NoCombiner bool
 }
 
 type DasetsetShardMetadata struct {
@@ -120,6 +121,9 @@ type Task struct {
 	InputChans   []*util.Piper // task specific input chans. InputShard may have multiple reading tasks
 	OutputShards []*DatasetShard
 	Stat         *pb.InstructionStat
+	StartTime    time.Time
+	StopTime     time.Time
+	Error        error
 }
 
 type RunLocked struct {
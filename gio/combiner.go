@@ -0,0 +1,69 @@
+package gio
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chrislusf/gleamold/util"
+)
+
+type combinerEntry struct {
+	ts     int64
+	keys   []interface{}
+	values []interface{}
+}
+
+// ProcessCombiner partially reduces rows that are not sorted.
+// The values of the same keys are reduced in a hash table of at most maxKeys entries,
+// which is flushed whenever it is full. So the output may still have duplicated keys.
+func ProcessCombiner(f Reducer, keyPositions []int, maxKeys int) (err error) {
+
+	keyFields := toKeyFields(keyPositions)
+	entries := make(map[string]*combinerEntry)
+
+	for {
+		ts, row, err := util.ReadRow(os.Stdin)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("combiner input row error: %v", err)
+		}
+
+		keys, values := getKeysAndValues(row, keyFields)
+		keyBytes, err := util.EncodeKeys(keys...)
+		if err != nil {
+			return fmt.Errorf("combiner failed to encode keys %v: %v", keys, err)
+		}
+
+		if entry, found := entries[string(keyBytes)]; found {
+			if entry.values, err = reduce(f, entry.values, values); err != nil {
+				return fmt.Errorf("combiner failed to reduce keys %v: %v", keys, err)
+			}
+			if ts > entry.ts {
+				entry.ts = ts
+			}
+			continue
+		}
+
+		if len(entries) >= maxKeys {
+			if err = flushCombinerEntries(entries); err != nil {
+				return err
+			}
+			entries = make(map[string]*combinerEntry)
+		}
+		entries[string(keyBytes)] = &combinerEntry{ts: ts, keys: keys, values: values}
+	}
+
+	return flushCombinerEntries(entries)
+}
+
+func flushCombinerEntries(entries map[string]*combinerEntry) error {
+	for _, entry := range entries {
+		if err := output(entry.ts, entry.keys, entry.values); err != nil {
+			return fmt.Errorf("combiner output error: %v", err)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package gio
 
 import (
+	"encoding/base64"
 	"flag"
 	"fmt"
 	"log"
@@ -15,10 +16,17 @@ type ReducerId string
 type Mapper func([]interface{}) error
 type Reducer func(x, y interface{}) (interface{}, error)
 
+// A MapperFactory creates a mapper with the arguments passed by Dataset.MapperWithArgs().
+// The done function is called after all rows are processed, with the processing error if any,
+// and returns the final error, e.g., flushing the buffered writes.
+type MapperFactory func(args []byte) (mapper Mapper, done func(error) error, err error)
+
 type gleamTaskOption struct {
-	Mapper    string
-	Reducer   string
-	KeyFields string
+	Mapper       string
+	MapperArgs   string
+	Reducer      string
+	KeyFields    string
+	CombinerKeys int
 }
 
 var (
@@ -29,19 +37,23 @@ var (
 
 func init() {
 	flag.StringVar(&taskOption.Mapper, "gleamold.mapper", "", "the generated mapper name")
+	flag.StringVar(&taskOption.MapperArgs, "gleamold.mapperArgs", "", "the base64 encoded arguments for the mapper")
 	flag.StringVar(&taskOption.Reducer, "gleamold.reducer", "", "the generated reducer name")
 	flag.StringVar(&taskOption.KeyFields, "gleamold.keyFields", "", "the 1-based key fields")
+	flag.IntVar(&taskOption.CombinerKeys, "gleamold.combinerKeys", 0, "if positive, run the reducer as a hash combiner holding at most this many keys")
 }
 
 var (
-	mappers      map[string]Mapper
-	reducers     map[string]Reducer
-	mappersLock  sync.Mutex
-	reducersLock sync.Mutex
+	mappers         map[string]Mapper
+	mapperFactories map[string]MapperFactory
+	reducers        map[string]Reducer
+	mappersLock     sync.Mutex
+	reducersLock    sync.Mutex
 )
 
 func init() {
 	mappers = make(map[string]Mapper)
+	mapperFactories = make(map[string]MapperFactory)
 	reducers = make(map[string]Reducer)
 }
 
@@ -50,11 +62,26 @@ func RegisterMapper(fn Mapper) MapperId {
 	mappersLock.Lock()
 	defer mappersLock.Unlock()
 
-	mapperName := fmt.Sprintf("m%d", len(mappers)+1)
+	mapperName := fmt.Sprintf("m%d", len(mappers)+len(mapperFactories)+1)
 	mappers[mapperName] = fn
 	return MapperId(mapperName)
 }
 
+// RegisterMapperFactory register a mapper that needs arguments, e.g., the configuration of a sink.
+func RegisterMapperFactory(fn MapperFactory) MapperId {
+	mappersLock.Lock()
+	defer mappersLock.Unlock()
+
+	mapperName := fmt.Sprintf("m%d", len(mappers)+len(mapperFactories)+1)
+	mapperFactories[mapperName] = fn
+	return MapperId(mapperName)
+}
+
+// EncodeMapperArgs encodes the mapper arguments into one command line argument.
+func EncodeMapperArgs(args []byte) string {
+	return base64.RawURLEncoding.EncodeToString(args)
+}
+
 func RegisterReducer(fn Reducer) ReducerId {
 	reducersLock.Lock()
 	defer reducersLock.Unlock()
@@ -87,9 +114,14 @@ func runMapperReducer() {
 				log.Fatalf("Failed to execute mapper %v: %v", os.Args, err)
 			}
 			return
-		} else {
-			log.Fatalf("Failed to find mapper function for %v", taskOption.Mapper)
 		}
+		if fn, ok := mapperFactories[taskOption.Mapper]; ok {
+			if err := processMapperFactory(fn, taskOption.MapperArgs); err != nil {
+				log.Fatalf("Failed to execute mapper %v: %v", os.Args, err)
+			}
+			return
+		}
+		log.Fatalf("Failed to find mapper function for %v", taskOption.Mapper)
 	}
 
 	if taskOption.Reducer != "" {
@@ -108,6 +140,13 @@ func runMapperReducer() {
 				keyIndexes = append(keyIndexes, keyIndex)
 			}
 
+			if taskOption.CombinerKeys > 0 {
+				if err := ProcessCombiner(fn, keyIndexes, taskOption.CombinerKeys); err != nil {
+					log.Fatalf("Failed to execute combiner %v: %v", os.Args, err)
+				}
+				return
+			}
+
 			if err := ProcessReducer(fn, keyIndexes); err != nil {
 				log.Fatalf("Failed to execute reducer %v: %v", os.Args, err)
 			}
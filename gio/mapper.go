@@ -1,6 +1,7 @@
 package gio
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"os"
@@ -27,6 +28,13 @@ func TsEmit(ts int64, anyObject ...interface{}) error {
 	return util.WriteRow(os.Stdout, ts, anyObject...)
 }
 
+// AddCounter adds the value to the named counter of the current task,
+// reported to the driver in the task stats of the flow's RunResult.
+// It only works in the Go mappers and reducers run by the flow.
+func AddCounter(name string, value int64) error {
+	return util.WriteCounter(os.Stderr, name, value)
+}
+
 func ProcessMapper(f Mapper) (err error) {
 	var row []interface{}
 	for {
@@ -44,3 +52,15 @@ func ProcessMapper(f Mapper) (err error) {
 	}
 	return nil
 }
+
+func processMapperFactory(fn MapperFactory, encodedArgs string) error {
+	args, err := base64.RawURLEncoding.DecodeString(encodedArgs)
+	if err != nil {
+		return fmt.Errorf("mapper arguments error: %v", err)
+	}
+	mapper, done, err := fn(args)
+	if err != nil {
+		return err
+	}
+	return done(ProcessMapper(mapper))
+}
@@ -10,11 +10,7 @@ import (
 
 func ProcessReducer(f Reducer, keyPositions []int) (err error) {
 
-	keyFields := make([]bool, len(keyPositions))
-	for _, keyPosition := range keyPositions {
-		// change from 1-base to 0-base
-		keyFields[keyPosition-1] = true
-	}
+	keyFields := toKeyFields(keyPositions)
 
 	// get the first row
 	ts, row, err := util.ReadRow(os.Stdin)
@@ -76,6 +72,21 @@ func reduce(f Reducer, x, y []interface{}) ([]interface{}, error) {
 	return z.([]interface{}), nil
 }
 
+func toKeyFields(keyPositions []int) []bool {
+	width := 0
+	for _, keyPosition := range keyPositions {
+		if keyPosition > width {
+			width = keyPosition
+		}
+	}
+	keyFields := make([]bool, width)
+	for _, keyPosition := range keyPositions {
+		// change from 1-base to 0-base
+		keyFields[keyPosition-1] = true
+	}
+	return keyFields
+}
+
 func getKeysAndValues(row []interface{}, keyFields []bool) (keys, values []interface{}) {
 	for i, data := range row {
 		if i < len(keyFields) && keyFields[i] {
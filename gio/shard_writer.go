@@ -0,0 +1,37 @@
+package gio
+
+import (
+	"fmt"
+)
+
+// A ShardWriter writes the rows of one dataset shard to an external system, e.g., by a sink.
+type ShardWriter interface {
+	Write(row []interface{}) error
+	// Commit is called after all rows are written, e.g., to flush the buffered rows.
+	Commit() error
+	// Abort is called instead of Commit if the rows can not be all written,
+	// e.g., to discard the buffered rows.
+	Abort() error
+}
+
+// A ShardWriterOpener opens a ShardWriter with the args passed to Dataset.WriteShards().
+type ShardWriterOpener func(args []byte) (ShardWriter, error)
+
+// RegisterShardWriter registers a mapper to write each shard via the opened ShardWriter.
+func RegisterShardWriter(open ShardWriterOpener) MapperId {
+	return RegisterMapperFactory(func(args []byte) (Mapper, func(error) error, error) {
+		w, err := open(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		return w.Write, func(err error) error {
+			if err == nil {
+				return w.Commit()
+			}
+			if abortErr := w.Abort(); abortErr != nil {
+				return fmt.Errorf("%v, and failed to abort: %v", err, abortErr)
+			}
+			return err
+		}, nil
+	})
+}
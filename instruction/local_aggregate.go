@@ -0,0 +1,427 @@
+package instruction
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetLocalAggregate() != nil {
+			return NewLocalAggregate(
+				toInts(m.GetLocalAggregate().GetIndexes()),
+				toAggregators(m.GetLocalAggregate().GetAggregators()),
+			)
+		}
+		return nil
+	})
+}
+
+type AggregatorType int
+
+const (
+	AggregateCount AggregatorType = iota + 1
+	AggregateSum
+	AggregateMin
+	AggregateMax
+	AggregateAvg
+	AggregateCountDistinct
+	AggregateFirst
+	AggregateLast
+	AggregateCountNotNull
+	AggregateConcat
+)
+
+type Aggregator struct {
+	Type  AggregatorType
+	Index int // column index, starting from 1, not used by AggregateCount
+}
+
+// LocalAggregate computes the aggregators for each group of rows with the
+// same key fields. The rows should already be locally sorted by the keys.
+// Each output row has the keys followed by one value for each aggregator.
+type LocalAggregate struct {
+	indexes     []int
+	aggregators []Aggregator
+}
+
+func NewLocalAggregate(indexes []int, aggregators []Aggregator) *LocalAggregate {
+	return &LocalAggregate{indexes, aggregators}
+}
+
+func (b *LocalAggregate) Name() string {
+	return "LocalAggregate"
+}
+
+func (b *LocalAggregate) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoLocalAggregate(readers[0], writers[0], b.indexes, b.aggregators, stats)
+	}
+}
+
+func (b *LocalAggregate) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		Name: b.Name(),
+		LocalAggregate: &pb.Instruction_LocalAggregate{
+			Indexes:     getIndexes(b.indexes),
+			Aggregators: getAggregators(b.aggregators),
+		},
+	}
+}
+
+func (b *LocalAggregate) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+// DoLocalAggregate streams through the rows sorted by the key fields.
+// If there are no key fields, all rows are aggregated into one row,
+// which is written out even if there are no input rows.
+func DoLocalAggregate(reader io.Reader, writer io.Writer, indexes []int, aggregators []Aggregator, stats *pb.InstructionStat) error {
+	for _, a := range aggregators {
+		if a.Type < AggregateCount || a.Type > AggregateConcat {
+			return fmt.Errorf("Aggregate>Unknown aggregator type %d", a.Type)
+		}
+	}
+
+	var prevKeys []interface{}
+	var prevTs int64
+	var accumulators []accumulator
+
+	flush := func() error {
+		row := append([]interface{}{}, prevKeys...)
+		for _, acc := range accumulators {
+			row = append(row, acc.result())
+		}
+		if err := util.WriteRow(writer, prevTs, row...); err != nil {
+			return fmt.Errorf("Aggregate>Failed to write: %v", err)
+		}
+		stats.OutputCounter++
+		return nil
+	}
+
+	err := util.ProcessMessage(reader, func(input []byte) error {
+		ts, row, err := util.DecodeRow(input)
+		if err != nil {
+			return fmt.Errorf("Aggregate>Failed to decode %v: %+v", err, input)
+		}
+		stats.InputCounter++
+
+		keys, err := pickFields(row, indexes)
+		if err != nil {
+			return err
+		}
+		if accumulators == nil || util.Compare(keys, prevKeys) != 0 {
+			if accumulators != nil {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			prevKeys, prevTs = keys, ts
+			accumulators = newAccumulators(aggregators)
+		} else if ts > prevTs {
+			prevTs = ts
+		}
+
+		for i, a := range aggregators {
+			var value interface{}
+			if a.Type != AggregateCount {
+				if a.Index < 1 || a.Index > len(row) {
+					return fmt.Errorf("Aggregate>Field %d does not exist in row %v", a.Index, row)
+				}
+				value = row[a.Index-1]
+			}
+			if err := accumulators[i].add(ts, value); err != nil {
+				return fmt.Errorf("Aggregate>Field %d: %v", a.Index, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if accumulators == nil && len(indexes) == 0 {
+		accumulators = newAccumulators(aggregators)
+	}
+	if accumulators != nil {
+		return flush()
+	}
+	return nil
+}
+
+func pickFields(row []interface{}, indexes []int) (fields []interface{}, err error) {
+	for _, x := range indexes {
+		if x < 1 || x > len(row) {
+			return nil, fmt.Errorf("Aggregate>Key field %d does not exist in row %v", x, row)
+		}
+		fields = append(fields, row[x-1])
+	}
+	return fields, nil
+}
+
+func toAggregators(aggregators []*pb.Instruction_Aggregator) (ret []Aggregator) {
+	for _, a := range aggregators {
+		ret = append(ret, Aggregator{
+			Type:  AggregatorType(a.GetType()),
+			Index: int(a.GetIndex()),
+		})
+	}
+	return ret
+}
+
+func getAggregators(aggregators []Aggregator) (ret []*pb.Instruction_Aggregator) {
+	for _, a := range aggregators {
+		ret = append(ret, &pb.Instruction_Aggregator{
+			Type:  int32(a.Type),
+			Index: int32(a.Index),
+		})
+	}
+	return ret
+}
+
+// accumulator collects the values of one aggregator for one group.
+// Nil values are skipped, except by AggregateCount.
+type accumulator interface {
+	add(ts int64, value interface{}) error
+	result() interface{}
+}
+
+func newAccumulators(aggregators []Aggregator) (ret []accumulator) {
+	for _, a := range aggregators {
+		switch a.Type {
+		case AggregateCount:
+			ret = append(ret, &countAccumulator{})
+		case AggregateSum:
+			ret = append(ret, &sumAccumulator{})
+		case AggregateMin:
+			ret = append(ret, &minMaxAccumulator{isMax: false})
+		case AggregateMax:
+			ret = append(ret, &minMaxAccumulator{isMax: true})
+		case AggregateAvg:
+			ret = append(ret, &avgAccumulator{})
+		case AggregateCountDistinct:
+			ret = append(ret, &countDistinctAccumulator{seen: make(map[string]bool)})
+		case AggregateFirst:
+			ret = append(ret, &firstLastAccumulator{isLast: false})
+		case AggregateLast:
+			ret = append(ret, &firstLastAccumulator{isLast: true})
+		case AggregateCountNotNull:
+			ret = append(ret, &countAccumulator{notNull: true})
+		case AggregateConcat:
+			ret = append(ret, &concatAccumulator{})
+		}
+	}
+	return ret
+}
+
+type countAccumulator struct {
+	notNull bool
+	count   int64
+}
+
+func (a *countAccumulator) add(ts int64, value interface{}) error {
+	if a.notNull && value == nil {
+		return nil
+	}
+	a.count++
+	return nil
+}
+
+func (a *countAccumulator) result() interface{} {
+	return a.count
+}
+
+// sumAccumulator keeps an int64 sum until a float value is added.
+type sumAccumulator struct {
+	hasValue bool
+	isFloat  bool
+	intSum   int64
+	floatSum float64
+}
+
+func (a *sumAccumulator) add(ts int64, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	i, f, isFloat, ok := toNumber(value)
+	if !ok {
+		return fmt.Errorf("can not sum %v of type %T", value, value)
+	}
+	a.hasValue = true
+	if isFloat && !a.isFloat {
+		a.isFloat = true
+		a.floatSum = float64(a.intSum)
+	}
+	if a.isFloat {
+		a.floatSum += f
+	} else {
+		a.intSum += i
+	}
+	return nil
+}
+
+func (a *sumAccumulator) result() interface{} {
+	if !a.hasValue {
+		return nil
+	}
+	if a.isFloat {
+		return a.floatSum
+	}
+	return a.intSum
+}
+
+type minMaxAccumulator struct {
+	isMax bool
+	value interface{}
+}
+
+func (a *minMaxAccumulator) add(ts int64, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if a.value == nil {
+		a.value = value
+		return nil
+	}
+	x := util.Compare(value, a.value)
+	if (a.isMax && x > 0) || (!a.isMax && x < 0) {
+		a.value = value
+	}
+	return nil
+}
+
+func (a *minMaxAccumulator) result() interface{} {
+	return a.value
+}
+
+type avgAccumulator struct {
+	count int64
+	sum   float64
+}
+
+func (a *avgAccumulator) add(ts int64, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	_, f, _, ok := toNumber(value)
+	if !ok {
+		return fmt.Errorf("can not average %v of type %T", value, value)
+	}
+	a.count++
+	a.sum += f
+	return nil
+}
+
+func (a *avgAccumulator) result() interface{} {
+	if a.count == 0 {
+		return nil
+	}
+	return a.sum / float64(a.count)
+}
+
+type countDistinctAccumulator struct {
+	seen map[string]bool
+}
+
+func (a *countDistinctAccumulator) add(ts int64, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	key, err := util.EncodeKeys(value)
+	if err != nil {
+		return err
+	}
+	a.seen[string(key)] = true
+	return nil
+}
+
+func (a *countDistinctAccumulator) result() interface{} {
+	return int64(len(a.seen))
+}
+
+// firstLastAccumulator picks the value with the smallest or largest timestamp.
+// For the same timestamp, the earlier row wins.
+type firstLastAccumulator struct {
+	isLast   bool
+	hasValue bool
+	ts       int64
+	value    interface{}
+}
+
+func (a *firstLastAccumulator) add(ts int64, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if !a.hasValue || (a.isLast && ts > a.ts) || (!a.isLast && ts < a.ts) {
+		a.hasValue, a.ts, a.value = true, ts, value
+	}
+	return nil
+}
+
+func (a *firstLastAccumulator) result() interface{} {
+	return a.value
+}
+
+// concatAccumulator joins the values with commas, in the order of the rows.
+type concatAccumulator struct {
+	hasValue bool
+	buf      bytes.Buffer
+}
+
+func (a *concatAccumulator) add(ts int64, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if a.hasValue {
+		a.buf.WriteByte(',')
+	}
+	a.hasValue = true
+	switch x := value.(type) {
+	case string:
+		a.buf.WriteString(x)
+	case []byte:
+		a.buf.Write(x)
+	default:
+		fmt.Fprintf(&a.buf, "%v", x)
+	}
+	return nil
+}
+
+func (a *concatAccumulator) result() interface{} {
+	if !a.hasValue {
+		return nil
+	}
+	return a.buf.String()
+}
+
+func toNumber(value interface{}) (i int64, f float64, isFloat bool, ok bool) {
+	switch x := value.(type) {
+	case int64:
+		return x, float64(x), false, true
+	case uint64:
+		return int64(x), float64(x), false, true
+	case int:
+		return int64(x), float64(x), false, true
+	case int32:
+		return int64(x), float64(x), false, true
+	case uint32:
+		return int64(x), float64(x), false, true
+	case int16:
+		return int64(x), float64(x), false, true
+	case uint16:
+		return int64(x), float64(x), false, true
+	case int8:
+		return int64(x), float64(x), false, true
+	case uint8:
+		return int64(x), float64(x), false, true
+	case float64:
+		return int64(x), x, true, true
+	case float32:
+		return int64(x), float64(x), true, true
+	}
+	return 0, 0, false, false
+}
@@ -0,0 +1,95 @@
+package instruction
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func TestLocalAggregate(t *testing.T) {
+
+	var input bytes.Buffer
+	util.WriteRow(&input, 3, "a", 1, 2.5, "x")
+	util.WriteRow(&input, 1, "a", 2, nil, "y")
+	util.WriteRow(&input, 2, "a", 3, 0.5, "x")
+	util.WriteRow(&input, 5, "b", 4, 1.0, "z")
+
+	aggregators := []Aggregator{
+		{Type: AggregateCount},
+		{Type: AggregateSum, Index: 2},
+		{Type: AggregateSum, Index: 3},
+		{Type: AggregateMin, Index: 2},
+		{Type: AggregateMax, Index: 4},
+		{Type: AggregateAvg, Index: 3},
+		{Type: AggregateCountDistinct, Index: 4},
+		{Type: AggregateFirst, Index: 4},
+		{Type: AggregateLast, Index: 4},
+		{Type: AggregateCountNotNull, Index: 3},
+		{Type: AggregateConcat, Index: 4},
+	}
+
+	var output bytes.Buffer
+	stats := &pb.InstructionStat{}
+	if err := DoLocalAggregate(&input, &output, []int{1}, aggregators, stats); err != nil {
+		t.Fatalf("Failed to aggregate: %v", err)
+	}
+	if stats.InputCounter != 4 || stats.OutputCounter != 2 {
+		t.Errorf("Expect 4 input and 2 output rows, but got %d and %d", stats.InputCounter, stats.OutputCounter)
+	}
+
+	expected := [][]interface{}{
+		{"a", int64(3), int64(6), 3.0, int64(1), "y", 1.5, int64(2), "y", "x", int64(2), "x,y,x"},
+		{"b", int64(1), int64(4), 1.0, int64(4), "z", 1.0, int64(1), "z", "z", int64(1), "z"},
+	}
+	for _, want := range expected {
+		_, row, err := util.ReadRow(&output)
+		if err != nil {
+			t.Fatalf("Failed to read aggregated row: %v", err)
+		}
+		if !sameRow(row, want) {
+			t.Errorf("Expect %v, but got %v", want, row)
+		}
+	}
+	if _, _, err := util.ReadRow(&output); err != io.EOF {
+		t.Errorf("Expect no more rows, but got error %v", err)
+	}
+}
+
+func TestLocalAggregateWithoutKeys(t *testing.T) {
+
+	var output bytes.Buffer
+	stats := &pb.InstructionStat{}
+	aggregators := []Aggregator{{Type: AggregateCount}, {Type: AggregateSum, Index: 1}}
+	if err := DoLocalAggregate(&bytes.Buffer{}, &output, nil, aggregators, stats); err != nil {
+		t.Fatalf("Failed to aggregate: %v", err)
+	}
+	_, row, err := util.ReadRow(&output)
+	if err != nil {
+		t.Fatalf("Failed to read aggregated row: %v", err)
+	}
+	if !sameRow(row, []interface{}{int64(0), nil}) {
+		t.Errorf("Expect count 0 and nil sum, but got %v", row)
+	}
+}
+
+// sameRow compares the decoded values, since strings are decoded as []byte.
+func sameRow(row, expected []interface{}) bool {
+	if len(row) != len(expected) {
+		return false
+	}
+	for i, x := range expected {
+		if x == nil || row[i] == nil {
+			if x != row[i] {
+				return false
+			}
+			continue
+		}
+		if util.Compare(x, row[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
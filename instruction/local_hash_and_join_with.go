@@ -52,13 +52,14 @@ func (b *LocalHashAndJoinWith) GetMemoryCostInMB(partitionSize int64) int64 {
 }
 
 func DoLocalHashAndJoinWith(leftReader, rightReader io.Reader, writer io.Writer, indexes []int, stats *pb.InstructionStat) error {
-	hashmap := make(map[string][]interface{})
+	// all rows with the same keys are kept, to join with each row of the bigger input
+	hashmap := make(map[string][][]interface{})
 	err := util.ProcessMessage(leftReader, func(input []byte) error {
 		if keys, vals, err := genKeyBytesAndValues(input, indexes); err != nil {
 			return fmt.Errorf("%v: %+v", err, input)
 		} else {
 			stats.InputCounter++
-			hashmap[string(keys)] = vals
+			hashmap[string(keys)] = append(hashmap[string(keys)], vals)
 		}
 		return nil
 	})
@@ -80,10 +81,9 @@ func DoLocalHashAndJoinWith(leftReader, rightReader io.Reader, writer io.Writer,
 			if err != nil {
 				return fmt.Errorf("Failed to encoded row %+v: %v", keys, err)
 			}
-			if mappedValues, ok := hashmap[string(keyBytes)]; ok {
-				row := append(keys, vals...)
-				row = append(row, mappedValues...)
-				util.WriteRow(writer, ts, row...)
+			row := append(keys, vals...)
+			for _, mappedValues := range hashmap[string(keyBytes)] {
+				util.WriteRow(writer, ts, append(row, mappedValues...)...)
 				stats.OutputCounter++
 			}
 		}
@@ -0,0 +1,43 @@
+package instruction
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func TestLocalHashAndJoinWithSameKeys(t *testing.T) {
+
+	var smaller, bigger bytes.Buffer
+	util.WriteRow(&smaller, 1, "a", 1)
+	util.WriteRow(&smaller, 1, "a", 2)
+	util.WriteRow(&smaller, 1, "b", 3)
+	util.WriteRow(&bigger, 2, "a", "x")
+	util.WriteRow(&bigger, 2, "c", "y")
+
+	var output bytes.Buffer
+	stats := &pb.InstructionStat{}
+	if err := DoLocalHashAndJoinWith(&smaller, &bigger, &output, []int{1}, stats); err != nil {
+		t.Fatalf("Failed to join: %v", err)
+	}
+
+	expected := [][]interface{}{
+		{"a", "x", 1},
+		{"a", "x", 2},
+	}
+	for _, want := range expected {
+		_, row, err := util.ReadRow(&output)
+		if err != nil {
+			t.Fatalf("Failed to read joined row: %v", err)
+		}
+		if !sameRow(row, want) {
+			t.Errorf("Expect %v, but got %v", want, row)
+		}
+	}
+	if _, _, err := util.ReadRow(&output); err != io.EOF {
+		t.Errorf("Expect no more rows, but got error %v", err)
+	}
+}
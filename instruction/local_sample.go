@@ -0,0 +1,90 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetLocalSample() != nil {
+			return NewLocalSample(
+				int(m.GetLocalSample().GetN()),
+				toOrderBys(m.GetLocalSample().GetOrderBys()),
+			)
+		}
+		return nil
+	})
+}
+
+type LocalSample struct {
+	n        int
+	orderBys []OrderBy
+}
+
+func NewLocalSample(n int, orderBys []OrderBy) *LocalSample {
+	return &LocalSample{n, orderBys}
+}
+
+func (b *LocalSample) Name() string {
+	return "LocalSample"
+}
+
+func (b *LocalSample) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoLocalSample(readers[0], writers[0], b.n, b.orderBys, stats)
+	}
+}
+
+func (b *LocalSample) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		Name: b.Name(),
+		LocalSample: &pb.Instruction_LocalSample{
+			N:        int32(b.n),
+			OrderBys: getOrderBys(b.orderBys),
+		},
+	}
+}
+
+func (b *LocalSample) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+// DoLocalSample picks n random rows with reservoir sampling,
+// and writes out only the sort keys of the picked rows.
+func DoLocalSample(reader io.Reader, writer io.Writer, n int, orderBys []OrderBy, stats *pb.InstructionStat) error {
+	indexes := getIndexesFromOrderBys(orderBys)
+	var samples [][]interface{}
+	var timestamps []int64
+
+	err := util.ProcessMessage(reader, func(input []byte) error {
+		ts, keys, err := util.DecodeRowKeys(input, indexes)
+		if err != nil {
+			return fmt.Errorf("%v: %+v", err, input)
+		}
+		stats.InputCounter++
+		if len(samples) < n {
+			samples = append(samples, keys)
+			timestamps = append(timestamps, ts)
+		} else if x := rand.Int63n(stats.InputCounter); x < int64(n) {
+			samples[x], timestamps[x] = keys, ts
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Sample>Failed to process input data:%v\n", err)
+		return err
+	}
+
+	for i, keys := range samples {
+		if err := util.WriteRow(writer, timestamps[i], keys...); err != nil {
+			return fmt.Errorf("Sample>Failed to write: %v", err)
+		}
+		stats.OutputCounter++
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package instruction
 
 import (
+	"bufio"
 	"fmt"
 	"io"
-	"math"
+	"io/ioutil"
+	"os"
 
 	"github.com/chrislusf/gleamold/pb"
 	"github.com/chrislusf/gleamold/util"
@@ -22,6 +24,15 @@ func init() {
 	})
 }
 
+var (
+	// SpillDir is where LocalSort writes its sorted runs when a partition
+	// does not fit in memory. Executors set it to the agent directory.
+	SpillDir = os.TempDir()
+	// MaxLocalSortMemoryInMB caps the memory used to sort one run.
+	// It is also used when the partition size is unknown.
+	MaxLocalSortMemoryInMB = 256
+)
+
 type pair struct {
 	keys []interface{}
 	data []byte
@@ -42,13 +53,14 @@ func (b *LocalSort) Name() string {
 
 func (b *LocalSort) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
 	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
-		return DoLocalSort(readers[0], writers[0], b.orderBys, stats)
+		return DoLocalSort(readers[0], writers[0], b.orderBys, b.runMemoryInMB(), stats)
 	}
 }
 
 func (b *LocalSort) SerializeToCommand() *pb.Instruction {
 	return &pb.Instruction{
-		Name: b.Name(),
+		Name:       b.Name(),
+		MemoryInMB: int32(b.memoryInMB),
 		LocalSort: &pb.Instruction_LocalSort{
 			OrderBys: getOrderBys(b.orderBys),
 		},
@@ -56,11 +68,34 @@ func (b *LocalSort) SerializeToCommand() *pb.Instruction {
 }
 
 func (b *LocalSort) GetMemoryCostInMB(partitionSize int64) int64 {
-	return int64(math.Max(float64(b.memoryInMB), float64(partitionSize)))
+	return int64(b.runMemoryInMB())
+}
+
+func (b *LocalSort) runMemoryInMB() int {
+	if b.memoryInMB <= 0 || b.memoryInMB > MaxLocalSortMemoryInMB {
+		return MaxLocalSortMemoryInMB
+	}
+	return b.memoryInMB
 }
 
-func DoLocalSort(reader io.Reader, writer io.Writer, orderBys []OrderBy, stats *pb.InstructionStat) error {
+// DoLocalSort sorts the rows in runs of about memoryInMB.
+// If all rows fit in one run, the run is sorted and written out directly.
+// Otherwise each run is sorted and spilled to a temporary file under SpillDir,
+// and all the runs are k-way merged to the writer.
+func DoLocalSort(reader io.Reader, writer io.Writer, orderBys []OrderBy, memoryInMB int, stats *pb.InstructionStat) error {
 	var kvs []interface{}
+	var runs []*os.File
+	defer func() {
+		for _, run := range runs {
+			run.Close()
+			os.Remove(run.Name())
+		}
+	}()
+
+	// decoded keys and the slice of pairs take about 3 times the raw row size
+	runSizeLimit := int64(memoryInMB) * 1024 * 1024 / 3
+	var runSize int64
+
 	indexes := getIndexesFromOrderBys(orderBys)
 	err := util.ProcessMessage(reader, func(input []byte) error {
 		if _, keys, err := util.DecodeRowKeys(input, indexes); err != nil {
@@ -68,6 +103,15 @@ func DoLocalSort(reader io.Reader, writer io.Writer, orderBys []OrderBy, stats *
 		} else {
 			stats.InputCounter++
 			kvs = append(kvs, pair{keys: keys, data: input})
+			runSize += int64(len(input))
+		}
+		if runSize >= runSizeLimit {
+			run, err := spillSortedRun(kvs, orderBys)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+			kvs, runSize = nil, 0
 		}
 		return nil
 	})
@@ -75,22 +119,78 @@ func DoLocalSort(reader io.Reader, writer io.Writer, orderBys []OrderBy, stats *
 		fmt.Printf("Sort>Failed to read:%v\n", err)
 		return err
 	}
-	if len(kvs) == 0 {
+
+	if len(runs) == 0 {
+		if len(kvs) == 0 {
+			return nil
+		}
+		sortPairs(kvs, orderBys)
+		for _, kv := range kvs {
+			// println("sorted key", kv.(pair).keys[0].(string))
+			if err := util.WriteMessage(writer, kv.(pair).data); err != nil {
+				return fmt.Errorf("Sort>Failed to write: %v", err)
+			} else {
+				stats.OutputCounter++
+			}
+		}
 		return nil
 	}
+
+	if len(kvs) > 0 {
+		run, err := spillSortedRun(kvs, orderBys)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+		kvs = nil
+	}
+
+	var readers []io.Reader
+	for _, run := range runs {
+		readers = append(readers, bufio.NewReader(run))
+	}
+	mergeStats := &pb.InstructionStat{}
+	err = DoMergeSortedTo(readers, writer, orderBys, mergeStats)
+	stats.OutputCounter += mergeStats.OutputCounter
+	if err != nil {
+		return fmt.Errorf("Sort>Failed to merge %d runs: %v", len(runs), err)
+	}
+	return nil
+}
+
+func sortPairs(kvs []interface{}, orderBys []OrderBy) {
 	timsort.Sort(kvs, func(a, b interface{}) bool {
 		return pairsLessThan(orderBys, a, b)
 	})
+}
+
+// spillSortedRun sorts the pairs and writes them to a temporary file.
+// The returned file is positioned at the beginning for reading.
+func spillSortedRun(kvs []interface{}, orderBys []OrderBy) (*os.File, error) {
+	sortPairs(kvs, orderBys)
 
+	run, err := ioutil.TempFile(SpillDir, "sort-run-")
+	if err != nil {
+		return nil, fmt.Errorf("Sort>Failed to create run file under %s: %v", SpillDir, err)
+	}
+	writer := bufio.NewWriter(run)
 	for _, kv := range kvs {
-		// println("sorted key", kv.(pair).keys[0].(string))
-		if err := util.WriteMessage(writer, kv.(pair).data); err != nil {
-			return fmt.Errorf("Sort>Failed to write: %v", err)
-		} else {
-			stats.OutputCounter++
+		if err = util.WriteMessage(writer, kv.(pair).data); err != nil {
+			break
 		}
 	}
-	return nil
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		_, err = run.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		run.Close()
+		os.Remove(run.Name())
+		return nil, fmt.Errorf("Sort>Failed to spill run to %s: %v", run.Name(), err)
+	}
+	return run, nil
 }
 
 func getIndexesFromOrderBys(orderBys []OrderBy) (indexes []int) {
@@ -0,0 +1,62 @@
+package instruction
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func TestLocalSortSpillToDisk(t *testing.T) {
+
+	var input bytes.Buffer
+	count := 20000
+	for i := 0; i < count; i++ {
+		util.WriteRow(&input, util.Now(), rand.Int63n(1000000), "some padding to make the rows bigger")
+	}
+
+	dir, err := ioutil.TempDir("", "local_sort_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	SpillDir = dir
+
+	var output bytes.Buffer
+	stats := &pb.InstructionStat{}
+	orderBys := []OrderBy{{Index: 1, Order: Descending}}
+	if err := DoLocalSort(&input, &output, orderBys, 1, stats); err != nil {
+		t.Fatalf("Failed to sort: %v", err)
+	}
+
+	if stats.InputCounter != int64(count) || stats.OutputCounter != int64(count) {
+		t.Errorf("Expect %d rows, but read %d and wrote %d", count, stats.InputCounter, stats.OutputCounter)
+	}
+
+	var prev interface{}
+	for n := 0; ; n++ {
+		_, row, err := util.ReadRow(&output)
+		if err == io.EOF {
+			if n != count {
+				t.Errorf("Expect %d sorted rows, but got %d", count, n)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read sorted row %d: %v", n, err)
+		}
+		if prev != nil && util.Compare(prev, row[0]) < 0 {
+			t.Fatalf("Row %d is out of order: %v after %v", n, row[0], prev)
+		}
+		prev = row[0]
+	}
+
+	if fileInfos, _ := ioutil.ReadDir(dir); len(fileInfos) != 0 {
+		t.Errorf("Expect spilled runs to be removed, but found %d files", len(fileInfos))
+	}
+}
@@ -0,0 +1,100 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetRangeSplitPoints() != nil {
+			return NewRangeSplitPoints(
+				int(m.GetRangeSplitPoints().GetShardCount()),
+				toOrderBys(m.GetRangeSplitPoints().GetOrderBys()),
+			)
+		}
+		return nil
+	})
+}
+
+// RangeSplitPoints reads the sampled keys from all input shards,
+// and picks the keys to split the rows into shardCount ranges.
+type RangeSplitPoints struct {
+	shardCount int
+	orderBys   []OrderBy
+}
+
+func NewRangeSplitPoints(shardCount int, orderBys []OrderBy) *RangeSplitPoints {
+	return &RangeSplitPoints{shardCount, orderBys}
+}
+
+func (b *RangeSplitPoints) Name() string {
+	return "RangeSplitPoints"
+}
+
+func (b *RangeSplitPoints) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoRangeSplitPoints(readers, writers[0], b.shardCount, b.orderBys, stats)
+	}
+}
+
+func (b *RangeSplitPoints) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		Name: b.Name(),
+		RangeSplitPoints: &pb.Instruction_RangeSplitPoints{
+			ShardCount: int32(b.shardCount),
+			OrderBys:   getOrderBys(b.orderBys),
+		},
+	}
+}
+
+func (b *RangeSplitPoints) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+// DoRangeSplitPoints sorts the key-only rows from all readers,
+// and writes out shardCount-1 evenly spaced keys in sorted order.
+func DoRangeSplitPoints(readers []io.Reader, writer io.Writer, shardCount int, orderBys []OrderBy, stats *pb.InstructionStat) error {
+	var samples []interface{}
+	for _, reader := range readers {
+		keys, err := readKeyRows(reader, len(orderBys))
+		if err != nil {
+			return fmt.Errorf("RangeSplitPoints>Failed to read samples: %v", err)
+		}
+		stats.InputCounter += int64(len(keys))
+		samples = append(samples, keys...)
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+
+	sortPairs(samples, orderBys)
+	for i := 1; i < shardCount; i++ {
+		keys := samples[i*len(samples)/shardCount].(pair).keys
+		if err := util.WriteRow(writer, 0, keys...); err != nil {
+			return fmt.Errorf("RangeSplitPoints>Failed to write: %v", err)
+		}
+		stats.OutputCounter++
+	}
+	return nil
+}
+
+// readKeyRows reads rows that only have the keys, as written by LocalSample.
+func readKeyRows(reader io.Reader, keyCount int) (keys []interface{}, err error) {
+	for {
+		_, row, err := util.ReadRow(reader)
+		if err == io.EOF {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) < keyCount {
+			return nil, fmt.Errorf("expect %d keys: %v", keyCount, row)
+		}
+		keys = append(keys, pair{keys: row[:keyCount]})
+	}
+}
@@ -0,0 +1,135 @@
+package instruction
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetReadTextSplit() != nil {
+			return NewReadTextSplit()
+		}
+		return nil
+	})
+}
+
+// ReadTextSplit reads the lines in byte ranges of text files.
+// Each input row is a split of (file name, offset, length).
+// A split has all the lines starting within its byte range,
+// so the splits of one file can be read in parallel.
+type ReadTextSplit struct{}
+
+func NewReadTextSplit() *ReadTextSplit {
+	return &ReadTextSplit{}
+}
+
+func (b *ReadTextSplit) Name() string {
+	return "ReadTextSplit"
+}
+
+func (b *ReadTextSplit) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoReadTextSplit(readers[0], writers[0], stats)
+	}
+}
+
+func (b *ReadTextSplit) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		Name:          b.Name(),
+		ReadTextSplit: &pb.Instruction_ReadTextSplit{},
+	}
+}
+
+func (b *ReadTextSplit) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 3
+}
+
+func DoReadTextSplit(reader io.Reader, writer io.Writer, stats *pb.InstructionStat) error {
+	w := bufio.NewWriterSize(writer, util.BUFFER_SIZE)
+	defer w.Flush()
+	return util.ProcessMessage(reader, func(encodedBytes []byte) error {
+		_, row, err := util.DecodeRow(encodedBytes)
+		if err != nil {
+			return fmt.Errorf("Failed to decode byte: %v", err)
+		}
+		if len(row) < 3 {
+			return fmt.Errorf("Expect a split of file name, offset and length, but got %v", row)
+		}
+		stats.InputCounter++
+		fileName := formatField(row[0])
+		offset, _, _, _ := toNumber(row[1])
+		length, _, _, _ := toNumber(row[2])
+		return ReadLinesInSplit(fileName, offset, length, func(line []byte) error {
+			stats.OutputCounter++
+			return util.WriteRow(w, util.Now(), line)
+		})
+	})
+}
+
+// ReadLinesInSplit calls fn for each line starting in [offset, offset+length).
+// The line passed to fn is only valid until fn returns.
+func ReadLinesInSplit(fileName string, offset, length int64, fn func([]byte) error) error {
+	file, err := filesystem.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("Can not open file %s: %v", fileName, err)
+	}
+	defer file.Close()
+
+	// start from the byte before the split, to see whether a line starts at the offset
+	position := offset
+	if offset > 0 {
+		position = offset - 1
+	}
+	if _, err = file.Seek(position, io.SeekStart); err != nil {
+		return fmt.Errorf("Failed to seek %s to %d: %v", fileName, position, err)
+	}
+	reader := bufio.NewReaderSize(file, util.BUFFER_SIZE)
+
+	var line []byte
+	if offset > 0 {
+		// the partial line belongs to the previous split
+		if line, err = readLine(reader, line[:0]); err != nil && err != io.EOF {
+			return fmt.Errorf("Failed to read %s: %v", fileName, err)
+		}
+		position += int64(len(line))
+	}
+
+	end := offset + length
+	for position < end && err == nil {
+		line, err = readLine(reader, line[:0])
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("Failed to read %s: %v", fileName, err)
+		}
+		if len(line) == 0 {
+			break
+		}
+		position += int64(len(line))
+		if e := fn(trimLineEnd(line)); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// readLine appends the next line to buf, including the line end if any.
+func readLine(reader *bufio.Reader, buf []byte) ([]byte, error) {
+	for {
+		fragment, err := reader.ReadSlice('\n')
+		buf = append(buf, fragment...)
+		if err != bufio.ErrBufferFull {
+			return buf, err
+		}
+	}
+}
+
+func trimLineEnd(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte{'\n'})
+	return bytes.TrimSuffix(line, []byte{'\r'})
+}
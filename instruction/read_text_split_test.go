@@ -0,0 +1,38 @@
+package instruction
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadLinesInSplit(t *testing.T) {
+
+	file, err := ioutil.TempFile("", "text_split")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	lines := []string{"a", "", "bcd", "efghijk", "l", "mn\r", "opq"}
+	content := strings.Join(lines, "\n")
+	file.WriteString(content)
+	file.Close()
+
+	for splitSize := int64(1); splitSize <= int64(len(content))+1; splitSize++ {
+		var got []string
+		for offset := int64(0); offset < int64(len(content)); offset += splitSize {
+			err := ReadLinesInSplit(file.Name(), offset, splitSize, func(line []byte) error {
+				got = append(got, string(line))
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Failed to read split %d+%d: %v", offset, splitSize, err)
+			}
+		}
+		if strings.Join(got, "|") != "a||bcd|efghijk|l|mn|opq" {
+			t.Errorf("Split size %d: unexpected lines %q", splitSize, got)
+		}
+	}
+}
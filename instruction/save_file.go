@@ -0,0 +1,291 @@
+package instruction
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetSaveFile() != nil {
+			return NewSaveFile(
+				m.GetSaveFile().GetFormat(),
+				m.GetSaveFile().GetCompression(),
+				m.GetSaveFile().GetPathPattern(),
+				m.GetSaveFile().GetFieldNames(),
+				m.GetSaveFile().GetIsInputPipe(),
+			)
+		}
+		return nil
+	})
+}
+
+// SaveFile writes each shard into one file, named by formatting
+// the path pattern with the shard id.
+// The file is written to a temporary name first, and renamed when all rows are written.
+// The file is compressed in the compression format, or by the file extension if not set.
+// The fieldNames are the names of the fields in the "jsonl" format, or the header line in the "csv" format.
+type SaveFile struct {
+	format      string
+	compression string
+	pathPattern string
+	fieldNames  []string
+	isInputPipe bool
+}
+
+func NewSaveFile(format, compression, pathPattern string, fieldNames []string, isInputPipe bool) *SaveFile {
+	return &SaveFile{format, compression, pathPattern, fieldNames, isInputPipe}
+}
+
+func (b *SaveFile) Name() string {
+	return "SaveFile"
+}
+
+func (b *SaveFile) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		compression := filesystem.Compression(b.compression)
+		filePath := ShardFilePath(b.pathPattern, int(stats.TaskId), compression)
+		if compression == filesystem.NoCompression {
+			compression = filesystem.CompressionOf(filePath)
+		}
+		return DoSaveFile(readers[0], b.format, b.fieldNames, compression, filePath, b.isInputPipe, stats)
+	}
+}
+
+func (b *SaveFile) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		Name: b.Name(),
+		SaveFile: &pb.Instruction_SaveFile{
+			Format:      b.format,
+			Compression: b.compression,
+			PathPattern: b.pathPattern,
+			FieldNames:  b.fieldNames,
+			IsInputPipe: b.isInputPipe,
+		},
+	}
+}
+
+func (b *SaveFile) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 3
+}
+
+// ShardFilePath formats the path pattern with the shard id.
+// A path pattern without any formatting verb is a directory,
+// with files named as part-00000, part-00001, etc., plus the compression extension.
+func ShardFilePath(pathPattern string, shardId int, compression filesystem.Compression) string {
+	if !strings.Contains(pathPattern, "%") {
+		pathPattern = strings.TrimSuffix(pathPattern, "/") + "/part-%05d" + compression.Extension()
+	}
+	return fmt.Sprintf(pathPattern, shardId)
+}
+
+// DoSaveFile writes the rows to the file in the format, "tsv", "csv", or "jsonl".
+// In the "csv" format, the field names are written as the header line if any.
+// In the "jsonl" format, each row is a JSON object of the named fields on one line.
+// A row of one map is written as the JSON object if there are no field names.
+// If the input comes from a pipe, each line is a row of tab-separated fields.
+func DoSaveFile(reader io.Reader, format string, fieldNames []string, compression filesystem.Compression, filePath string, isInputPipe bool, stats *pb.InstructionStat) (err error) {
+	var writeRow func([]interface{}) error
+	var flush func() error
+
+	tempPath := temporaryFilePath(filePath)
+	rawFile, err := filesystem.Create(tempPath)
+	if err != nil {
+		return fmt.Errorf("Failed to create %s: %v", tempPath, err)
+	}
+	file, err := filesystem.NewCompressedWriter(rawFile, compression)
+	if err != nil {
+		rawFile.Close()
+		filesystem.Delete(tempPath)
+		return fmt.Errorf("Failed to compress %s: %v", tempPath, err)
+	}
+	defer func() {
+		if file != nil {
+			file.Close()
+			filesystem.Delete(tempPath)
+		}
+	}()
+
+	switch format {
+	case "tsv", "txt", "":
+		w := bufio.NewWriterSize(file, util.BUFFER_SIZE)
+		writeRow = func(row []interface{}) error {
+			_, err := w.WriteString(strings.Join(formatFields(row), "\t") + "\n")
+			return err
+		}
+		flush = w.Flush
+	case "csv":
+		w := csv.NewWriter(bufio.NewWriterSize(file, util.BUFFER_SIZE))
+		if len(fieldNames) > 0 {
+			if err := w.Write(fieldNames); err != nil {
+				return fmt.Errorf("Failed to write header to %s: %v", tempPath, err)
+			}
+		}
+		writeRow = func(row []interface{}) error {
+			return w.Write(formatFields(row))
+		}
+		flush = func() error {
+			w.Flush()
+			return w.Error()
+		}
+	case "jsonl":
+		w := bufio.NewWriterSize(file, util.BUFFER_SIZE)
+		var buf bytes.Buffer
+		writeRow = func(row []interface{}) error {
+			buf.Reset()
+			if err := encodeJsonObject(&buf, fieldNames, row); err != nil {
+				return err
+			}
+			buf.WriteByte('\n')
+			_, err := w.Write(buf.Bytes())
+			return err
+		}
+		flush = w.Flush
+	default:
+		return fmt.Errorf("Unknown file format %s", format)
+	}
+
+	write := func(row []interface{}) error {
+		stats.InputCounter++
+		if err := writeRow(row); err != nil {
+			return fmt.Errorf("Failed to write %s: %v", tempPath, err)
+		}
+		stats.OutputCounter++
+		return nil
+	}
+	if isInputPipe {
+		err = util.TakeTsv(reader, -1, func(fields []string) error {
+			row := make([]interface{}, len(fields))
+			for i, field := range fields {
+				row[i] = field
+			}
+			return write(row)
+		})
+	} else {
+		err = util.ProcessMessage(reader, func(encodedBytes []byte) error {
+			_, row, err := util.DecodeRow(encodedBytes)
+			if err != nil {
+				return fmt.Errorf("Failed to decode byte: %v", err)
+			}
+			return write(row)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = flush(); err != nil {
+		return fmt.Errorf("Failed to write %s: %v", tempPath, err)
+	}
+	err, file = file.Close(), nil
+	if err != nil {
+		filesystem.Delete(tempPath)
+		return fmt.Errorf("Failed to close %s: %v", tempPath, err)
+	}
+	if err = filesystem.Rename(tempPath, filePath); err != nil {
+		filesystem.Delete(tempPath)
+		return fmt.Errorf("Failed to rename %s to %s: %v", tempPath, filePath, err)
+	}
+	return nil
+}
+
+// temporaryFilePath is a hidden file in the same directory.
+func temporaryFilePath(filePath string) string {
+	dir, name := "", filePath
+	if i := strings.LastIndex(filePath, "/"); i >= 0 {
+		dir, name = filePath[:i+1], filePath[i+1:]
+	}
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return fmt.Sprintf("%s.%s.%d.tmp", dir, name, random.Uint32())
+}
+
+func formatFields(row []interface{}) []string {
+	fields := make([]string, len(row))
+	for i, field := range row {
+		fields[i] = formatField(field)
+	}
+	return fields
+}
+
+// encodeJsonObject writes the fields in the order of the field names.
+// The fields without names are named by their 1-based positions.
+func encodeJsonObject(buf *bytes.Buffer, fieldNames []string, row []interface{}) error {
+	if len(fieldNames) == 0 && len(row) == 1 {
+		if _, isMap := row[0].(map[interface{}]interface{}); isMap {
+			return encodeJsonValue(buf, row[0])
+		}
+	}
+	buf.WriteByte('{')
+	for i, field := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name := fmt.Sprintf("%d", i+1)
+		if i < len(fieldNames) {
+			name = fieldNames[i]
+		}
+		if err := encodeJsonValue(buf, name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := encodeJsonValue(buf, field); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeJsonValue(buf *bytes.Buffer, value interface{}) error {
+	encoded, err := json.Marshal(toJsonValue(value))
+	if err != nil {
+		return fmt.Errorf("Failed to encode %v as JSON: %v", value, err)
+	}
+	buf.Write(encoded)
+	return nil
+}
+
+// toJsonValue converts the decoded msgpack values, e.g., []byte and
+// map[interface{}]interface{}, to the values that encoding/json can encode.
+func toJsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, x := range v {
+			m[formatField(key)] = toJsonValue(x)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, x := range v {
+			list[i] = toJsonValue(x)
+		}
+		return list
+	}
+	return value
+}
+
+func formatField(field interface{}) string {
+	switch v := field.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(field)
+}
@@ -0,0 +1,124 @@
+package instruction
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func TestSaveFile(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "save_file")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var input bytes.Buffer
+	util.WriteRow(&input, 0, "a", 1, 2.5)
+	util.WriteRow(&input, 0, "b,c", 2, nil)
+
+	filePath := ShardFilePath(dir+"/out", 3, filesystem.NoCompression)
+	stats := &pb.InstructionStat{}
+	if err := DoSaveFile(&input, "csv", nil, filesystem.NoCompression, filePath, false, stats); err != nil {
+		t.Fatalf("Failed to save file: %v", err)
+	}
+	if stats.InputCounter != 2 || stats.OutputCounter != 2 {
+		t.Errorf("Expect 2 input and 2 output rows, but got %d and %d", stats.InputCounter, stats.OutputCounter)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "out", "part-00003"))
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+	if expected := "a,1,2.5\n\"b,c\",2,\n"; string(data) != expected {
+		t.Errorf("Expect %q, but got %q", expected, string(data))
+	}
+
+	files, _ := ioutil.ReadDir(filepath.Join(dir, "out"))
+	if len(files) != 1 {
+		t.Errorf("Expect only the saved file, but got %d files", len(files))
+	}
+}
+
+func TestSaveCsvWithHeader(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "save_file")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var input bytes.Buffer
+	util.WriteRow(&input, 0, "a", 1)
+
+	filePath := filepath.Join(dir, "out.csv")
+	if err := DoSaveFile(&input, "csv", []string{"name", "count"}, filesystem.NoCompression, filePath, false, &pb.InstructionStat{}); err != nil {
+		t.Fatalf("Failed to save file: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+	if expected := "name,count\na,1\n"; string(data) != expected {
+		t.Errorf("Expect %q, but got %q", expected, string(data))
+	}
+}
+
+func TestSaveFileFromPipe(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "save_file")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := ShardFilePath(dir+"/part-%d.txt", 0, filesystem.NoCompression)
+	input := strings.NewReader("a\t1\nb\t2\n")
+	if err := DoSaveFile(input, "tsv", nil, filesystem.NoCompression, filePath, true, &pb.InstructionStat{}); err != nil {
+		t.Fatalf("Failed to save file: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "part-0.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+	if expected := "a\t1\nb\t2\n"; string(data) != expected {
+		t.Errorf("Expect %q, but got %q", expected, string(data))
+	}
+}
+
+func TestSaveFileAsJsonLines(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "save_file")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var input bytes.Buffer
+	util.WriteRow(&input, 0, "a", 1, map[string]interface{}{"x": []interface{}{"y", 2}})
+	util.WriteRow(&input, 0, map[string]interface{}{"k": "v"})
+
+	filePath := dir + "/data.jsonl"
+	if err := DoSaveFile(&input, "jsonl", []string{"name", "count"}, filesystem.NoCompression, filePath, false, &pb.InstructionStat{}); err != nil {
+		t.Fatalf("Failed to save file: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+	expected := `{"name":"a","count":1,"3":{"x":["y",2]}}` + "\n" + `{"name":{"k":"v"}}` + "\n"
+	if string(data) != expected {
+		t.Errorf("Expect %q, but got %q", expected, string(data))
+	}
+}
@@ -0,0 +1,108 @@
+package instruction
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func init() {
+	InstructionRunner.Register(func(m *pb.Instruction) Instruction {
+		if m.GetScatterRanges() != nil {
+			return NewScatterRanges(
+				toOrderBys(m.GetScatterRanges().GetOrderBys()),
+			)
+		}
+		return nil
+	})
+}
+
+// ScatterRanges sends each row to the shard whose key range covers it.
+// The first reader has the rows, and the second reader has the split points.
+type ScatterRanges struct {
+	orderBys []OrderBy
+}
+
+func NewScatterRanges(orderBys []OrderBy) *ScatterRanges {
+	return &ScatterRanges{orderBys}
+}
+
+func (b *ScatterRanges) Name() string {
+	return "ScatterRanges"
+}
+
+func (b *ScatterRanges) Function() func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+	return func(readers []io.Reader, writers []io.Writer, stats *pb.InstructionStat) error {
+		return DoScatterRanges(readers[0], readers[1], writers, b.orderBys, stats)
+	}
+}
+
+func (b *ScatterRanges) SerializeToCommand() *pb.Instruction {
+	return &pb.Instruction{
+		Name: b.Name(),
+		ScatterRanges: &pb.Instruction_ScatterRanges{
+			OrderBys: getOrderBys(b.orderBys),
+		},
+	}
+}
+
+func (b *ScatterRanges) GetMemoryCostInMB(partitionSize int64) int64 {
+	return 5
+}
+
+// DoScatterRanges writes row to writers[x], where x is the number of
+// split points not greater than the row keys.
+// The split points are computed from a sample of the same rows, so the rows
+// are first spooled to a temporary file under SpillDir. Otherwise the
+// sampling could be blocked by the rows not yet consumed here.
+func DoScatterRanges(reader, splitPointsReader io.Reader, writers []io.Writer, orderBys []OrderBy, stats *pb.InstructionStat) error {
+	spool, err := ioutil.TempFile(SpillDir, "scatter-ranges-")
+	if err != nil {
+		return fmt.Errorf("ScatterRanges>Failed to create spool file under %s: %v", SpillDir, err)
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+	spoolWriter := bufio.NewWriter(spool)
+	if _, err = io.Copy(spoolWriter, reader); err == nil {
+		err = spoolWriter.Flush()
+	}
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return fmt.Errorf("ScatterRanges>Failed to spool rows to %s: %v", spool.Name(), err)
+	}
+
+	splitPoints, err := readKeyRows(splitPointsReader, len(orderBys))
+	if err != nil {
+		return fmt.Errorf("ScatterRanges>Failed to read split points: %v", err)
+	}
+	if len(splitPoints) >= len(writers) {
+		splitPoints = splitPoints[:len(writers)-1]
+	}
+
+	indexes := getIndexesFromOrderBys(orderBys)
+	return util.ProcessMessage(bufio.NewReader(spool), func(data []byte) error {
+		_, keys, err := util.DecodeRowKeys(data, indexes)
+		if err != nil {
+			return fmt.Errorf("ScatterRanges>Failed to find keys on %v: %v", indexes, err)
+		}
+		stats.InputCounter++
+		p := pair{keys: keys}
+		x := sort.Search(len(splitPoints), func(i int) bool {
+			return pairsLessThan(orderBys, p, splitPoints[i])
+		})
+		if err = util.WriteMessage(writers[x], data); err == nil {
+			stats.OutputCounter++
+		}
+		return err
+	})
+}
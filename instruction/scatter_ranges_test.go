@@ -0,0 +1,87 @@
+package instruction
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/gleamold/pb"
+	"github.com/chrislusf/gleamold/util"
+)
+
+func TestScatterRanges(t *testing.T) {
+
+	var input bytes.Buffer
+	count := 10000
+	for i := 0; i < count; i++ {
+		util.WriteRow(&input, util.Now(), rand.Int63n(1000000), "value")
+	}
+
+	dir, err := ioutil.TempDir("", "scatter_ranges_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	SpillDir = dir
+
+	orderBys := []OrderBy{{Index: 1, Order: Descending}}
+	shardCount := 4
+
+	var samples, splitPoints bytes.Buffer
+	if err := DoLocalSample(bytes.NewReader(input.Bytes()), &samples, 500, orderBys, &pb.InstructionStat{}); err != nil {
+		t.Fatalf("Failed to sample: %v", err)
+	}
+	stats := &pb.InstructionStat{}
+	if err := DoRangeSplitPoints([]io.Reader{&samples}, &splitPoints, shardCount, orderBys, stats); err != nil {
+		t.Fatalf("Failed to find split points: %v", err)
+	}
+	if stats.InputCounter != 500 || stats.OutputCounter != int64(shardCount-1) {
+		t.Errorf("Expect 500 samples and %d split points, but got %d and %d", shardCount-1, stats.InputCounter, stats.OutputCounter)
+	}
+
+	outputs := make([]bytes.Buffer, shardCount)
+	var writers []io.Writer
+	for i := range outputs {
+		writers = append(writers, &outputs[i])
+	}
+	stats = &pb.InstructionStat{}
+	if err := DoScatterRanges(&input, &splitPoints, writers, orderBys, stats); err != nil {
+		t.Fatalf("Failed to scatter: %v", err)
+	}
+	if stats.OutputCounter != int64(count) {
+		t.Errorf("Expect %d rows, but wrote %d", count, stats.OutputCounter)
+	}
+
+	// every row in a shard should be no less than the rows in the next shard
+	var prevMin interface{}
+	for i := range outputs {
+		var max, min interface{}
+		for n := 0; ; n++ {
+			_, row, err := util.ReadRow(&outputs[i])
+			if err == io.EOF {
+				if n == 0 {
+					t.Errorf("Shard %d is empty", i)
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read shard %d: %v", i, err)
+			}
+			if max == nil || util.Compare(row[0], max) > 0 {
+				max = row[0]
+			}
+			if min == nil || util.Compare(row[0], min) < 0 {
+				min = row[0]
+			}
+		}
+		if prevMin != nil && max != nil && util.Compare(max, prevMin) > 0 {
+			t.Errorf("Shard %d has %v, which is bigger than %v in shard %d", i, max, prevMin, i-1)
+		}
+		if min != nil {
+			prevMin = min
+		}
+	}
+}
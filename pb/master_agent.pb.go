@@ -939,10 +939,11 @@ func (m *ExecutionStat) GetStats() []*InstructionStat {
 }
 
 type InstructionStat struct {
-	StepId        int32 `protobuf:"varint,1,opt,name=stepId" json:"stepId,omitempty"`
-	TaskId        int32 `protobuf:"varint,2,opt,name=taskId" json:"taskId,omitempty"`
-	InputCounter  int64 `protobuf:"varint,3,opt,name=inputCounter" json:"inputCounter,omitempty"`
-	OutputCounter int64 `protobuf:"varint,4,opt,name=outputCounter" json:"outputCounter,omitempty"`
+	StepId        int32                      `protobuf:"varint,1,opt,name=stepId" json:"stepId,omitempty"`
+	TaskId        int32                      `protobuf:"varint,2,opt,name=taskId" json:"taskId,omitempty"`
+	InputCounter  int64                      `protobuf:"varint,3,opt,name=inputCounter" json:"inputCounter,omitempty"`
+	OutputCounter int64                      `protobuf:"varint,4,opt,name=outputCounter" json:"outputCounter,omitempty"`
+	Counters      []*InstructionStat_Counter `protobuf:"bytes,5,rep,name=counters" json:"counters,omitempty"`
 }
 
 func (m *InstructionStat) Reset()                    { *m = InstructionStat{} }
@@ -978,6 +979,37 @@ func (m *InstructionStat) GetOutputCounter() int64 {
 	return 0
 }
 
+func (m *InstructionStat) GetCounters() []*InstructionStat_Counter {
+	if m != nil {
+		return m.Counters
+	}
+	return nil
+}
+
+type InstructionStat_Counter struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value int64  `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *InstructionStat_Counter) Reset()                    { *m = InstructionStat_Counter{} }
+func (m *InstructionStat_Counter) String() string            { return proto.CompactTextString(m) }
+func (*InstructionStat_Counter) ProtoMessage()               {}
+func (*InstructionStat_Counter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15, 0} }
+
+func (m *InstructionStat_Counter) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InstructionStat_Counter) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
 type ControlMessage struct {
 	IsOnDiskIO   bool          `protobuf:"varint,1,opt,name=isOnDiskIO" json:"isOnDiskIO,omitempty"`
 	ReadRequest  *ReadRequest  `protobuf:"bytes,2,opt,name=readRequest" json:"readRequest,omitempty"`
@@ -1176,6 +1208,12 @@ type Instruction struct {
 	MergeSortedTo            *Instruction_MergeSortedTo            `protobuf:"bytes,19,opt,name=mergeSortedTo" json:"mergeSortedTo,omitempty"`
 	MergeTo                  *Instruction_MergeTo                  `protobuf:"bytes,22,opt,name=mergeTo" json:"mergeTo,omitempty"`
 	LocalDistinct            *Instruction_LocalDistinct            `protobuf:"bytes,21,opt,name=localDistinct" json:"localDistinct,omitempty"`
+	LocalSample              *Instruction_LocalSample              `protobuf:"bytes,23,opt,name=localSample" json:"localSample,omitempty"`
+	RangeSplitPoints         *Instruction_RangeSplitPoints         `protobuf:"bytes,24,opt,name=rangeSplitPoints" json:"rangeSplitPoints,omitempty"`
+	ScatterRanges            *Instruction_ScatterRanges            `protobuf:"bytes,25,opt,name=scatterRanges" json:"scatterRanges,omitempty"`
+	LocalAggregate           *Instruction_LocalAggregate           `protobuf:"bytes,26,opt,name=localAggregate" json:"localAggregate,omitempty"`
+	SaveFile                 *Instruction_SaveFile                 `protobuf:"bytes,27,opt,name=saveFile" json:"saveFile,omitempty"`
+	ReadTextSplit            *Instruction_ReadTextSplit            `protobuf:"bytes,28,opt,name=readTextSplit" json:"readTextSplit,omitempty"`
 }
 
 func (m *Instruction) Reset()                    { *m = Instruction{} }
@@ -1330,6 +1368,48 @@ func (m *Instruction) GetLocalDistinct() *Instruction_LocalDistinct {
 	return nil
 }
 
+func (m *Instruction) GetLocalSample() *Instruction_LocalSample {
+	if m != nil {
+		return m.LocalSample
+	}
+	return nil
+}
+
+func (m *Instruction) GetRangeSplitPoints() *Instruction_RangeSplitPoints {
+	if m != nil {
+		return m.RangeSplitPoints
+	}
+	return nil
+}
+
+func (m *Instruction) GetScatterRanges() *Instruction_ScatterRanges {
+	if m != nil {
+		return m.ScatterRanges
+	}
+	return nil
+}
+
+func (m *Instruction) GetLocalAggregate() *Instruction_LocalAggregate {
+	if m != nil {
+		return m.LocalAggregate
+	}
+	return nil
+}
+
+func (m *Instruction) GetSaveFile() *Instruction_SaveFile {
+	if m != nil {
+		return m.SaveFile
+	}
+	return nil
+}
+
+func (m *Instruction) GetReadTextSplit() *Instruction_ReadTextSplit {
+	if m != nil {
+		return m.ReadTextSplit
+	}
+	return nil
+}
+
 type Instruction_JoinPartitionedSorted struct {
 	Indexes          []int32 `protobuf:"varint,1,rep,packed,name=indexes" json:"indexes,omitempty"`
 	IsLeftOuterJoin  bool    `protobuf:"varint,2,opt,name=isLeftOuterJoin" json:"isLeftOuterJoin,omitempty"`
@@ -1598,6 +1678,178 @@ func (m *Instruction_LocalDistinct) GetOrderBys() []*OrderBy {
 	return nil
 }
 
+type Instruction_LocalSample struct {
+	N        int32      `protobuf:"varint,1,opt,name=n" json:"n,omitempty"`
+	OrderBys []*OrderBy `protobuf:"bytes,2,rep,name=orderBys" json:"orderBys,omitempty"`
+}
+
+func (m *Instruction_LocalSample) Reset()                    { *m = Instruction_LocalSample{} }
+func (m *Instruction_LocalSample) String() string            { return proto.CompactTextString(m) }
+func (*Instruction_LocalSample) ProtoMessage()               {}
+func (*Instruction_LocalSample) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22, 15} }
+
+func (m *Instruction_LocalSample) GetN() int32 {
+	if m != nil {
+		return m.N
+	}
+	return 0
+}
+
+func (m *Instruction_LocalSample) GetOrderBys() []*OrderBy {
+	if m != nil {
+		return m.OrderBys
+	}
+	return nil
+}
+
+type Instruction_RangeSplitPoints struct {
+	ShardCount int32      `protobuf:"varint,1,opt,name=shardCount" json:"shardCount,omitempty"`
+	OrderBys   []*OrderBy `protobuf:"bytes,2,rep,name=orderBys" json:"orderBys,omitempty"`
+}
+
+func (m *Instruction_RangeSplitPoints) Reset()         { *m = Instruction_RangeSplitPoints{} }
+func (m *Instruction_RangeSplitPoints) String() string { return proto.CompactTextString(m) }
+func (*Instruction_RangeSplitPoints) ProtoMessage()    {}
+func (*Instruction_RangeSplitPoints) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{22, 16}
+}
+
+func (m *Instruction_RangeSplitPoints) GetShardCount() int32 {
+	if m != nil {
+		return m.ShardCount
+	}
+	return 0
+}
+
+func (m *Instruction_RangeSplitPoints) GetOrderBys() []*OrderBy {
+	if m != nil {
+		return m.OrderBys
+	}
+	return nil
+}
+
+type Instruction_ScatterRanges struct {
+	OrderBys []*OrderBy `protobuf:"bytes,1,rep,name=orderBys" json:"orderBys,omitempty"`
+}
+
+func (m *Instruction_ScatterRanges) Reset()                    { *m = Instruction_ScatterRanges{} }
+func (m *Instruction_ScatterRanges) String() string            { return proto.CompactTextString(m) }
+func (*Instruction_ScatterRanges) ProtoMessage()               {}
+func (*Instruction_ScatterRanges) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22, 17} }
+
+func (m *Instruction_ScatterRanges) GetOrderBys() []*OrderBy {
+	if m != nil {
+		return m.OrderBys
+	}
+	return nil
+}
+
+type Instruction_Aggregator struct {
+	Type  int32 `protobuf:"varint,1,opt,name=type" json:"type,omitempty"`
+	Index int32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+}
+
+func (m *Instruction_Aggregator) Reset()                    { *m = Instruction_Aggregator{} }
+func (m *Instruction_Aggregator) String() string            { return proto.CompactTextString(m) }
+func (*Instruction_Aggregator) ProtoMessage()               {}
+func (*Instruction_Aggregator) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22, 18} }
+
+func (m *Instruction_Aggregator) GetType() int32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *Instruction_Aggregator) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type Instruction_LocalAggregate struct {
+	Indexes     []int32                   `protobuf:"varint,1,rep,packed,name=indexes" json:"indexes,omitempty"`
+	Aggregators []*Instruction_Aggregator `protobuf:"bytes,2,rep,name=aggregators" json:"aggregators,omitempty"`
+}
+
+func (m *Instruction_LocalAggregate) Reset()         { *m = Instruction_LocalAggregate{} }
+func (m *Instruction_LocalAggregate) String() string { return proto.CompactTextString(m) }
+func (*Instruction_LocalAggregate) ProtoMessage()    {}
+func (*Instruction_LocalAggregate) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{22, 19}
+}
+
+func (m *Instruction_LocalAggregate) GetIndexes() []int32 {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+func (m *Instruction_LocalAggregate) GetAggregators() []*Instruction_Aggregator {
+	if m != nil {
+		return m.Aggregators
+	}
+	return nil
+}
+
+type Instruction_SaveFile struct {
+	Format      string   `protobuf:"bytes,1,opt,name=format" json:"format,omitempty"`
+	PathPattern string   `protobuf:"bytes,2,opt,name=pathPattern" json:"pathPattern,omitempty"`
+	IsInputPipe bool     `protobuf:"varint,3,opt,name=isInputPipe" json:"isInputPipe,omitempty"`
+	Compression string   `protobuf:"bytes,4,opt,name=compression" json:"compression,omitempty"`
+	FieldNames  []string `protobuf:"bytes,5,rep,name=fieldNames" json:"fieldNames,omitempty"`
+}
+
+func (m *Instruction_SaveFile) Reset()                    { *m = Instruction_SaveFile{} }
+func (m *Instruction_SaveFile) String() string            { return proto.CompactTextString(m) }
+func (*Instruction_SaveFile) ProtoMessage()               {}
+func (*Instruction_SaveFile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22, 20} }
+
+func (m *Instruction_SaveFile) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *Instruction_SaveFile) GetPathPattern() string {
+	if m != nil {
+		return m.PathPattern
+	}
+	return ""
+}
+
+func (m *Instruction_SaveFile) GetIsInputPipe() bool {
+	if m != nil {
+		return m.IsInputPipe
+	}
+	return false
+}
+
+func (m *Instruction_SaveFile) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *Instruction_SaveFile) GetFieldNames() []string {
+	if m != nil {
+		return m.FieldNames
+	}
+	return nil
+}
+
+type Instruction_ReadTextSplit struct {
+}
+
+func (m *Instruction_ReadTextSplit) Reset()                    { *m = Instruction_ReadTextSplit{} }
+func (m *Instruction_ReadTextSplit) String() string            { return proto.CompactTextString(m) }
+func (*Instruction_ReadTextSplit) ProtoMessage()               {}
+func (*Instruction_ReadTextSplit) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22, 21} }
+
 type OrderBy struct {
 	Index int32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
 	Order int32 `protobuf:"varint,2,opt,name=order" json:"order,omitempty"`
@@ -1727,6 +1979,7 @@ func init() {
 	proto.RegisterType((*ExecutionResponse)(nil), "pb.ExecutionResponse")
 	proto.RegisterType((*ExecutionStat)(nil), "pb.ExecutionStat")
 	proto.RegisterType((*InstructionStat)(nil), "pb.InstructionStat")
+	proto.RegisterType((*InstructionStat_Counter)(nil), "pb.InstructionStat.Counter")
 	proto.RegisterType((*ControlMessage)(nil), "pb.ControlMessage")
 	proto.RegisterType((*DeleteDatasetShardRequest)(nil), "pb.DeleteDatasetShardRequest")
 	proto.RegisterType((*DeleteDatasetShardResponse)(nil), "pb.DeleteDatasetShardResponse")
@@ -1749,6 +2002,13 @@ func init() {
 	proto.RegisterType((*Instruction_MergeSortedTo)(nil), "pb.Instruction.MergeSortedTo")
 	proto.RegisterType((*Instruction_MergeTo)(nil), "pb.Instruction.MergeTo")
 	proto.RegisterType((*Instruction_LocalDistinct)(nil), "pb.Instruction.LocalDistinct")
+	proto.RegisterType((*Instruction_LocalSample)(nil), "pb.Instruction.LocalSample")
+	proto.RegisterType((*Instruction_RangeSplitPoints)(nil), "pb.Instruction.RangeSplitPoints")
+	proto.RegisterType((*Instruction_ScatterRanges)(nil), "pb.Instruction.ScatterRanges")
+	proto.RegisterType((*Instruction_Aggregator)(nil), "pb.Instruction.Aggregator")
+	proto.RegisterType((*Instruction_LocalAggregate)(nil), "pb.Instruction.LocalAggregate")
+	proto.RegisterType((*Instruction_SaveFile)(nil), "pb.Instruction.SaveFile")
+	proto.RegisterType((*Instruction_ReadTextSplit)(nil), "pb.Instruction.ReadTextSplit")
 	proto.RegisterType((*OrderBy)(nil), "pb.OrderBy")
 	proto.RegisterType((*DatasetShard)(nil), "pb.DatasetShard")
 	proto.RegisterType((*DatasetShardLocation)(nil), "pb.DatasetShardLocation")
@@ -2223,152 +2483,171 @@ var _GleamoldAgent_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("master_agent.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2337 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x59, 0xcd, 0x6e, 0xdc, 0xc8,
-	0x11, 0x5e, 0xce, 0x8c, 0x46, 0x33, 0x35, 0xa3, 0xbf, 0x96, 0x6c, 0x73, 0x99, 0x5d, 0x5b, 0x4b,
-	0x38, 0x5e, 0x25, 0xc1, 0x6a, 0x6d, 0xad, 0x83, 0x0d, 0x9c, 0x20, 0x88, 0x2c, 0xf9, 0x47, 0xde,
-	0x91, 0x65, 0xb4, 0x04, 0x6c, 0x7e, 0x0e, 0x02, 0x35, 0x6c, 0x4b, 0x5c, 0x8d, 0xc8, 0x09, 0xbb,
-	0xc7, 0xb6, 0xf2, 0x02, 0x39, 0x04, 0x39, 0x04, 0xc8, 0x25, 0x97, 0x20, 0x0f, 0x11, 0xe4, 0xb2,
-	0x4f, 0x90, 0x53, 0xf2, 0x10, 0xc9, 0x23, 0xe4, 0x1e, 0x54, 0x75, 0x93, 0x6c, 0x72, 0x38, 0xb2,
-	0xf6, 0xc6, 0xae, 0xfa, 0xaa, 0xba, 0xaa, 0xba, 0xaa, 0xbb, 0xba, 0x09, 0xec, 0x22, 0x90, 0x4a,
-	0xa4, 0xc7, 0xc1, 0xa9, 0x88, 0xd5, 0xe6, 0x38, 0x4d, 0x54, 0xc2, 0x1a, 0xe3, 0x13, 0xff, 0x5f,
-	0x0e, 0x2c, 0xee, 0x24, 0x17, 0xe3, 0x89, 0x12, 0x5c, 0xfc, 0x76, 0x22, 0xa4, 0x62, 0x77, 0xa0,
-	0x17, 0x06, 0x2a, 0x38, 0x1e, 0x8a, 0x58, 0x89, 0xd4, 0x75, 0xd6, 0x9d, 0x8d, 0x2e, 0x07, 0x24,
-	0xed, 0x10, 0x85, 0xfd, 0x02, 0x56, 0x86, 0x5a, 0xe4, 0x38, 0x15, 0x32, 0x99, 0xa4, 0x43, 0x21,
-	0xdd, 0xc6, 0x7a, 0x73, 0xa3, 0xb7, 0xb5, 0xba, 0x39, 0x3e, 0xd9, 0xcc, 0xf5, 0x69, 0x1e, 0x5f,
-	0x1e, 0x96, 0x09, 0x92, 0x79, 0xd0, 0x99, 0x48, 0x91, 0xc6, 0xc1, 0x85, 0x70, 0x9b, 0xa4, 0x3f,
-	0x1f, 0x23, 0xef, 0x2c, 0x91, 0x8a, 0x78, 0x2d, 0xcd, 0xcb, 0xc6, 0xcc, 0x87, 0xfe, 0xeb, 0x51,
-	0xf2, 0xf6, 0x79, 0x20, 0xcf, 0x76, 0x92, 0x50, 0xb8, 0x73, 0xeb, 0xce, 0xc6, 0x02, 0x2f, 0xd1,
-	0xfc, 0x6f, 0x1d, 0x58, 0xaa, 0x58, 0xc0, 0xbe, 0x07, 0xdd, 0xe1, 0x78, 0x72, 0x3c, 0x4c, 0x26,
-	0xb1, 0x22, 0x87, 0xe6, 0x78, 0x67, 0x38, 0x9e, 0xec, 0xe0, 0x38, 0x63, 0x8e, 0xc4, 0x1b, 0x31,
-	0x72, 0x1b, 0x39, 0x73, 0x80, 0x63, 0x64, 0x9e, 0xe6, 0x92, 0x4d, 0xcd, 0x3c, 0xb5, 0x24, 0x4f,
-	0x73, 0xc9, 0x56, 0xce, 0xcc, 0x25, 0x2f, 0xc4, 0x45, 0x92, 0x5e, 0x1e, 0x5f, 0x9c, 0x90, 0xa1,
-	0x4d, 0xde, 0xd1, 0x84, 0xfd, 0x13, 0x76, 0x0b, 0xe6, 0xc3, 0x48, 0x9e, 0x23, 0xab, 0x4d, 0xac,
-	0x36, 0x0e, 0xf7, 0x4f, 0xfc, 0x01, 0xf4, 0x77, 0x03, 0x15, 0xe4, 0x96, 0x6f, 0x40, 0x67, 0x94,
-	0x0c, 0x03, 0x15, 0x25, 0x31, 0x19, 0xde, 0xdb, 0xea, 0x63, 0x88, 0x07, 0x86, 0xc6, 0x73, 0x2e,
-	0x63, 0xd0, 0x92, 0xd1, 0xef, 0x04, 0x79, 0xd0, 0xe4, 0xf4, 0xed, 0x9f, 0x43, 0x27, 0x43, 0xbe,
-	0x7f, 0x59, 0x19, 0xb4, 0xd2, 0x60, 0x78, 0x4e, 0x0a, 0xba, 0x9c, 0xbe, 0xd9, 0x4d, 0x68, 0x4b,
-	0x91, 0xbe, 0x11, 0xa9, 0x59, 0x26, 0x33, 0x42, 0xec, 0x38, 0x49, 0x95, 0x71, 0x9a, 0xbe, 0xfd,
-	0x08, 0x60, 0x7b, 0x94, 0x9b, 0x73, 0x7d, 0xc3, 0x1f, 0x40, 0x37, 0xd0, 0x72, 0x22, 0xa4, 0xc9,
-	0x67, 0xa4, 0x51, 0x81, 0xf2, 0x77, 0x61, 0xb9, 0x98, 0x8a, 0x0b, 0x39, 0x19, 0x29, 0x76, 0x1f,
-	0x7a, 0x41, 0x4e, 0x93, 0xae, 0x43, 0xf9, 0xb8, 0x88, 0x8a, 0x2c, 0xa8, 0x0d, 0xf1, 0xff, 0xe2,
-	0x40, 0xf7, 0xb9, 0x08, 0x52, 0x75, 0x22, 0x02, 0xf5, 0x1d, 0x0c, 0xfe, 0x1c, 0x3a, 0x59, 0xde,
-	0x5f, 0x65, 0x6f, 0x0e, 0x2a, 0x7b, 0xd8, 0xbc, 0x96, 0x87, 0xf3, 0x30, 0xf7, 0xe4, 0x62, 0xac,
-	0x2e, 0xfd, 0x50, 0x27, 0xc4, 0xc0, 0x5a, 0x66, 0x2a, 0x0d, 0xbd, 0x7e, 0xf4, 0x5d, 0x32, 0xbd,
-	0x71, 0xa5, 0xe9, 0x37, 0xa1, 0x9d, 0xc4, 0xbb, 0x91, 0x3c, 0x27, 0x33, 0x3a, 0xdc, 0x8c, 0xfc,
-	0x7f, 0xf7, 0x61, 0xf5, 0xe9, 0x28, 0x79, 0xfb, 0xe4, 0x9d, 0x18, 0x4e, 0x10, 0x79, 0xa8, 0x02,
-	0x35, 0x91, 0x6c, 0x1b, 0x40, 0x2a, 0x31, 0x7e, 0x96, 0x26, 0x93, 0x71, 0x16, 0xd3, 0x4f, 0x50,
-	0x77, 0x0d, 0x78, 0xf3, 0x30, 0x43, 0x72, 0x4b, 0x08, 0x55, 0xa8, 0x40, 0x9e, 0x1b, 0x15, 0x8d,
-	0xab, 0x55, 0x1c, 0x65, 0x48, 0x6e, 0x09, 0xb1, 0x9f, 0x42, 0x07, 0xf3, 0x54, 0x0a, 0x25, 0xdd,
-	0x26, 0x29, 0xb8, 0x33, 0x4b, 0xc1, 0xae, 0xc6, 0xf1, 0x5c, 0x80, 0xbd, 0x80, 0x05, 0xf3, 0x7d,
-	0x78, 0x16, 0xa4, 0xa1, 0x74, 0x5b, 0xa4, 0xe1, 0xee, 0x7b, 0x34, 0x10, 0x98, 0x97, 0x45, 0xd9,
-	0x16, 0xcc, 0xa1, 0x59, 0xd2, 0x9d, 0x23, 0x1d, 0x1f, 0x5d, 0xe5, 0x06, 0xd7, 0x50, 0x94, 0xc1,
-	0x68, 0x48, 0xb7, 0x7d, 0xb5, 0x0c, 0x46, 0x8f, 0x6b, 0x28, 0x5b, 0x84, 0x46, 0x14, 0xba, 0xf3,
-	0xb4, 0xbb, 0x35, 0xa2, 0x90, 0x3d, 0x82, 0x76, 0x98, 0x46, 0x58, 0x86, 0x1d, 0x5a, 0x5e, 0x7f,
-	0xa6, 0xf1, 0x84, 0xda, 0x8b, 0x5f, 0x27, 0xdc, 0x48, 0x78, 0x9b, 0xd0, 0x42, 0x73, 0xa8, 0x94,
-	0x95, 0x18, 0xef, 0x85, 0x66, 0x03, 0x34, 0x23, 0x33, 0x97, 0xde, 0xf7, 0x1a, 0x51, 0xe8, 0xfd,
-	0xdd, 0x81, 0x16, 0xda, 0x62, 0x18, 0x4e, 0xc6, 0xc8, 0x33, 0xaf, 0x61, 0x65, 0xde, 0x47, 0xd0,
-	0x1d, 0x07, 0xa9, 0x88, 0xd5, 0x5e, 0xa8, 0x97, 0x66, 0x8e, 0x17, 0x04, 0xe6, 0xc2, 0x3c, 0xc6,
-	0x60, 0xcf, 0x04, 0x7d, 0x8e, 0x67, 0x43, 0x76, 0x0f, 0x16, 0xa3, 0x78, 0x3c, 0x51, 0x26, 0xd8,
-	0x7b, 0x21, 0x45, 0x74, 0x8e, 0x57, 0xa8, 0x6c, 0x03, 0x96, 0x92, 0x89, 0x2a, 0x01, 0xdb, 0x64,
-	0x50, 0x95, 0xec, 0xfd, 0x0a, 0xe6, 0xcd, 0x60, 0xca, 0xf0, 0xc2, 0xf3, 0x46, 0xc9, 0xf3, 0x7b,
-	0xb0, 0x98, 0x8a, 0x20, 0x8c, 0xe2, 0xd3, 0x43, 0x22, 0x64, 0x1e, 0x54, 0xa8, 0xde, 0xcf, 0x74,
-	0x09, 0x66, 0x69, 0x80, 0x4e, 0x87, 0xb9, 0x39, 0x7a, 0x9a, 0x82, 0x30, 0x15, 0xcf, 0x1d, 0xe8,
-	0xe6, 0x85, 0x81, 0x11, 0x91, 0x66, 0x2e, 0x47, 0x47, 0xc4, 0x0c, 0xcb, 0x91, 0x6c, 0x54, 0x22,
-	0xe9, 0xfd, 0xa7, 0x09, 0xdd, 0xbc, 0x36, 0xae, 0xd0, 0x62, 0x45, 0xbc, 0x51, 0x8e, 0xf8, 0x26,
-	0xcc, 0xa7, 0xfa, 0x80, 0x37, 0x3b, 0xd0, 0x1a, 0xe6, 0x50, 0x9e, 0x3f, 0xe6, 0xf0, 0xe7, 0x19,
-	0x88, 0x6d, 0x02, 0x14, 0x7b, 0x25, 0xed, 0xf3, 0xd3, 0xbb, 0xa9, 0x85, 0x60, 0x5f, 0x01, 0x88,
-	0x4c, 0x59, 0x56, 0x1f, 0x3f, 0x7a, 0x6f, 0x99, 0x5b, 0x06, 0x58, 0xe2, 0xde, 0xff, 0x1c, 0xe8,
-	0xe6, 0x1c, 0xf6, 0x31, 0x6e, 0x42, 0x41, 0xaa, 0x8e, 0x55, 0x64, 0x36, 0xbe, 0x26, 0xef, 0x12,
-	0xe5, 0x28, 0xba, 0xa0, 0xc3, 0x5d, 0xaa, 0x64, 0xac, 0xb9, 0xfa, 0xf4, 0xeb, 0x20, 0x81, 0x98,
-	0x77, 0xa0, 0x27, 0x2f, 0xa5, 0x12, 0x17, 0x9a, 0x8d, 0xae, 0x3b, 0x1c, 0x34, 0x29, 0x93, 0xc6,
-	0xd6, 0x43, 0xb3, 0x5b, 0xc4, 0xa6, 0x5e, 0x84, 0x98, 0x6b, 0x30, 0x27, 0xd2, 0x34, 0x49, 0xe9,
-	0xfc, 0xee, 0x73, 0x3d, 0x40, 0x9d, 0x3a, 0xfb, 0x8e, 0xcf, 0x02, 0x79, 0x46, 0x09, 0xd9, 0xe7,
-	0xa0, 0x49, 0xd8, 0x86, 0xb0, 0x2f, 0x61, 0x41, 0xd8, 0x1e, 0x53, 0x25, 0xf7, 0xb6, 0x56, 0x4a,
-	0x11, 0x47, 0x06, 0x2f, 0xe3, 0xbc, 0x7f, 0x3a, 0x00, 0x45, 0x09, 0x97, 0xda, 0x24, 0xe7, 0x8a,
-	0x36, 0xa9, 0x51, 0x69, 0x93, 0x6e, 0x67, 0x6b, 0x11, 0x9c, 0x8c, 0xb2, 0x06, 0xcb, 0xa2, 0xb0,
-	0x4f, 0x61, 0xa9, 0x18, 0x69, 0x27, 0x74, 0xa7, 0xb5, 0x58, 0x90, 0xc9, 0x91, 0x72, 0xe4, 0xe7,
-	0xae, 0x8c, 0x7c, 0xbb, 0x1c, 0x79, 0xff, 0x8f, 0x0e, 0xac, 0x3e, 0x8d, 0x46, 0xc5, 0xe9, 0x66,
-	0x12, 0xab, 0xee, 0x00, 0x5b, 0x86, 0x66, 0x18, 0xa5, 0xc6, 0x0f, 0xfc, 0x44, 0x14, 0xd9, 0xd5,
-	0xa4, 0x3d, 0x90, 0xbe, 0xa7, 0xba, 0xbf, 0xd6, 0x74, 0xf7, 0x87, 0x05, 0x30, 0x4c, 0x62, 0x25,
-	0x62, 0x65, 0xd6, 0x2c, 0x1b, 0xfa, 0x03, 0x58, 0x2b, 0x9b, 0x23, 0xc7, 0x49, 0x2c, 0x05, 0xbb,
-	0x0b, 0x0b, 0xc1, 0x08, 0x2b, 0xfe, 0xf2, 0xc9, 0xbb, 0x48, 0x2a, 0x49, 0x86, 0x75, 0x78, 0x99,
-	0x88, 0x55, 0x9d, 0xe8, 0xd6, 0xa8, 0xc3, 0x1b, 0xc9, 0xb9, 0xff, 0x27, 0x07, 0x96, 0xab, 0xc5,
-	0xc3, 0x1e, 0xe1, 0xae, 0x26, 0x55, 0x3a, 0x19, 0xd2, 0x8a, 0x0a, 0x65, 0x1a, 0x09, 0x86, 0x0b,
-	0xbf, 0x57, 0xe2, 0xf0, 0x0a, 0xb2, 0x26, 0x04, 0x76, 0x9b, 0xd1, 0xbc, 0x46, 0x9b, 0xe1, 0xff,
-	0xc3, 0x81, 0x15, 0xcb, 0x26, 0xe3, 0x1f, 0x1e, 0xf9, 0x94, 0x9a, 0x64, 0x4c, 0x9f, 0x9b, 0x51,
-	0x91, 0xdb, 0x0d, 0x3b, 0xb7, 0x6f, 0x83, 0x55, 0x1c, 0x35, 0xe5, 0x62, 0x52, 0xf2, 0xa8, 0xae,
-	0x5a, 0xa6, 0xd2, 0x7e, 0xee, 0x7a, 0x69, 0xef, 0xa7, 0xb0, 0x50, 0xe2, 0x4f, 0xad, 0xb4, 0x53,
-	0xb3, 0xd2, 0x75, 0xc7, 0xd1, 0x0f, 0xf0, 0xac, 0x0d, 0xf2, 0x2e, 0x61, 0xb5, 0x1a, 0x77, 0x9c,
-	0x5b, 0x23, 0xfc, 0x3f, 0x38, 0xb0, 0x54, 0x61, 0xcd, 0x3c, 0x22, 0x6f, 0x42, 0x5b, 0x6f, 0xa3,
-	0xd9, 0x01, 0xa2, 0x47, 0x68, 0x26, 0x9d, 0x57, 0x74, 0x1b, 0x30, 0x3d, 0x72, 0x93, 0x97, 0x68,
-	0x98, 0x5e, 0x3a, 0xe0, 0x19, 0xa8, 0x45, 0xa0, 0x32, 0x11, 0x5b, 0xd1, 0xc5, 0x9d, 0x24, 0x56,
-	0x69, 0x32, 0xda, 0x17, 0x52, 0x06, 0xa7, 0x54, 0xc4, 0x91, 0x3c, 0xa0, 0xf6, 0x6c, 0xef, 0xc0,
-	0x24, 0xa5, 0x45, 0x61, 0x0f, 0xa0, 0x87, 0x09, 0x6a, 0x72, 0xcf, 0xf4, 0x7d, 0x4b, 0xe8, 0x31,
-	0x2f, 0xc8, 0xdc, 0xc6, 0xb0, 0x87, 0xd0, 0x7f, 0x9b, 0x46, 0xf9, 0x4d, 0xcf, 0x64, 0xd5, 0x32,
-	0xca, 0x7c, 0x6d, 0xd1, 0x79, 0x09, 0xe5, 0x7f, 0x0e, 0x1f, 0xee, 0x8a, 0x91, 0x50, 0xa2, 0xd4,
-	0x19, 0xcd, 0xae, 0x66, 0x7f, 0x0b, 0xbc, 0x3a, 0x01, 0x93, 0x8f, 0x79, 0xde, 0x69, 0x11, 0x3d,
-	0xf0, 0x53, 0xe8, 0xdb, 0x26, 0xb0, 0x75, 0xe8, 0x0d, 0xcf, 0x82, 0x38, 0x16, 0xa3, 0x97, 0x85,
-	0x7a, 0x9b, 0x84, 0xf1, 0x21, 0x33, 0xd3, 0x97, 0x45, 0x16, 0x58, 0x14, 0xd4, 0x80, 0xbe, 0x8b,
-	0x74, 0xc7, 0xba, 0xbb, 0xd9, 0x24, 0xff, 0x00, 0x7a, 0x56, 0xa8, 0xae, 0x37, 0xa5, 0x96, 0xb7,
-	0xa7, 0x2c, 0x28, 0xfe, 0x7f, 0x1d, 0x58, 0x2c, 0x97, 0x39, 0xfb, 0x02, 0x53, 0x24, 0xa7, 0x64,
-	0x2d, 0xf4, 0x52, 0x25, 0x31, 0x79, 0x09, 0x54, 0x35, 0xbd, 0x31, 0x65, 0xfa, 0x54, 0x81, 0x34,
-	0x6b, 0x0a, 0x64, 0x1d, 0x7a, 0x91, 0x7c, 0x95, 0x26, 0xaf, 0xa3, 0x51, 0x14, 0x9f, 0x52, 0xde,
-	0x75, 0xb8, 0x4d, 0x42, 0x2d, 0xf4, 0x1e, 0xb0, 0x1d, 0x86, 0xa9, 0x90, 0x92, 0xea, 0xb5, 0xcb,
-	0x4b, 0xb4, 0x7c, 0x81, 0xdb, 0xd6, 0x02, 0xff, 0x75, 0x19, 0x7a, 0x96, 0xf5, 0xdf, 0xb9, 0x6e,
-	0x6e, 0x03, 0xe8, 0x9b, 0xf0, 0x5e, 0xbc, 0xff, 0xd8, 0xac, 0x8c, 0x45, 0xc9, 0xe7, 0x6c, 0x59,
-	0xa5, 0xfd, 0x02, 0x56, 0xa9, 0xae, 0x28, 0x99, 0x06, 0xf9, 0x35, 0x4f, 0x37, 0x1a, 0x2e, 0xc6,
-	0xd3, 0xce, 0xb6, 0xfc, 0xea, 0x53, 0x27, 0xc4, 0x06, 0xb0, 0x76, 0x40, 0xe5, 0x57, 0x51, 0xd6,
-	0x7e, 0x8f, 0xb2, 0xb5, 0xa4, 0x46, 0x8a, 0xfd, 0x06, 0x6e, 0x7c, 0x93, 0x44, 0xf1, 0xab, 0x20,
-	0x55, 0x11, 0x52, 0x44, 0x78, 0x98, 0xa4, 0x78, 0xd3, 0xd3, 0xa7, 0xfe, 0xf7, 0x2b, 0x6b, 0xbd,
-	0xf9, 0xa2, 0x0e, 0xcc, 0xeb, 0x75, 0xb0, 0x10, 0xdc, 0x61, 0x42, 0xad, 0xd2, 0xb4, 0x7e, 0x7d,
-	0x17, 0xd8, 0xa8, 0xea, 0xdf, 0x99, 0x81, 0xe7, 0x33, 0x35, 0xb1, 0x47, 0x00, 0xe3, 0x68, 0x2c,
-	0xb6, 0xe5, 0x76, 0x7a, 0x2a, 0xdd, 0x2e, 0xe9, 0xf5, 0xaa, 0x7a, 0x5f, 0xe5, 0x08, 0x6e, 0xa1,
-	0xd9, 0x01, 0xac, 0xc8, 0x61, 0xa0, 0x94, 0x48, 0x73, 0xbd, 0xd2, 0x05, 0x52, 0xf1, 0x49, 0x55,
-	0xc5, 0x61, 0x15, 0xc8, 0xa7, 0x65, 0x51, 0xe1, 0x30, 0x19, 0x8d, 0xc4, 0x50, 0x59, 0x0a, 0x7b,
-	0xf5, 0x0a, 0x77, 0xaa, 0x40, 0x3e, 0x2d, 0xcb, 0x06, 0xb0, 0xac, 0xb3, 0x60, 0x3c, 0x8a, 0x14,
-	0xa7, 0x2a, 0x72, 0xfb, 0xa4, 0x6f, 0xbd, 0xaa, 0x6f, 0xaf, 0x82, 0xe3, 0x53, 0x92, 0x18, 0xab,
-	0x34, 0x99, 0xc4, 0x21, 0x4f, 0x4e, 0xa2, 0xd8, 0x5d, 0xa8, 0x8f, 0x15, 0xcf, 0x11, 0xdc, 0x42,
-	0xb3, 0x87, 0xfa, 0xa2, 0x3e, 0x3a, 0x4a, 0xc6, 0xee, 0x22, 0x49, 0xba, 0x55, 0xc9, 0x81, 0xe1,
-	0xf3, 0x1c, 0xc9, 0xbe, 0x84, 0xee, 0x49, 0x9a, 0x04, 0xe1, 0x30, 0x90, 0xca, 0x5d, 0x22, 0xb1,
-	0x0f, 0xab, 0x62, 0x8f, 0x33, 0x00, 0x2f, 0xb0, 0xec, 0x97, 0xb0, 0x46, 0x4a, 0x70, 0x4b, 0xd8,
-	0x8e, 0x43, 0x4c, 0xbc, 0xaf, 0x23, 0x75, 0xe6, 0x2e, 0x93, 0x8e, 0xbb, 0xb5, 0x53, 0x57, 0xb0,
-	0xbc, 0x56, 0x03, 0xdb, 0x84, 0xb6, 0x1c, 0xa6, 0xd1, 0x58, 0xb9, 0x2b, 0xa4, 0xeb, 0xe6, 0xf4,
-	0x4a, 0x23, 0x97, 0x1b, 0x14, 0xba, 0x40, 0x7a, 0x30, 0xdf, 0x5c, 0x56, 0xef, 0xc2, 0x20, 0x03,
-	0xf0, 0x02, 0xcb, 0x76, 0x60, 0xe1, 0x42, 0xa4, 0xa7, 0x42, 0x27, 0xea, 0x51, 0xe2, 0xae, 0x92,
-	0xf0, 0xc7, 0x55, 0xe1, 0x7d, 0x1b, 0xc4, 0xcb, 0x32, 0xec, 0x01, 0xcc, 0x13, 0xe1, 0x28, 0x71,
-	0x6f, 0x92, 0xf8, 0xad, 0x5a, 0xf1, 0xa3, 0x84, 0x67, 0x38, 0x9c, 0x97, 0x8c, 0xd8, 0x8d, 0xa4,
-	0x8a, 0xe2, 0xa1, 0x72, 0x6f, 0xd4, 0xcf, 0x3b, 0xb0, 0x41, 0xbc, 0x2c, 0xe3, 0xfd, 0xde, 0x81,
-	0x1b, 0xb5, 0xd5, 0x8e, 0x6d, 0x6a, 0x14, 0x87, 0xe2, 0x9d, 0xc8, 0x6f, 0x70, 0x66, 0x88, 0x37,
-	0xde, 0x48, 0x0e, 0xc4, 0x6b, 0x75, 0x30, 0x51, 0x22, 0x45, 0x69, 0xd3, 0x75, 0x56, 0xc9, 0xec,
-	0x87, 0xb0, 0x1c, 0x49, 0x1e, 0x9d, 0x9e, 0x59, 0x50, 0xfd, 0xaa, 0x33, 0x45, 0xf7, 0x1e, 0x82,
-	0x3b, 0x6b, 0x5b, 0x98, 0x6d, 0x8b, 0xb7, 0x0e, 0x50, 0x14, 0x3d, 0xee, 0xca, 0xc3, 0xac, 0x19,
-	0xeb, 0x72, 0xfa, 0xf6, 0x3e, 0x83, 0x95, 0xa9, 0x9a, 0xbe, 0x42, 0xe1, 0x2a, 0xac, 0x4c, 0x55,
-	0xac, 0x77, 0x1f, 0x96, 0xab, 0x65, 0x87, 0xb7, 0x61, 0x2a, 0xbc, 0xa3, 0xcb, 0x71, 0x36, 0x61,
-	0x41, 0xf0, 0xfa, 0x00, 0x45, 0x81, 0x79, 0xdb, 0xfa, 0x91, 0x93, 0x4a, 0xa5, 0x0f, 0x4e, 0x6c,
-	0x0e, 0x21, 0x27, 0x66, 0x9f, 0x42, 0x27, 0x49, 0x43, 0x91, 0x3e, 0xbe, 0xcc, 0x1e, 0x9e, 0x7a,
-	0xb8, 0x7e, 0x07, 0x9a, 0xc6, 0x73, 0xa6, 0xd7, 0x83, 0x6e, 0x5e, 0x40, 0xde, 0x7d, 0x58, 0xab,
-	0xab, 0x84, 0x2b, 0xdc, 0xfa, 0x35, 0xb4, 0x75, 0xbe, 0xe3, 0x89, 0x17, 0x49, 0x8c, 0x99, 0x69,
-	0xd8, 0xcc, 0x88, 0xde, 0x4b, 0x03, 0x75, 0x96, 0x35, 0xab, 0xf8, 0x8d, 0xb4, 0x00, 0xb7, 0x5b,
-	0xec, 0x55, 0xbb, 0x9c, 0xbe, 0xf1, 0x16, 0x20, 0xe2, 0x37, 0xf4, 0x5a, 0xd2, 0xe5, 0xf8, 0xe9,
-	0x3d, 0x84, 0x6e, 0x5e, 0x18, 0x25, 0x87, 0x9c, 0xab, 0x1c, 0xfa, 0x09, 0x2c, 0x94, 0x2a, 0xe2,
-	0xfa, 0x92, 0x5d, 0x98, 0x37, 0xc5, 0x80, 0x4a, 0x4a, 0xe9, 0x7d, 0x6d, 0x25, 0xfe, 0x8f, 0x61,
-	0xde, 0x10, 0xb1, 0xdd, 0xa3, 0x30, 0x99, 0x55, 0xd1, 0x03, 0xa4, 0x12, 0xd8, 0x34, 0x06, 0x7a,
-	0xe0, 0xff, 0xd9, 0xa9, 0xbc, 0xb4, 0x78, 0xd0, 0x79, 0x3a, 0x4a, 0xde, 0x5a, 0xfd, 0x58, 0xe7,
-	0xb5, 0x19, 0x63, 0x8a, 0x14, 0x8f, 0x42, 0x8d, 0xea, 0x2b, 0xcc, 0x3d, 0x58, 0xb4, 0x35, 0xed,
-	0x85, 0xa6, 0xcd, 0x58, 0x0c, 0x4b, 0x54, 0x6c, 0x81, 0x9e, 0xbe, 0xe7, 0x4e, 0xe9, 0x7f, 0x03,
-	0x6b, 0x75, 0xed, 0x00, 0x2e, 0xe0, 0xcb, 0xea, 0x4d, 0x96, 0x41, 0xeb, 0x79, 0x62, 0xda, 0xf1,
-	0x2e, 0x6f, 0xe1, 0x95, 0x1c, 0x69, 0xaf, 0x70, 0xdf, 0x6b, 0x16, 0x8f, 0xe5, 0xd6, 0x43, 0x6c,
-	0xcb, 0x7e, 0x88, 0xdd, 0xfa, 0xd6, 0x81, 0xde, 0xb3, 0x91, 0x08, 0x2e, 0xf6, 0xe9, 0x7f, 0x0d,
-	0x7b, 0x04, 0xfd, 0x67, 0x42, 0x15, 0x7f, 0x4e, 0x58, 0xe9, 0x0a, 0x48, 0x8d, 0xab, 0xb7, 0x56,
-	0x79, 0x96, 0xa1, 0xf7, 0x70, 0xff, 0x03, 0xf6, 0x19, 0x2c, 0x1c, 0x8a, 0x38, 0x2c, 0x9e, 0xb8,
-	0x17, 0x10, 0x98, 0x0f, 0xbd, 0x2e, 0x5d, 0xcc, 0xe8, 0x95, 0xf9, 0x83, 0x0d, 0x87, 0x6d, 0xc3,
-	0x2d, 0x84, 0xd7, 0x3d, 0x03, 0xdf, 0x9a, 0xf1, 0x90, 0x53, 0x51, 0xb1, 0xf5, 0xb7, 0x06, 0x00,
-	0x59, 0xbf, 0x8d, 0x2d, 0x24, 0xfb, 0x0a, 0x96, 0x49, 0xa3, 0x75, 0xed, 0x36, 0xaa, 0xa6, 0xdf,
-	0x05, 0x3c, 0x77, 0x9a, 0xa1, 0x6f, 0x0c, 0xa8, 0xf9, 0xbe, 0xc3, 0x1e, 0xc1, 0xbc, 0x9e, 0x5d,
-	0xb0, 0xda, 0xa7, 0x2b, 0xef, 0x46, 0x85, 0x9a, 0x49, 0xdf, 0x77, 0xd8, 0xcf, 0xc1, 0x33, 0xfb,
-	0x4e, 0xc9, 0x01, 0xcc, 0xeb, 0xa1, 0x64, 0xd3, 0x17, 0xd4, 0x6a, 0x68, 0xf6, 0xa0, 0xad, 0x6f,
-	0x34, 0x8c, 0x0e, 0x80, 0x99, 0xd7, 0x21, 0xef, 0xf6, 0x2c, 0x76, 0x66, 0xcc, 0x49, 0x9b, 0xfe,
-	0xbd, 0x7d, 0xf1, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa9, 0x71, 0x4a, 0xbd, 0x91, 0x1b, 0x00,
-	0x00,
+	// 2644 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x59, 0xcd, 0x72, 0x24, 0x47,
+	0xf1, 0x77, 0xcf, 0x97, 0x66, 0x72, 0x3e, 0x24, 0x95, 0xb4, 0xab, 0x76, 0xdb, 0x5e, 0xeb, 0xdf,
+	0xe1, 0xbf, 0x2d, 0x20, 0x2c, 0xaf, 0xe5, 0x05, 0x3b, 0x16, 0x43, 0x20, 0x4b, 0x5e, 0x5b, 0xf6,
+	0x68, 0xa5, 0x28, 0x29, 0xc2, 0x80, 0x0f, 0x8a, 0xd6, 0x74, 0x69, 0xd4, 0xd6, 0x4c, 0xf7, 0xd0,
+	0x55, 0xb3, 0xbb, 0xe2, 0x05, 0x38, 0x71, 0x20, 0x82, 0x0b, 0x37, 0x8e, 0x3c, 0x00, 0xc1, 0xc5,
+	0x4f, 0xc0, 0x09, 0x1e, 0x02, 0xae, 0xdc, 0x38, 0x70, 0x23, 0xb2, 0xaa, 0xba, 0xbb, 0xfa, 0x63,
+	0x66, 0x65, 0x6e, 0x5d, 0x59, 0xbf, 0xcc, 0xca, 0xcc, 0xca, 0xcc, 0xca, 0xaa, 0x06, 0x32, 0xf5,
+	0xb8, 0x60, 0xf1, 0x85, 0x37, 0x66, 0xa1, 0xd8, 0x9d, 0xc5, 0x91, 0x88, 0x48, 0x6d, 0x76, 0xe9,
+	0xfe, 0xcd, 0x82, 0xc1, 0x41, 0x34, 0x9d, 0xcd, 0x05, 0xa3, 0xec, 0x57, 0x73, 0xc6, 0x05, 0x79,
+	0x13, 0xba, 0xbe, 0x27, 0xbc, 0x8b, 0x11, 0x0b, 0x05, 0x8b, 0x6d, 0x6b, 0xdb, 0xda, 0xe9, 0x50,
+	0x40, 0xd2, 0x81, 0xa4, 0x90, 0x9f, 0xc1, 0xfa, 0x48, 0xb1, 0x5c, 0xc4, 0x8c, 0x47, 0xf3, 0x78,
+	0xc4, 0xb8, 0x5d, 0xdb, 0xae, 0xef, 0x74, 0xf7, 0x36, 0x76, 0x67, 0x97, 0xbb, 0xa9, 0x3c, 0x35,
+	0x47, 0xd7, 0x46, 0x79, 0x02, 0x27, 0x0e, 0xb4, 0xe7, 0x9c, 0xc5, 0xa1, 0x37, 0x65, 0x76, 0x5d,
+	0xca, 0x4f, 0xc7, 0x38, 0x77, 0x1d, 0x71, 0x21, 0xe7, 0x1a, 0x6a, 0x2e, 0x19, 0x13, 0x17, 0x7a,
+	0x57, 0x93, 0xe8, 0xf9, 0xe7, 0x1e, 0xbf, 0x3e, 0x88, 0x7c, 0x66, 0x37, 0xb7, 0xad, 0x9d, 0x3e,
+	0xcd, 0xd1, 0xdc, 0x6f, 0x2d, 0x58, 0x2d, 0x68, 0x40, 0x5e, 0x83, 0xce, 0x68, 0x36, 0xbf, 0x18,
+	0x45, 0xf3, 0x50, 0x48, 0x83, 0x9a, 0xb4, 0x3d, 0x9a, 0xcd, 0x0f, 0x70, 0x9c, 0x4c, 0x4e, 0xd8,
+	0x33, 0x36, 0xb1, 0x6b, 0xe9, 0xe4, 0x10, 0xc7, 0x38, 0x39, 0x4e, 0x39, 0xeb, 0x6a, 0x72, 0x6c,
+	0x70, 0x8e, 0x53, 0xce, 0x46, 0x3a, 0x99, 0x72, 0x4e, 0xd9, 0x34, 0x8a, 0x6f, 0x2f, 0xa6, 0x97,
+	0x52, 0xd1, 0x3a, 0x6d, 0x2b, 0xc2, 0xf1, 0x25, 0xd9, 0x82, 0x15, 0x3f, 0xe0, 0x37, 0x38, 0xd5,
+	0x92, 0x53, 0x2d, 0x1c, 0x1e, 0x5f, 0xba, 0x43, 0xe8, 0x1d, 0x7a, 0xc2, 0x4b, 0x35, 0xdf, 0x81,
+	0xf6, 0x24, 0x1a, 0x79, 0x22, 0x88, 0x42, 0xa9, 0x78, 0x77, 0xaf, 0x87, 0x2e, 0x1e, 0x6a, 0x1a,
+	0x4d, 0x67, 0x09, 0x81, 0x06, 0x0f, 0x7e, 0xcd, 0xa4, 0x05, 0x75, 0x2a, 0xbf, 0xdd, 0x1b, 0x68,
+	0x27, 0xc8, 0x97, 0x6f, 0x2b, 0x81, 0x46, 0xec, 0x8d, 0x6e, 0xa4, 0x80, 0x0e, 0x95, 0xdf, 0xe4,
+	0x3e, 0xb4, 0x38, 0x8b, 0x9f, 0xb1, 0x58, 0x6f, 0x93, 0x1e, 0x21, 0x76, 0x16, 0xc5, 0x42, 0x1b,
+	0x2d, 0xbf, 0xdd, 0x00, 0x60, 0x7f, 0x92, 0xaa, 0x73, 0x77, 0xc5, 0xdf, 0x87, 0x8e, 0xa7, 0xf8,
+	0x98, 0x2f, 0x17, 0x5f, 0x10, 0x46, 0x19, 0xca, 0x3d, 0x84, 0xb5, 0x6c, 0x29, 0xca, 0xf8, 0x7c,
+	0x22, 0xc8, 0x43, 0xe8, 0x7a, 0x29, 0x8d, 0xdb, 0x96, 0x8c, 0xc7, 0x01, 0x0a, 0x32, 0xa0, 0x26,
+	0xc4, 0xfd, 0x83, 0x05, 0x9d, 0xcf, 0x99, 0x17, 0x8b, 0x4b, 0xe6, 0x89, 0xef, 0xa0, 0xf0, 0x7b,
+	0xd0, 0x4e, 0xe2, 0x7e, 0x99, 0xbe, 0x29, 0x28, 0x6f, 0x61, 0xfd, 0x4e, 0x16, 0xae, 0x40, 0xf3,
+	0xd3, 0xe9, 0x4c, 0xdc, 0xba, 0xbe, 0x0a, 0x88, 0xa1, 0xb1, 0xcd, 0x32, 0x35, 0xd4, 0xfe, 0xc9,
+	0xef, 0x9c, 0xea, 0xb5, 0xa5, 0xaa, 0xdf, 0x87, 0x56, 0x14, 0x1e, 0x06, 0xfc, 0x46, 0xaa, 0xd1,
+	0xa6, 0x7a, 0xe4, 0xfe, 0xbd, 0x07, 0x1b, 0x4f, 0x26, 0xd1, 0xf3, 0x4f, 0x5f, 0xb0, 0xd1, 0x1c,
+	0x91, 0x67, 0xc2, 0x13, 0x73, 0x4e, 0xf6, 0x01, 0xb8, 0x60, 0xb3, 0xcf, 0xe2, 0x68, 0x3e, 0x4b,
+	0x7c, 0xfa, 0x7f, 0x28, 0xbb, 0x02, 0xbc, 0x7b, 0x96, 0x20, 0xa9, 0xc1, 0x84, 0x22, 0x84, 0xc7,
+	0x6f, 0xb4, 0x88, 0xda, 0x72, 0x11, 0xe7, 0x09, 0x92, 0x1a, 0x4c, 0xe4, 0xc7, 0xd0, 0xc6, 0x38,
+	0xe5, 0x4c, 0x70, 0xbb, 0x2e, 0x05, 0xbc, 0xb9, 0x48, 0xc0, 0xa1, 0xc2, 0xd1, 0x94, 0x81, 0x7c,
+	0x01, 0x7d, 0xfd, 0x7d, 0x76, 0xed, 0xc5, 0x3e, 0xb7, 0x1b, 0x52, 0xc2, 0x5b, 0x2f, 0x91, 0x20,
+	0xc1, 0x34, 0xcf, 0x4a, 0xf6, 0xa0, 0x89, 0x6a, 0x71, 0xbb, 0x29, 0x65, 0xbc, 0xbe, 0xcc, 0x0c,
+	0xaa, 0xa0, 0xc8, 0x83, 0xde, 0xe0, 0x76, 0x6b, 0x39, 0x0f, 0x7a, 0x8f, 0x2a, 0x28, 0x19, 0x40,
+	0x2d, 0xf0, 0xed, 0x15, 0x59, 0xdd, 0x6a, 0x81, 0x4f, 0x1e, 0x43, 0xcb, 0x8f, 0x03, 0x4c, 0xc3,
+	0xb6, 0xdc, 0x5e, 0x77, 0xa1, 0xf2, 0x12, 0x75, 0x14, 0x5e, 0x45, 0x54, 0x73, 0x38, 0xbb, 0xd0,
+	0x40, 0x75, 0x64, 0x2a, 0x0b, 0x36, 0x3b, 0xf2, 0x75, 0x01, 0xd4, 0x23, 0xbd, 0x96, 0xaa, 0x7b,
+	0xb5, 0xc0, 0x77, 0xfe, 0x6c, 0x41, 0x03, 0x75, 0xd1, 0x13, 0x56, 0x32, 0x91, 0x46, 0x5e, 0xcd,
+	0x88, 0xbc, 0xd7, 0xa1, 0x33, 0xf3, 0x62, 0x16, 0x8a, 0x23, 0x5f, 0x6d, 0x4d, 0x93, 0x66, 0x04,
+	0x62, 0xc3, 0x0a, 0xfa, 0xe0, 0x48, 0x3b, 0xbd, 0x49, 0x93, 0x21, 0x79, 0x1b, 0x06, 0x41, 0x38,
+	0x9b, 0x0b, 0xed, 0xec, 0x23, 0x5f, 0x7a, 0xb4, 0x49, 0x0b, 0x54, 0xb2, 0x03, 0xab, 0xd1, 0x5c,
+	0xe4, 0x80, 0x2d, 0xa9, 0x50, 0x91, 0xec, 0xfc, 0x02, 0x56, 0xf4, 0xa0, 0xa4, 0x78, 0x66, 0x79,
+	0x2d, 0x67, 0xf9, 0xdb, 0x30, 0x88, 0x99, 0xe7, 0x07, 0xe1, 0xf8, 0x4c, 0x12, 0x12, 0x0b, 0x0a,
+	0x54, 0xe7, 0x63, 0x95, 0x82, 0x49, 0x18, 0xa0, 0xd1, 0x7e, 0xaa, 0x8e, 0x5a, 0x26, 0x23, 0x94,
+	0xfc, 0x79, 0x00, 0x9d, 0x34, 0x31, 0xd0, 0x23, 0x5c, 0xaf, 0x65, 0x29, 0x8f, 0xe8, 0x61, 0xde,
+	0x93, 0xb5, 0x82, 0x27, 0x9d, 0x7f, 0xd4, 0xa1, 0x93, 0xe6, 0xc6, 0x12, 0x29, 0x86, 0xc7, 0x6b,
+	0x79, 0x8f, 0xef, 0xc2, 0x4a, 0xac, 0x0e, 0x78, 0x5d, 0x81, 0x36, 0x31, 0x86, 0xd2, 0xf8, 0xd1,
+	0x87, 0x3f, 0x4d, 0x40, 0x64, 0x17, 0x20, 0xab, 0x95, 0xb2, 0xce, 0x97, 0xab, 0xa9, 0x81, 0x20,
+	0x5f, 0x02, 0xb0, 0x44, 0x58, 0x92, 0x1f, 0x3f, 0x78, 0x69, 0x9a, 0x1b, 0x0a, 0x18, 0xec, 0xce,
+	0xbf, 0x2d, 0xe8, 0xa4, 0x33, 0xe4, 0x0d, 0x2c, 0x42, 0x5e, 0x2c, 0x2e, 0x44, 0xa0, 0x0b, 0x5f,
+	0x9d, 0x76, 0x24, 0xe5, 0x3c, 0x98, 0xca, 0xc3, 0x9d, 0x8b, 0x68, 0xa6, 0x66, 0xd5, 0xe9, 0xd7,
+	0x46, 0x82, 0x9c, 0x7c, 0x13, 0xba, 0xfc, 0x96, 0x0b, 0x36, 0x55, 0xd3, 0x68, 0xba, 0x45, 0x41,
+	0x91, 0x12, 0x6e, 0x6c, 0x3d, 0xd4, 0x74, 0x43, 0x4e, 0xcb, 0x5e, 0x44, 0x4e, 0x6e, 0x42, 0x93,
+	0xc5, 0x71, 0x14, 0xcb, 0xf3, 0xbb, 0x47, 0xd5, 0x00, 0x65, 0xaa, 0xe8, 0xbb, 0xb8, 0xf6, 0xf8,
+	0xb5, 0x0c, 0xc8, 0x1e, 0x05, 0x45, 0xc2, 0x36, 0x84, 0x7c, 0x08, 0x7d, 0x66, 0x5a, 0x2c, 0x33,
+	0xb9, 0xbb, 0xb7, 0x9e, 0xf3, 0x38, 0x4e, 0xd0, 0x3c, 0xce, 0xf9, 0xab, 0x05, 0x90, 0xa5, 0x70,
+	0xae, 0x4d, 0xb2, 0x96, 0xb4, 0x49, 0xb5, 0x42, 0x9b, 0xf4, 0x20, 0xd9, 0x0b, 0xef, 0x72, 0x92,
+	0x34, 0x58, 0x06, 0x85, 0xbc, 0x03, 0xab, 0xd9, 0x48, 0x19, 0xa1, 0x3a, 0xad, 0x41, 0x46, 0x96,
+	0x86, 0xe4, 0x3d, 0xdf, 0x5c, 0xea, 0xf9, 0x56, 0xde, 0xf3, 0xee, 0x6f, 0x2d, 0xd8, 0x78, 0x12,
+	0x4c, 0xb2, 0xd3, 0x4d, 0x07, 0x56, 0xd5, 0x01, 0xb6, 0x06, 0x75, 0x3f, 0x88, 0xb5, 0x1d, 0xf8,
+	0x89, 0x28, 0xa9, 0x57, 0x5d, 0xd6, 0x40, 0xf9, 0x5d, 0xea, 0xfe, 0x1a, 0xe5, 0xee, 0x0f, 0x13,
+	0x60, 0x14, 0x85, 0x82, 0x85, 0x42, 0xef, 0x59, 0x32, 0x74, 0x87, 0xb0, 0x99, 0x57, 0x87, 0xcf,
+	0xa2, 0x90, 0x33, 0xf2, 0x16, 0xf4, 0xbd, 0x09, 0x66, 0xfc, 0xed, 0xa7, 0x2f, 0x02, 0x2e, 0xb8,
+	0x54, 0xac, 0x4d, 0xf3, 0x44, 0xcc, 0xea, 0x48, 0xb5, 0x46, 0x6d, 0x5a, 0x8b, 0x6e, 0xdc, 0xdf,
+	0x59, 0xb0, 0x56, 0x4c, 0x1e, 0xf2, 0x18, 0xab, 0x1a, 0x17, 0xf1, 0x7c, 0x24, 0x77, 0x94, 0x09,
+	0xdd, 0x48, 0x10, 0xdc, 0xf8, 0xa3, 0xdc, 0x0c, 0x2d, 0x20, 0x2b, 0x5c, 0x60, 0xb6, 0x19, 0xf5,
+	0x3b, 0xb4, 0x19, 0xee, 0x5f, 0x2c, 0x58, 0x37, 0x74, 0xd2, 0xf6, 0xe1, 0x91, 0x2f, 0x43, 0x53,
+	0x2a, 0xd3, 0xa3, 0x7a, 0x94, 0xc5, 0x76, 0xcd, 0x8c, 0xed, 0x07, 0x60, 0x24, 0x47, 0x45, 0xba,
+	0xe8, 0x90, 0x3c, 0xaf, 0xca, 0x96, 0x52, 0xd8, 0x37, 0xef, 0x16, 0xf6, 0x6e, 0x0c, 0xfd, 0xdc,
+	0x7c, 0x69, 0xa7, 0xad, 0x8a, 0x9d, 0xae, 0x3a, 0x8e, 0xbe, 0x87, 0x67, 0xad, 0x97, 0x76, 0x09,
+	0x1b, 0x45, 0xbf, 0xe3, 0xda, 0x0a, 0xe1, 0xfe, 0xc7, 0x82, 0xd5, 0xc2, 0xd4, 0xc2, 0x23, 0xf2,
+	0x3e, 0xb4, 0x54, 0x19, 0x4d, 0x0e, 0x10, 0x35, 0x42, 0x35, 0xe5, 0x79, 0x25, 0x6f, 0x03, 0xba,
+	0x47, 0xae, 0xd3, 0x1c, 0x0d, 0xc3, 0x4b, 0x39, 0x3c, 0x01, 0x35, 0x24, 0x28, 0x4f, 0x24, 0x1f,
+	0x42, 0x7b, 0xa4, 0x3e, 0x93, 0xda, 0xf9, 0x5a, 0x85, 0xee, 0xbb, 0x1a, 0x4e, 0x53, 0xb0, 0xf3,
+	0x01, 0xac, 0x24, 0x32, 0xaa, 0x12, 0x6b, 0x13, 0x9a, 0xcf, 0xbc, 0xc9, 0x3c, 0xa9, 0x8b, 0x6a,
+	0x80, 0x8d, 0xef, 0xe0, 0x20, 0x0a, 0x45, 0x1c, 0x4d, 0x8e, 0x19, 0xe7, 0xde, 0x58, 0x96, 0x8c,
+	0x80, 0x9f, 0xc8, 0x66, 0xf0, 0xe8, 0x44, 0xa7, 0x80, 0x41, 0x21, 0xef, 0x43, 0x17, 0xd3, 0x41,
+	0x47, 0xba, 0xee, 0x32, 0x57, 0x51, 0x47, 0x9a, 0x91, 0xa9, 0x89, 0x21, 0x8f, 0xa0, 0xf7, 0x3c,
+	0x0e, 0xd2, 0x7b, 0xa5, 0x8e, 0xe1, 0x35, 0xe4, 0xf9, 0xca, 0xa0, 0xd3, 0x1c, 0xca, 0x7d, 0x0f,
+	0x5e, 0x3d, 0x64, 0x13, 0x26, 0x58, 0xae, 0x0f, 0x5b, 0x5c, 0x3b, 0xdc, 0x3d, 0x70, 0xaa, 0x18,
+	0x74, 0xf4, 0xa7, 0x51, 0xae, 0x58, 0xd4, 0xc0, 0x8d, 0xa1, 0x67, 0xaa, 0x40, 0xb6, 0xa1, 0x3b,
+	0xba, 0xf6, 0xc2, 0x90, 0x4d, 0x9e, 0x66, 0xe2, 0x4d, 0x12, 0xfa, 0x47, 0xaa, 0x19, 0x3f, 0xcd,
+	0x62, 0xce, 0xa0, 0xa0, 0x04, 0xb4, 0x9d, 0xc5, 0x07, 0xc6, 0x4d, 0xd1, 0x24, 0xb9, 0x27, 0xd0,
+	0x35, 0x5c, 0x75, 0xb7, 0x25, 0x15, 0xbf, 0xb9, 0x64, 0x46, 0x71, 0xff, 0x69, 0xc1, 0x20, 0x5f,
+	0x54, 0xc8, 0x07, 0x18, 0x90, 0x29, 0x25, 0x69, 0xd8, 0x57, 0x0b, 0xa1, 0x44, 0x73, 0xa0, 0xa2,
+	0xea, 0xb5, 0x92, 0xea, 0xa5, 0x74, 0xac, 0x57, 0xa4, 0xe3, 0x36, 0x74, 0x03, 0x7e, 0x1a, 0x47,
+	0x57, 0xc1, 0x24, 0x08, 0xc7, 0x32, 0xca, 0xdb, 0xd4, 0x24, 0xa1, 0x14, 0xf9, 0xfa, 0xb0, 0xef,
+	0xfb, 0x31, 0xe3, 0x5c, 0x56, 0x87, 0x0e, 0xcd, 0xd1, 0xd2, 0x0d, 0x6e, 0x19, 0x1b, 0xfc, 0xaf,
+	0x2d, 0xe8, 0x1a, 0xda, 0x7f, 0xe7, 0x2c, 0x7d, 0x00, 0xa0, 0xee, 0xdd, 0x47, 0xe1, 0xf1, 0x27,
+	0x7a, 0x67, 0x0c, 0x4a, 0xba, 0x66, 0xc3, 0xc8, 0x9b, 0x2f, 0x60, 0x43, 0x66, 0xb1, 0x0c, 0xa6,
+	0x61, 0x7a, 0xa9, 0x54, 0xa9, 0x69, 0xa3, 0x3f, 0xcd, 0x68, 0x4b, 0x00, 0xb4, 0x8a, 0x89, 0x0c,
+	0x61, 0xf3, 0x64, 0x2e, 0x4a, 0x74, 0xbb, 0xf5, 0x12, 0x61, 0x9b, 0x51, 0x05, 0x17, 0xf9, 0x1a,
+	0xee, 0x7d, 0x13, 0x05, 0xe1, 0xa9, 0x17, 0x8b, 0x00, 0x29, 0xcc, 0x3f, 0x8b, 0x62, 0xbc, 0x57,
+	0xaa, 0x1e, 0xe3, 0xff, 0x0b, 0x7b, 0xbd, 0xfb, 0x45, 0x15, 0x98, 0x56, 0xcb, 0x20, 0x3e, 0xd8,
+	0xa3, 0x48, 0x36, 0x66, 0x65, 0xf9, 0xea, 0xe6, 0xb1, 0x53, 0x94, 0x7f, 0xb0, 0x00, 0x4f, 0x17,
+	0x4a, 0x22, 0x8f, 0x01, 0x66, 0xc1, 0x8c, 0xed, 0xf3, 0xfd, 0x78, 0xcc, 0xed, 0x8e, 0x94, 0xeb,
+	0x14, 0xe5, 0x9e, 0xa6, 0x08, 0x6a, 0xa0, 0xc9, 0x09, 0xac, 0xf3, 0x91, 0x27, 0x04, 0x8b, 0x53,
+	0xb9, 0xdc, 0x86, 0x6d, 0x2b, 0xb9, 0x54, 0x9a, 0x22, 0xce, 0x8a, 0x40, 0x5a, 0xe6, 0x45, 0x81,
+	0xa3, 0x68, 0x32, 0x61, 0x23, 0x61, 0x08, 0xec, 0x56, 0x0b, 0x3c, 0x28, 0x02, 0x69, 0x99, 0x97,
+	0x0c, 0x61, 0x4d, 0x45, 0xc1, 0x6c, 0x12, 0x08, 0x2a, 0xb3, 0xc8, 0xee, 0x49, 0x79, 0xdb, 0x45,
+	0x79, 0x47, 0x05, 0x1c, 0x2d, 0x71, 0xa2, 0xaf, 0xe2, 0x68, 0x1e, 0xfa, 0x34, 0xba, 0x0c, 0x42,
+	0xbb, 0x5f, 0xed, 0x2b, 0x9a, 0x22, 0xa8, 0x81, 0x26, 0x8f, 0xd4, 0xb3, 0xc0, 0xe4, 0x3c, 0x9a,
+	0xd9, 0x83, 0x6d, 0x2b, 0x09, 0x36, 0x93, 0x73, 0xa8, 0xe7, 0x69, 0x8a, 0x24, 0x1f, 0x42, 0xe7,
+	0x32, 0x8e, 0x3c, 0x7f, 0xe4, 0x71, 0x61, 0xaf, 0x4a, 0xb6, 0x57, 0x8b, 0x6c, 0x9f, 0x24, 0x00,
+	0x9a, 0x61, 0xc9, 0xcf, 0x61, 0x53, 0x0a, 0xc1, 0x92, 0xb0, 0x1f, 0xfa, 0x18, 0x78, 0x5f, 0x05,
+	0xe2, 0xda, 0x5e, 0xdb, 0xb6, 0x92, 0xfb, 0x76, 0x69, 0xe9, 0x02, 0x96, 0x56, 0x4a, 0x20, 0xbb,
+	0xd0, 0xe2, 0xa3, 0x38, 0x98, 0x09, 0x7b, 0x5d, 0xca, 0xba, 0x5f, 0xde, 0x69, 0x9c, 0xa5, 0x1a,
+	0x85, 0x26, 0x48, 0x39, 0x18, 0x6f, 0x36, 0xa9, 0x36, 0x61, 0x98, 0x00, 0x68, 0x86, 0x25, 0x07,
+	0xd0, 0x9f, 0xb2, 0x78, 0xcc, 0x54, 0xa0, 0x9e, 0x47, 0xf6, 0x86, 0x64, 0x7e, 0xa3, 0xc8, 0x7c,
+	0x6c, 0x82, 0x68, 0x9e, 0x87, 0xbc, 0x0f, 0x2b, 0x92, 0x70, 0x1e, 0xd9, 0xf7, 0x25, 0xfb, 0x56,
+	0x25, 0xfb, 0x79, 0x44, 0x13, 0x1c, 0xae, 0x2b, 0x95, 0x38, 0x0c, 0xb8, 0x08, 0xc2, 0x91, 0xb0,
+	0xef, 0x55, 0xaf, 0x3b, 0x34, 0x41, 0x34, 0xcf, 0x43, 0x7e, 0x02, 0x5d, 0x65, 0x89, 0x37, 0x9d,
+	0x4d, 0x98, 0xbd, 0xb5, 0x6d, 0x55, 0xb4, 0x11, 0xbb, 0xc3, 0x0c, 0x42, 0x4d, 0x3c, 0xc6, 0x6d,
+	0xec, 0x85, 0x63, 0x26, 0xa3, 0xef, 0x34, 0x0a, 0x42, 0xc1, 0x6d, 0xbb, 0x3a, 0x6e, 0x69, 0x01,
+	0x47, 0x4b, 0x9c, 0x68, 0x91, 0xce, 0x35, 0x09, 0xe6, 0xf6, 0xab, 0xd5, 0x16, 0x9d, 0x99, 0x20,
+	0x9a, 0xe7, 0x21, 0x4f, 0x60, 0x20, 0x35, 0xdc, 0x1f, 0x8f, 0x63, 0x36, 0xf6, 0x04, 0xb3, 0x1d,
+	0x29, 0xe5, 0x41, 0xa5, 0x51, 0x29, 0x8a, 0x16, 0xb8, 0x30, 0x11, 0xb8, 0xf7, 0x8c, 0x61, 0xfb,
+	0x6f, 0xbf, 0x56, 0x9d, 0x08, 0x67, 0x7a, 0x9e, 0xa6, 0x48, 0x34, 0x01, 0x0f, 0xc1, 0x73, 0xf6,
+	0x42, 0x65, 0xa4, 0xfd, 0x7a, 0xb5, 0x09, 0xd4, 0x04, 0xd1, 0x3c, 0x8f, 0xf3, 0x1b, 0x0b, 0xee,
+	0x55, 0x96, 0x60, 0xbc, 0xa9, 0x04, 0xa1, 0xcf, 0x5e, 0xb0, 0xf4, 0x12, 0xaf, 0x87, 0xf8, 0xe8,
+	0x11, 0xf0, 0x21, 0xbb, 0x12, 0x27, 0x73, 0xc1, 0x62, 0xe4, 0xd6, 0x17, 0x8f, 0x22, 0x99, 0x7c,
+	0x1f, 0xd6, 0x02, 0x4e, 0x83, 0xf1, 0xb5, 0x01, 0x55, 0x0f, 0x7b, 0x25, 0xba, 0xf3, 0x08, 0xec,
+	0x45, 0xb5, 0x7a, 0xb1, 0x2e, 0xce, 0x36, 0x40, 0x56, 0x89, 0xf1, 0xa8, 0x1c, 0x25, 0xfd, 0x78,
+	0x87, 0xca, 0x6f, 0xe7, 0x5d, 0x58, 0x2f, 0x15, 0xda, 0x25, 0x02, 0x37, 0x60, 0xbd, 0x54, 0x46,
+	0x9d, 0x87, 0xb0, 0x56, 0xac, 0x85, 0xf8, 0x20, 0x22, 0xab, 0xe1, 0xf9, 0xed, 0x2c, 0x59, 0x30,
+	0x23, 0x38, 0x3d, 0x80, 0xac, 0xea, 0x39, 0xfb, 0xea, 0x9d, 0x5b, 0xd6, 0xaf, 0x1e, 0x58, 0xa1,
+	0xee, 0x0c, 0xac, 0x90, 0xbc, 0x03, 0xed, 0x28, 0xf6, 0x59, 0xfc, 0xc9, 0x6d, 0xf2, 0xf6, 0xd8,
+	0xc5, 0xfd, 0x3b, 0x51, 0x34, 0x9a, 0x4e, 0x3a, 0x5d, 0xe8, 0xa4, 0x55, 0xcd, 0x79, 0x08, 0x9b,
+	0x55, 0xe5, 0x69, 0x89, 0x59, 0xbf, 0x84, 0x96, 0x2a, 0x42, 0xd8, 0x86, 0x04, 0x1c, 0x7d, 0xa6,
+	0xbb, 0x68, 0x3d, 0x92, 0x4f, 0xe6, 0x9e, 0xb8, 0x4e, 0xee, 0x2b, 0xf8, 0x8d, 0x34, 0x2f, 0x1e,
+	0xab, 0xeb, 0x4a, 0x87, 0xca, 0x6f, 0xbc, 0x08, 0xb2, 0xf0, 0x99, 0x7c, 0x30, 0xeb, 0x50, 0xfc,
+	0x74, 0x1e, 0x41, 0x27, 0xad, 0x56, 0x39, 0x83, 0xac, 0x65, 0x06, 0x7d, 0x04, 0xfd, 0x5c, 0x99,
+	0xba, 0x3b, 0x67, 0x07, 0x56, 0x74, 0x85, 0x42, 0x21, 0xb9, 0x9a, 0x73, 0x77, 0x21, 0x87, 0xd0,
+	0x35, 0x4a, 0xcd, 0xff, 0xba, 0x2b, 0x5f, 0xc3, 0x5a, 0xb1, 0xd8, 0xc8, 0x2b, 0x2a, 0xf6, 0x44,
+	0x07, 0xc6, 0xdf, 0x1c, 0x83, 0x72, 0x77, 0xe1, 0x1f, 0x41, 0x3f, 0x57, 0x7e, 0xee, 0x6e, 0xdc,
+	0x8f, 0x00, 0x92, 0xea, 0x12, 0xc9, 0x8b, 0x97, 0x48, 0x82, 0xb4, 0x49, 0xe5, 0x37, 0xde, 0x3b,
+	0x64, 0x68, 0xe8, 0x5e, 0x54, 0x0d, 0x9c, 0x6b, 0x18, 0xe4, 0x4b, 0xd5, 0x92, 0x2a, 0xf0, 0x31,
+	0x74, 0xbd, 0x74, 0x8d, 0xc4, 0x92, 0xd2, 0xd1, 0x9f, 0xa9, 0x41, 0x4d, 0xb8, 0xf3, 0x27, 0x0b,
+	0xda, 0x49, 0x4d, 0xc3, 0x90, 0xbc, 0x8a, 0xe2, 0xa9, 0x27, 0x74, 0x1e, 0xe9, 0x11, 0xf6, 0xec,
+	0x18, 0x86, 0xa7, 0xd2, 0x07, 0xa1, 0x8e, 0x4c, 0x93, 0xa4, 0xba, 0x7a, 0x99, 0x9a, 0x32, 0xa2,
+	0xeb, 0x49, 0x57, 0x9f, 0x92, 0x10, 0x81, 0xbf, 0xf7, 0xb0, 0x7b, 0x4f, 0x1e, 0x0a, 0x3b, 0xd4,
+	0x24, 0xe1, 0x7e, 0x5d, 0x05, 0x6c, 0xe2, 0xe3, 0xa5, 0x45, 0xb5, 0xd0, 0x1d, 0x6a, 0x50, 0x9c,
+	0x55, 0xe8, 0xe7, 0x4a, 0xa8, 0xfb, 0x43, 0x58, 0xd1, 0x2e, 0xcf, 0xdc, 0x68, 0x19, 0x6e, 0x44,
+	0xaa, 0xdc, 0x8a, 0xc4, 0xb9, 0x72, 0xe0, 0xfe, 0xde, 0x2a, 0xbc, 0xd3, 0x3a, 0xd0, 0xc6, 0xc7,
+	0x47, 0xe3, 0x7e, 0xd5, 0xbe, 0xd2, 0x63, 0xac, 0x2e, 0xd9, 0x93, 0x72, 0xad, 0xf8, 0x86, 0xfb,
+	0x36, 0x0c, 0x4c, 0x49, 0x47, 0xbe, 0xbe, 0x36, 0x0c, 0xfc, 0x1c, 0x15, 0xaf, 0x34, 0x4f, 0x5e,
+	0xf2, 0x22, 0xe5, 0x7e, 0x03, 0x9b, 0x55, 0xed, 0x3d, 0x46, 0xcd, 0xd3, 0xe2, 0x75, 0x9d, 0x40,
+	0xe3, 0xf3, 0x48, 0x5f, 0xaf, 0x3b, 0xb4, 0x81, 0x0f, 0x7a, 0x48, 0x3b, 0xc5, 0x3e, 0xa6, 0x9e,
+	0xfd, 0x6a, 0x33, 0x7e, 0xe3, 0x34, 0xcc, 0xdf, 0x38, 0x7b, 0xdf, 0x5a, 0xd0, 0xfd, 0x6c, 0xc2,
+	0xbc, 0xe9, 0xb1, 0xfc, 0xdb, 0x4b, 0x1e, 0x43, 0xef, 0x33, 0x26, 0xb2, 0xff, 0xae, 0x24, 0xf7,
+	0x80, 0x24, 0x2f, 0xa2, 0xce, 0x66, 0xe1, 0x51, 0x57, 0xfe, 0x4d, 0x73, 0x5f, 0x21, 0xef, 0x42,
+	0xff, 0x8c, 0x85, 0x7e, 0xf6, 0x83, 0xac, 0x8f, 0xc0, 0x74, 0xe8, 0x74, 0x70, 0xa8, 0xfe, 0x51,
+	0xbd, 0xb2, 0x63, 0x91, 0x7d, 0xd8, 0x42, 0x78, 0xd5, 0x4f, 0xa4, 0xad, 0x05, 0xcf, 0xc0, 0x05,
+	0x11, 0x7b, 0x7f, 0xac, 0x01, 0x48, 0xed, 0xf7, 0xf1, 0x4a, 0x48, 0xbe, 0x84, 0x35, 0x29, 0xd1,
+	0x78, 0xb4, 0xd3, 0xa2, 0xca, 0xaf, 0x8a, 0x8e, 0x5d, 0x9e, 0x50, 0x2f, 0x00, 0x28, 0xf9, 0xa1,
+	0x45, 0x1e, 0xc3, 0x8a, 0x5a, 0x9d, 0x91, 0xca, 0x87, 0x6f, 0xe7, 0x5e, 0x81, 0x9a, 0x70, 0x3f,
+	0xb4, 0xc8, 0x4f, 0xc1, 0xd1, 0x47, 0x56, 0xce, 0x00, 0x2c, 0x89, 0x23, 0x4e, 0xca, 0xcf, 0x5b,
+	0x45, 0xd7, 0x1c, 0x41, 0x4b, 0xbd, 0x50, 0x10, 0xd9, 0x3b, 0x2c, 0x7c, 0xde, 0x70, 0x1e, 0x2c,
+	0x9a, 0x4e, 0x94, 0xb9, 0x6c, 0xc9, 0x3f, 0xf7, 0x1f, 0xfc, 0x77, 0x00, 0x64, 0x78, 0xc6, 0x7d,
+	0xcf, 0x1f, 0x00, 0x00,
 }
@@ -1,14 +1,34 @@
 package pb
 
 import (
+	"sync"
 	"time"
 )
 
+// executionsLock guards appending the executions of the task groups.
+var executionsLock sync.Mutex
+
 func (taskGroupStatus *FlowExecutionStatus_TaskGroup) Track(
 	execute func(*FlowExecutionStatus_TaskGroup_Execution) error) error {
 
 	executionStatus := &FlowExecutionStatus_TaskGroup_Execution{}
+	taskGroupStatus.AddExecution(executionStatus)
+	return executionStatus.Track(execute)
+}
+
+// AddExecution records an execution of the task group.
+// Concurrent executions can be added safely.
+func (taskGroupStatus *FlowExecutionStatus_TaskGroup) AddExecution(executionStatus *FlowExecutionStatus_TaskGroup_Execution) {
+	executionsLock.Lock()
+	defer executionsLock.Unlock()
 	taskGroupStatus.Executions = append(taskGroupStatus.Executions, executionStatus)
+}
+
+// Track sets the start time, stop time, and error of the execution,
+// without recording it with the task group.
+func (executionStatus *FlowExecutionStatus_TaskGroup_Execution) Track(
+	execute func(*FlowExecutionStatus_TaskGroup_Execution) error) error {
+
 	executionStatus.StartTime = time.Now().UnixNano()
 	defer func() {
 		executionStatus.StopTime = time.Now().UnixNano()
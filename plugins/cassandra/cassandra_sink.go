@@ -0,0 +1,303 @@
+package cassandra
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/gio"
+	"github.com/gocql/gocql"
+)
+
+const (
+	// DefaultBatchSize is the number of rows written in one batch.
+	DefaultBatchSize = 100
+	// maxPendingBatches limits the partition keys with rows waiting to be batched.
+	// All pending batches are written out when there are more partition keys.
+	maxPendingBatches = 1024
+)
+
+// CassandraSink inserts each row into a table.
+// The rows are batched by their partition keys, and each shard is written by a gio.ShardWriter on the executors.
+// The rows written by each task are counted as the input rows of its InstructionStat.
+type CassandraSink struct {
+	Hosts    string
+	Keyspace string
+	Table    string
+	// Columns maps the row fields to the table columns. The i-th column receives the (i+1)-th field.
+	// The fields with an empty column name are not written.
+	Columns []string
+	// BatchSize is the number of rows with the same partition key written in one unlogged batch.
+	BatchSize int
+	// Concurrency is the number of batches written at the same time by each task.
+	Concurrency    int
+	TimeoutSeconds int
+}
+
+var (
+	MapperWriteShard = gio.RegisterShardWriter(openShardWriter)
+)
+
+func init() {
+	gob.Register(CassandraSink{})
+}
+
+// NewSink creates a CassandraSink writing the row fields to the columns in order.
+func NewSink(hosts, keyspace, table string, columns ...string) *CassandraSink {
+	return &CassandraSink{
+		Hosts:          hosts,
+		Keyspace:       keyspace,
+		Table:          table,
+		Columns:        columns,
+		BatchSize:      DefaultBatchSize,
+		Concurrency:    4,
+		TimeoutSeconds: 16,
+	}
+}
+
+// SetBatchSize sets the number of rows with the same partition key written in one batch.
+func (s *CassandraSink) SetBatchSize(batchSize int) *CassandraSink {
+	s.BatchSize = batchSize
+	return s
+}
+
+// SetConcurrency sets the number of batches written at the same time by each task.
+func (s *CassandraSink) SetConcurrency(concurrency int) *CassandraSink {
+	s.Concurrency = concurrency
+	return s
+}
+
+func (s *CassandraSink) Timeout(seconds int) *CassandraSink {
+	s.TimeoutSeconds = seconds
+	return s
+}
+
+// Save writes the dataset to the table.
+func (s *CassandraSink) Save(d *flow.Dataset) *flow.Dataset {
+	if s.insertCql() == "" {
+		panic(fmt.Sprintf("CassandraSink has no columns to write to %s", s.Table))
+	}
+	if s.BatchSize <= 0 || s.Concurrency <= 0 {
+		panic(fmt.Sprintf("CassandraSink expects positive batch size and concurrency, but got %d and %d", s.BatchSize, s.Concurrency))
+	}
+	return d.WriteShards(MapperWriteShard, encodeSink(s))
+}
+
+func (s *CassandraSink) tableName() string {
+	if s.Keyspace != "" {
+		return s.Keyspace + "." + s.Table
+	}
+	return s.Table
+}
+
+func (s *CassandraSink) insertCql() string {
+	var columns, markers []string
+	for _, column := range s.Columns {
+		if column != "" {
+			columns = append(columns, column)
+			markers = append(markers, "?")
+		}
+	}
+	if len(columns) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", s.tableName(), strings.Join(columns, ","), strings.Join(markers, ","))
+}
+
+// keyIndexes finds the 0-based row fields of the partition keys.
+func (s *CassandraSink) keyIndexes(partitionKeys []string) ([]int, error) {
+	var indexes []int
+	for _, key := range partitionKeys {
+		index := -1
+		for i, column := range s.Columns {
+			if strings.EqualFold(column, key) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("Partition key %s of %s is not in the columns %v", key, s.tableName(), s.Columns)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+func openShardWriter(args []byte) (gio.ShardWriter, error) {
+	s, err := decodeSink(args)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := gocql.NewCluster(strings.Split(s.Hosts, ",")...)
+	cluster.Keyspace = s.Keyspace
+	cluster.ProtoVersion = 4
+	cluster.Timeout = time.Duration(s.TimeoutSeconds) * time.Second
+	cluster.ConnectTimeout = time.Duration(s.TimeoutSeconds) * time.Second
+	cluster.NumConns = s.Concurrency
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("CassandraSink connect to %s %s: %v", s.Hosts, s.Keyspace, err)
+	}
+
+	keyspaceMetadata, err := session.KeyspaceMetadata(s.Keyspace)
+	if err != nil {
+		session.Close()
+		return nil, fmt.Errorf("Can not find keyspace %s: %v", s.Keyspace, err)
+	}
+	t, ok := keyspaceMetadata.Tables[s.Table]
+	if !ok {
+		session.Close()
+		return nil, fmt.Errorf("Can not find table %s in keyspace %s", s.Table, s.Keyspace)
+	}
+	var partitionKeys []string
+	for _, column := range t.PartitionKey {
+		partitionKeys = append(partitionKeys, column.Name)
+	}
+	keyIndexes, err := s.keyIndexes(partitionKeys)
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	insert := s.insertCql()
+	w := newCassandraWriter(s, keyIndexes, func(rows [][]interface{}) error {
+		batch := session.NewBatch(gocql.UnloggedBatch)
+		for _, values := range rows {
+			batch.Query(insert, values...)
+		}
+		return session.ExecuteBatch(batch)
+	})
+	w.session = session
+	return w, nil
+}
+
+// cassandraWriter groups the rows by partition keys,
+// and writes the batches with limited concurrency.
+type cassandraWriter struct {
+	sink       *CassandraSink
+	keyIndexes []int
+	execute    func(rows [][]interface{}) error
+	session    *gocql.Session
+
+	batches map[string][][]interface{}
+	tokens  chan struct{}
+	wg      sync.WaitGroup
+
+	errLock sync.Mutex
+	err     error
+}
+
+func newCassandraWriter(sink *CassandraSink, keyIndexes []int, execute func(rows [][]interface{}) error) *cassandraWriter {
+	return &cassandraWriter{
+		sink:       sink,
+		keyIndexes: keyIndexes,
+		execute:    execute,
+		batches:    make(map[string][][]interface{}),
+		tokens:     make(chan struct{}, sink.Concurrency),
+	}
+}
+
+func (w *cassandraWriter) Write(row []interface{}) error {
+	if err := w.writeError(); err != nil {
+		return err
+	}
+	if len(row) < len(w.sink.Columns) {
+		return fmt.Errorf("Expecting %d fields for columns %v, but the row has %d fields", len(w.sink.Columns), w.sink.Columns, len(row))
+	}
+
+	var values []interface{}
+	for i, column := range w.sink.Columns {
+		if column != "" {
+			values = append(values, row[i])
+		}
+	}
+	var keys []interface{}
+	for _, index := range w.keyIndexes {
+		keys = append(keys, row[index])
+	}
+	key := fmt.Sprintf("%#v", keys)
+
+	w.batches[key] = append(w.batches[key], values)
+	if len(w.batches[key]) >= w.sink.BatchSize {
+		w.writeBatch(w.batches[key])
+		delete(w.batches, key)
+	} else if len(w.batches) > maxPendingBatches {
+		w.flush()
+	}
+	return nil
+}
+
+// writeBatch writes the rows asynchronously, blocking when there are too many batches being written.
+func (w *cassandraWriter) writeBatch(rows [][]interface{}) {
+	w.tokens <- struct{}{}
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			<-w.tokens
+			w.wg.Done()
+		}()
+		if err := w.execute(rows); err != nil {
+			w.errLock.Lock()
+			if w.err == nil {
+				w.err = fmt.Errorf("Failed to write %d rows to %s: %v", len(rows), w.sink.tableName(), err)
+			}
+			w.errLock.Unlock()
+		}
+	}()
+}
+
+func (w *cassandraWriter) flush() {
+	for key, rows := range w.batches {
+		w.writeBatch(rows)
+		delete(w.batches, key)
+	}
+}
+
+func (w *cassandraWriter) writeError() error {
+	w.errLock.Lock()
+	defer w.errLock.Unlock()
+	return w.err
+}
+
+// Commit writes the pending batches, and waits for all batches to be written.
+func (w *cassandraWriter) Commit() error {
+	w.flush()
+	w.Abort()
+	return w.writeError()
+}
+
+// Abort drops the pending batches, and waits for the batches being written.
+// The batches already written are not reverted.
+func (w *cassandraWriter) Abort() error {
+	w.wg.Wait()
+	if w.session != nil {
+		w.session.Close()
+	}
+	return nil
+}
+
+func decodeSink(encodedSink []byte) (*CassandraSink, error) {
+	network := bytes.NewBuffer(encodedSink)
+	dec := gob.NewDecoder(network)
+	var s CassandraSink
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode cassandra sink: %v", err)
+	}
+	return &s, nil
+}
+
+func encodeSink(s *CassandraSink) []byte {
+	var network bytes.Buffer
+	enc := gob.NewEncoder(&network)
+	if err := enc.Encode(s); err != nil {
+		log.Fatal("encode cassandra sink:", err)
+	}
+	return network.Bytes()
+}
@@ -0,0 +1,75 @@
+package cassandra
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestInsertCql(t *testing.T) {
+	s := NewSink("localhost", "shop", "orders", "user_id", "", "amount")
+	if cql := s.insertCql(); cql != "INSERT INTO shop.orders (user_id,amount) VALUES (?,?)" {
+		t.Errorf("Unexpected cql %s", cql)
+	}
+	if indexes, err := s.keyIndexes([]string{"user_id"}); err != nil || fmt.Sprint(indexes) != "[0]" {
+		t.Errorf("Unexpected key indexes %v: %v", indexes, err)
+	}
+	if _, err := s.keyIndexes([]string{"order_id"}); err == nil {
+		t.Errorf("Expect an error for a partition key not in the columns")
+	}
+}
+
+func TestCassandraWriter(t *testing.T) {
+	s := NewSink("localhost", "shop", "orders", "user_id", "", "amount").SetBatchSize(2).SetConcurrency(2)
+
+	var lock sync.Mutex
+	var batches []string
+	var running, maxRunning int32
+	w := newCassandraWriter(s, []int{0}, func(rows [][]interface{}) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		lock.Lock()
+		defer lock.Unlock()
+		if n > maxRunning {
+			maxRunning = n
+		}
+		batches = append(batches, fmt.Sprint(rows))
+		return nil
+	})
+
+	for _, row := range [][]interface{}{
+		{"a", "x", 1}, {"b", "x", 2}, {"a", "x", 3}, {"c", "x", 4}, {"b", "x", 5},
+	} {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("Failed to write %v: %v", row, err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	sort.Strings(batches)
+	if fmt.Sprint(batches) != "[[[a 1] [a 3]] [[b 2] [b 5]] [[c 4]]]" {
+		t.Errorf("Unexpected batches %v", batches)
+	}
+	if maxRunning > 2 {
+		t.Errorf("Expect at most 2 batches at a time, but got %d", maxRunning)
+	}
+
+	if err := w.Write([]interface{}{"a"}); err == nil {
+		t.Errorf("Expect an error for a row without enough fields")
+	}
+}
+
+func TestCassandraWriterError(t *testing.T) {
+	s := NewSink("localhost", "shop", "orders", "user_id", "amount").SetBatchSize(1)
+	w := newCassandraWriter(s, []int{0}, func(rows [][]interface{}) error {
+		return fmt.Errorf("timeout")
+	})
+	w.Write([]interface{}{"a", 1})
+	if err := w.Commit(); err == nil {
+		t.Errorf("Expect the write error when committing")
+	}
+}
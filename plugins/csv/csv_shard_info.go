@@ -14,6 +14,10 @@ type CsvShardInfo struct {
 	Config    map[string]string
 	FileName  string
 	HasHeader bool
+	// Offset and Length are the byte range of a split, or the whole file if Length is 0.
+	Offset     int64
+	Length     int64
+	StartState int
 }
 
 var (
@@ -38,8 +42,17 @@ func (ds *CsvShardInfo) ReadSplit() error {
 	}
 	defer fr.Close()
 
-	reader := NewReader(fr)
-	if ds.HasHeader {
+	var reader *Reader
+	if ds.Length > 0 {
+		sr, err := newSplitReader(fr, ds.Offset, ds.Length, ds.StartState)
+		if err != nil {
+			return fmt.Errorf("Failed to seek file %s to %d: %v", ds.FileName, ds.Offset, err)
+		}
+		reader = NewReader(sr)
+	} else {
+		reader = NewReader(fr)
+	}
+	if ds.HasHeader && ds.Offset == 0 {
 		reader.Read()
 	}
 
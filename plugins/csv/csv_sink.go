@@ -0,0 +1,43 @@
+package csv
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/instruction"
+)
+
+// CsvSink writes each row as a line of comma-separated values.
+// Each shard is written into one file by the executors, the same as Dataset.SaveAs().
+type CsvSink struct {
+	PathPattern string
+	Header      []string
+	Compression string
+}
+
+// NewSink creates a CsvSink writing to the files of the pathPattern,
+// e.g., "/data/out/part-%05d.csv", or a directory for files named as part-00000, etc.
+func NewSink(pathPattern string) *CsvSink {
+	return &CsvSink{
+		PathPattern: pathPattern,
+	}
+}
+
+// SetHeader writes the field names as the first line of each file.
+func (s *CsvSink) SetHeader(fieldNames ...string) *CsvSink {
+	s.Header = fieldNames
+	return s
+}
+
+// SetCompression compresses the files in "gzip", "bzip2", "zstd", or "snappy".
+// By default, the files are compressed by the file extension.
+// Writing "bzip2" runs the bzip2 command, which should be installed on the agents.
+func (s *CsvSink) SetCompression(compression string) *CsvSink {
+	s.Compression = compression
+	return s
+}
+
+// Save writes the dataset to the files.
+func (s *CsvSink) Save(d *flow.Dataset) *flow.Dataset {
+	step := d.Flow.AddOneToOneStep(d, nil)
+	step.SetInstruction(instruction.NewSaveFile("csv", s.Compression, s.PathPattern, s.Header, d.Step.IsPipe))
+	return d
+}
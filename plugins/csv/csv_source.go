@@ -30,6 +30,9 @@ import (
 	"github.com/chrislusf/gleamold/util"
 )
 
+// DefaultSplitSize is the size of the byte ranges that a large file is split into.
+const DefaultSplitSize = 128 * 1024 * 1024
+
 type CsvSource struct {
 	folder         string
 	fileBaseName   string
@@ -37,6 +40,7 @@ type CsvSource struct {
 	Path           string
 	HasHeader      bool
 	PartitionCount int
+	SplitSize      int64
 }
 
 // Generate generates data shard info,
@@ -48,12 +52,20 @@ func (s *CsvSource) Generate(f *flow.Flow) *flow.Dataset {
 
 // New creates a CsvSource based on a file name.
 // The base file name can have "*", "?" pattern denoting a list of file names.
+// The file name can also be on hdfs:// or s3://, e.g., "s3://bucket/logs/*.csv".
 func New(fileOrPattern string, partitionCount int) *CsvSource {
 	s := &CsvSource{
 		PartitionCount: partitionCount,
+		SplitSize:      DefaultSplitSize,
 	}
 
-	if strings.ContainsAny(fileOrPattern, "/\\") {
+	if strings.Contains(fileOrPattern, "://") {
+		// filepath.Dir would clean the "//" in s3:// or hdfs://
+		lastSlash := strings.LastIndex(fileOrPattern, "/")
+		s.folder = fileOrPattern[:lastSlash]
+		s.fileBaseName = fileOrPattern[lastSlash+1:]
+		s.Path = fileOrPattern
+	} else if strings.ContainsAny(fileOrPattern, "/\\") {
 		s.folder = filepath.Dir(fileOrPattern)
 		s.fileBaseName = filepath.Base(fileOrPattern)
 		s.Path = fileOrPattern
@@ -75,31 +87,81 @@ func (q *CsvSource) SetHasHeader(hasHeader bool) *CsvSource {
 	return q
 }
 
+// SetSplitSize sets the size of the byte ranges that a large file is split into,
+// so the ranges of one file can be read in parallel.
+// A file is not split if the size is 0.
+func (q *CsvSource) SetSplitSize(splitSize int64) *CsvSource {
+	q.SplitSize = splitSize
+	return q
+}
+
 func (s *CsvSource) genShardInfos(f *flow.Flow) *flow.Dataset {
 	return f.Source(func(writer io.Writer) error {
-		if !s.hasWildcard && !filesystem.IsDir(s.Path) {
-			util.WriteRow(writer, util.Now(), encodeShardInfo(&CsvShardInfo{
-				FileName:  s.Path,
-				HasHeader: s.HasHeader,
-			}))
-		} else {
-			virtualFiles, err := filesystem.List(s.folder)
-			if err != nil {
-				return fmt.Errorf("Failed to list folder %s: %v", s.folder, err)
-			}
-			for _, vf := range virtualFiles {
-				if !s.hasWildcard || s.match(vf.Location) {
-					util.WriteRow(writer, util.Now(), encodeShardInfo(&CsvShardInfo{
-						FileName:  vf.Location,
-						HasHeader: s.HasHeader,
-					}))
-				}
+		fileNames, err := s.fileNames()
+		if err != nil {
+			return err
+		}
+		for _, fileName := range fileNames {
+			if err := s.genFileShardInfos(writer, fileName); err != nil {
+				return err
 			}
 		}
 		return nil
 	})
 }
 
+// fileNames lists the files matching the pattern, or the files in the directory.
+func (s *CsvSource) fileNames() (fileNames []string, err error) {
+	if !s.hasWildcard && !filesystem.IsDir(s.Path) {
+		return []string{s.Path}, nil
+	}
+	// a directory is listed by itself, not by its parent folder
+	folder := s.folder
+	if !s.hasWildcard {
+		folder = s.Path
+	}
+	virtualFiles, err := filesystem.List(folder)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list folder %s: %v", folder, err)
+	}
+	for _, vf := range virtualFiles {
+		if !s.hasWildcard || s.match(vf.Location) {
+			fileNames = append(fileNames, vf.Location)
+		}
+	}
+	return fileNames, nil
+}
+
+// genFileShardInfos splits an uncompressed file larger than the split size into byte ranges.
+// The file is scanned in parallel to find where each split should start reading,
+// since a quoted field can have new lines.
+func (s *CsvSource) genFileShardInfos(writer io.Writer, fileName string) error {
+	fileInfo, err := filesystem.Stat(fileName)
+	if err != nil || s.SplitSize <= 0 || fileInfo.Size <= s.SplitSize || filesystem.IsCompressed(fileName) {
+		return util.WriteRow(writer, util.Now(), encodeShardInfo(&CsvShardInfo{
+			FileName:  fileName,
+			HasHeader: s.HasHeader,
+		}))
+	}
+
+	offsets, startStates, err := splitFile(fileName, fileInfo.Size, s.SplitSize)
+	if err != nil {
+		return fmt.Errorf("Failed to split file %s: %v", fileName, err)
+	}
+	for i, offset := range offsets {
+		if err := util.WriteRow(writer, util.Now(), encodeShardInfo(&CsvShardInfo{
+			FileName:   fileName,
+			HasHeader:  s.HasHeader,
+			Offset:     offset,
+			Length:     s.SplitSize,
+			StartState: startStates[i],
+		})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *CsvSource) match(fullPath string) bool {
 	baseName := filepath.Base(fullPath)
 	match, _ := filepath.Match(s.fileBaseName, baseName)
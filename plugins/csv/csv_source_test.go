@@ -0,0 +1,47 @@
+package csv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSourceFileNames(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "csv_source")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Mkdir(filepath.Join(dir, "logs"), 0755)
+	for _, name := range []string{"other.csv", "logs/a.csv", "logs/b.csv", "logs/c.txt"} {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte("x\n"), 0644)
+	}
+
+	tests := []struct {
+		fileOrPattern string
+		expected      []string
+	}{
+		{"logs/a.csv", []string{"logs/a.csv"}},
+		{"logs/*.csv", []string{"logs/a.csv", "logs/b.csv"}},
+		{"logs", []string{"logs/a.csv", "logs/b.csv", "logs/c.txt"}},
+	}
+	for _, test := range tests {
+		fileNames, err := New(filepath.Join(dir, test.fileOrPattern), 1).fileNames()
+		if err != nil {
+			t.Fatalf("Failed to list %s: %v", test.fileOrPattern, err)
+		}
+		var expected []string
+		for _, name := range test.expected {
+			expected = append(expected, filepath.Join(dir, name))
+		}
+		sort.Strings(fileNames)
+		if !reflect.DeepEqual(fileNames, expected) {
+			t.Errorf("Expect %s to list %v, but got %v", test.fileOrPattern, expected, fileNames)
+		}
+	}
+}
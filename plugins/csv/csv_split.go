@@ -0,0 +1,210 @@
+package csv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/chrislusf/gleamold/filesystem"
+)
+
+// The parsing states between two bytes, following the Reader.
+// Only recordStart is a boundary where a split can start reading records,
+// since a quoted field can have new lines.
+const (
+	recordStart = iota
+	fieldStart
+	unquoted
+	inDoubleQuotes
+	doubleQuoteInDoubleQuotes
+	inSingleQuotes
+	singleQuoteInSingleQuotes
+	stateCount
+)
+
+var transitions [stateCount][256]byte
+
+func init() {
+	for state := 0; state < stateCount; state++ {
+		for b := 0; b < 256; b++ {
+			transitions[state][b] = byte(nextState(state, byte(b)))
+		}
+	}
+}
+
+func nextState(state int, b byte) int {
+	if b == '\r' {
+		// \r\n is folded to \n
+		return state
+	}
+	switch state {
+	case recordStart, fieldStart:
+		switch b {
+		case '\n':
+			return recordStart
+		case ',':
+			return fieldStart
+		case DOUBLE_QUOTE:
+			return inDoubleQuotes
+		case SINGLE_QUOTE:
+			return inSingleQuotes
+		}
+		return unquoted
+	case unquoted:
+		switch b {
+		case '\n':
+			return recordStart
+		case ',':
+			return fieldStart
+		}
+		return unquoted
+	case inDoubleQuotes:
+		if b == DOUBLE_QUOTE {
+			return doubleQuoteInDoubleQuotes
+		}
+		return inDoubleQuotes
+	case inSingleQuotes:
+		if b == SINGLE_QUOTE {
+			return singleQuoteInSingleQuotes
+		}
+		return inSingleQuotes
+	case doubleQuoteInDoubleQuotes, singleQuoteInSingleQuotes:
+		switch b {
+		case '\n':
+			return recordStart
+		case ',':
+			return fieldStart
+		}
+		// an escaped quote, or a lazy bare quote
+		if state == doubleQuoteInDoubleQuotes {
+			return inDoubleQuotes
+		}
+		return inSingleQuotes
+	}
+	return state
+}
+
+// splitFile splits the file into byte ranges of splitSize,
+// and finds the parsing state at the start of each split.
+// The state at a split depends on all the bytes before it,
+// so all splits are scanned in parallel for the end state of each possible start state,
+// and then chained together from the beginning of the file.
+func splitFile(fileName string, fileSize, splitSize int64) (offsets []int64, startStates []int, err error) {
+	for offset := int64(0); offset < fileSize; offset += splitSize {
+		offsets = append(offsets, offset)
+	}
+	endStates := make([][stateCount]byte, len(offsets))
+
+	var wg sync.WaitGroup
+	var errLock sync.Mutex
+	limit := make(chan bool, runtime.NumCPU())
+	for i, offset := range offsets {
+		wg.Add(1)
+		limit <- true
+		go func(i int, offset int64) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			if scanErr := scanSplit(fileName, offset, splitSize, &endStates[i]); scanErr != nil {
+				errLock.Lock()
+				err = scanErr
+				errLock.Unlock()
+			}
+		}(i, offset)
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state := recordStart
+	for i := range offsets {
+		startStates = append(startStates, state)
+		state = int(endStates[i][state])
+	}
+	return offsets, startStates, nil
+}
+
+// scanSplit runs the state machine from every state over the byte range.
+func scanSplit(fileName string, offset, length int64, endStates *[stateCount]byte) error {
+	file, err := filesystem.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("Failed to open file %s: %v", fileName, err)
+	}
+	defer file.Close()
+
+	for state := range endStates {
+		endStates[state] = byte(state)
+	}
+	reader := bufio.NewReaderSize(io.NewSectionReader(file, offset, length), 1024*1024)
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to read file %s: %v", fileName, err)
+		}
+		for state, s := range endStates {
+			endStates[state] = transitions[s][b]
+		}
+	}
+}
+
+// splitReader reads the records starting within [offset, offset+length),
+// given the parsing state at the offset.
+// It skips the bytes before the first record boundary,
+// and stops at the first record boundary after the byte range.
+type splitReader struct {
+	reader   *bufio.Reader
+	state    int
+	position int64
+	end      int64
+}
+
+func newSplitReader(file filesystem.VirtualFile, offset, length int64, state int) (*splitReader, error) {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := &splitReader{
+		reader:   bufio.NewReader(file),
+		state:    state,
+		position: offset,
+		end:      offset + length,
+	}
+	for r.state != recordStart {
+		b, err := r.reader.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.position++
+		r.state = int(transitions[r.state][b])
+	}
+	return r, nil
+}
+
+func (r *splitReader) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if r.state == recordStart && r.position >= r.end {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, nil
+		}
+		b, err := r.reader.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		p[n] = b
+		n++
+		r.position++
+		r.state = int(transitions[r.state][b])
+	}
+	return n, nil
+}
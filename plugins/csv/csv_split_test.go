@@ -0,0 +1,69 @@
+package csv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/gleamold/filesystem"
+)
+
+func TestReadSplits(t *testing.T) {
+
+	file, err := ioutil.TempFile("", "csv_split")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	content := "a,b,c\n" +
+		"1,\"multi\nline\",x\r\n" +
+		"\n" +
+		"2,'single\n''quoted',\"\"\"\n,\"\n" +
+		"3,plain,\"a,b\"\n" +
+		"4,\"last\nline\",end"
+	file.WriteString(content)
+	file.Close()
+
+	expected := readAll(t, NewReader(openFile(t, file.Name())))
+	if len(expected) != 5 {
+		t.Fatalf("Expect 5 records, but got %d: %q", len(expected), expected)
+	}
+
+	size := int64(len(content))
+	for splitSize := int64(1); splitSize <= size; splitSize++ {
+		offsets, startStates, err := splitFile(file.Name(), size, splitSize)
+		if err != nil {
+			t.Fatalf("Failed to split file: %v", err)
+		}
+		var records [][]string
+		for i, offset := range offsets {
+			reader, err := newSplitReader(openFile(t, file.Name()), offset, splitSize, startStates[i])
+			if err != nil {
+				t.Fatalf("Failed to read split %d+%d: %v", offset, splitSize, err)
+			}
+			records = append(records, readAll(t, NewReader(reader))...)
+		}
+		if fmt.Sprintf("%q", records) != fmt.Sprintf("%q", expected) {
+			t.Errorf("Split size %d: expect %q, but got %q", splitSize, expected, records)
+		}
+	}
+}
+
+func openFile(t *testing.T, fileName string) filesystem.VirtualFile {
+	file, err := filesystem.Open(fileName)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", fileName, err)
+	}
+	return file
+}
+
+func readAll(t *testing.T, reader *Reader) [][]string {
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	return records
+}
@@ -0,0 +1,65 @@
+package jsonl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathElement is either an object key, or an array index.
+type jsonPathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJsonPath parses a simple JSON path, e.g., "$.user.name", "user.name",
+// or "items[0].price". "$" or "" is the whole object.
+func parseJsonPath(path string) (elements []jsonPathElement, err error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	for rest != "" {
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("Missing ] in JSON path %s", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("Bad array index %s in JSON path %s", rest[1:end], path)
+			}
+			elements = append(elements, jsonPathElement{index: index, isIndex: true})
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+			continue
+		}
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("Empty key in JSON path %s", path)
+		}
+		elements = append(elements, jsonPathElement{key: rest[:end]})
+		rest = strings.TrimPrefix(rest[end:], ".")
+	}
+	return elements, nil
+}
+
+// lookup returns the value at the path, or nil if it does not exist.
+func lookup(value interface{}, path []jsonPathElement) interface{} {
+	for _, element := range path {
+		if element.isIndex {
+			list, ok := value.([]interface{})
+			if !ok || element.index >= len(list) {
+				return nil
+			}
+			value = list[element.index]
+		} else {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = object[element.key]
+		}
+	}
+	return value
+}
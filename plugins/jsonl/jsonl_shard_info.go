@@ -0,0 +1,178 @@
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/chrislusf/gleamold/gio"
+	"github.com/chrislusf/gleamold/instruction"
+)
+
+type JsonlShardInfo struct {
+	FileName string
+	// Offset and Length are the byte range of a split, or the whole file if Length is 0.
+	Offset      int64
+	Length      int64
+	Fields      []string
+	MaxBadLines int
+}
+
+// BadLinesCounter is the task counter of the skipped bad lines,
+// summed up in the flow's RunResult.Counter(BadLinesCounter).
+const BadLinesCounter = "jsonl.badLines"
+
+var (
+	MapperReadShard = gio.RegisterMapper(readShard)
+)
+
+func init() {
+	gob.Register(JsonlShardInfo{})
+}
+
+func readShard(row []interface{}) error {
+	encodedShardInfo := row[0].([]byte)
+	return decodeShardInfo(encodedShardInfo).ReadSplit()
+}
+
+func (ds *JsonlShardInfo) ReadSplit() error {
+	badLines, err := ds.readSplit(gio.Emit)
+	if badLines > 0 {
+		if counterErr := gio.AddCounter(BadLinesCounter, int64(badLines)); err == nil {
+			err = counterErr
+		}
+	}
+	return err
+}
+
+// readSplit sends the rows of the split to emit, and returns the number of the skipped bad lines.
+func (ds *JsonlShardInfo) readSplit(emit func(row ...interface{}) error) (badLines int, err error) {
+	var paths [][]jsonPathElement
+	for _, field := range ds.Fields {
+		path, err := parseJsonPath(field)
+		if err != nil {
+			return 0, err
+		}
+		paths = append(paths, path)
+	}
+
+	var firstBadLineErr error
+	processLine := func(line []byte) error {
+		row, err := decodeLine(line, paths)
+		if err != nil {
+			badLines++
+			if firstBadLineErr == nil {
+				firstBadLineErr = err
+			}
+			if ds.MaxBadLines >= 0 && badLines > ds.MaxBadLines {
+				return fmt.Errorf("More than %d bad lines in %s: %v", ds.MaxBadLines, ds.FileName, firstBadLineErr)
+			}
+			return nil
+		}
+		if row == nil {
+			return nil
+		}
+		return emit(row...)
+	}
+
+	if ds.Length > 0 {
+		err = instruction.ReadLinesInSplit(ds.FileName, ds.Offset, ds.Length, processLine)
+	} else {
+		err = readLines(ds.FileName, processLine)
+	}
+	if badLines > 0 {
+		log.Printf("Skipped %d bad lines in %s from %d: %v", badLines, ds.FileName, ds.Offset, firstBadLineErr)
+	}
+	return badLines, err
+}
+
+func readLines(fileName string, fn func([]byte) error) error {
+	file, err := filesystem.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("Failed to open file %s: %v", fileName, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Failed to read file %s: %v", fileName, err)
+	}
+	return nil
+}
+
+// decodeLine returns the values at the paths, or the whole object if no paths.
+// A blank line has no row.
+func decodeLine(line []byte, paths [][]jsonPathElement) ([]interface{}, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var object interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("Unexpected data after the JSON value: %s", line)
+	}
+	object = toRowValue(object)
+	if len(paths) == 0 {
+		return []interface{}{object}, nil
+	}
+	row := make([]interface{}, len(paths))
+	for i, path := range paths {
+		row[i] = lookup(object, path)
+	}
+	return row, nil
+}
+
+// toRowValue converts the numbers to int64 if possible, or float64.
+func toRowValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, x := range v {
+			v[key] = toRowValue(x)
+		}
+	case []interface{}:
+		for i, x := range v {
+			v[i] = toRowValue(x)
+		}
+	}
+	return value
+}
+
+func decodeShardInfo(encodedShardInfo []byte) *JsonlShardInfo {
+	network := bytes.NewBuffer(encodedShardInfo)
+	dec := gob.NewDecoder(network)
+	var p JsonlShardInfo
+	if err := dec.Decode(&p); err != nil {
+		log.Fatal("decode shard info", err)
+	}
+	return &p
+}
+
+func encodeShardInfo(shardInfo *JsonlShardInfo) []byte {
+	var network bytes.Buffer
+	enc := gob.NewEncoder(&network)
+	if err := enc.Encode(shardInfo); err != nil {
+		log.Fatal("encode shard info:", err)
+	}
+	return network.Bytes()
+}
@@ -0,0 +1,40 @@
+package jsonl
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/instruction"
+)
+
+// JsonlSink writes each row as a JSON object with the named fields, one object per line.
+// Each shard is written into one file by the executors, the same as Dataset.SaveAs().
+type JsonlSink struct {
+	PathPattern string
+	FieldNames  []string
+	Compression string
+}
+
+// NewSink creates a JsonlSink writing to the files of the pathPattern,
+// e.g., "/data/out/part-%05d.jsonl", or a directory for files named as part-00000, etc.
+// A row of one map is written as the JSON object if there are no field names.
+// The fields without names are named by their 1-based positions.
+func NewSink(pathPattern string, fieldNames ...string) *JsonlSink {
+	return &JsonlSink{
+		PathPattern: pathPattern,
+		FieldNames:  fieldNames,
+	}
+}
+
+// SetCompression compresses the files in "gzip", "bzip2", "zstd", or "snappy".
+// By default, the files are compressed by the file extension.
+// Writing "bzip2" runs the bzip2 command, which should be installed on the agents.
+func (s *JsonlSink) SetCompression(compression string) *JsonlSink {
+	s.Compression = compression
+	return s
+}
+
+// Save writes the dataset to the files.
+func (s *JsonlSink) Save(d *flow.Dataset) *flow.Dataset {
+	step := d.Flow.AddOneToOneStep(d, nil)
+	step.SetInstruction(instruction.NewSaveFile("jsonl", s.Compression, s.PathPattern, s.FieldNames, d.Step.IsPipe))
+	return d
+}
@@ -0,0 +1,156 @@
+/*
+Package jsonl reads and writes JSON Lines files, i.e., one JSON object per line.
+
+The source follows the same steps as the csv plugin:
+1) generate a list of shard info on the driver, one per file or per byte range of a large file.
+2) send each piece of shard info to a remote executor.
+3) each executor reads the lines of the shard info via a mapper.
+
+Since the mapper to process shard info is in Go, the call to "gio.Init()"
+is required.
+*/
+package jsonl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/util"
+)
+
+// DefaultSplitSize is the size of the byte ranges that a large file is split into.
+const DefaultSplitSize = 128 * 1024 * 1024
+
+type JsonlSource struct {
+	folder         string
+	fileBaseName   string
+	hasWildcard    bool
+	Path           string
+	PartitionCount int
+	SplitSize      int64
+	Fields         []string
+	MaxBadLines    int
+}
+
+// Generate generates data shard info,
+// partitions them via round robin,
+// and reads each shard on each executor
+func (s *JsonlSource) Generate(f *flow.Flow) *flow.Dataset {
+	return s.genShardInfos(f).RoundRobin(s.PartitionCount).Mapper(MapperReadShard)
+}
+
+// New creates a JsonlSource based on a file name.
+// The base file name can have "*", "?" pattern denoting a list of file names.
+// The file name can also be on hdfs:// or s3://, e.g., "s3://bucket/events/*.jsonl.gz".
+// By default, each JSON object is one field of a row, and the bad lines are skipped.
+func New(fileOrPattern string, partitionCount int) *JsonlSource {
+	s := &JsonlSource{
+		PartitionCount: partitionCount,
+		SplitSize:      DefaultSplitSize,
+		MaxBadLines:    -1,
+	}
+
+	if strings.Contains(fileOrPattern, "://") {
+		lastSlash := strings.LastIndex(fileOrPattern, "/")
+		s.folder = fileOrPattern[:lastSlash]
+		s.fileBaseName = fileOrPattern[lastSlash+1:]
+		s.Path = fileOrPattern
+	} else if strings.ContainsAny(fileOrPattern, "/\\") {
+		s.folder = filepath.Dir(fileOrPattern)
+		s.fileBaseName = filepath.Base(fileOrPattern)
+		s.Path = fileOrPattern
+	} else {
+		s.folder, _ = os.Getwd()
+		s.fileBaseName = fileOrPattern
+		s.Path = filepath.Join(s.folder, s.fileBaseName)
+	}
+	if strings.ContainsAny(s.fileBaseName, "*?") {
+		s.hasWildcard = true
+	}
+
+	return s
+}
+
+// Select extracts the values by the JSON paths as the fields of each row,
+// e.g., "id", "user.name", "items[0].price", or "$" for the whole object.
+// A missing value is nil. Nested objects and arrays are maps and slices.
+func (s *JsonlSource) Select(paths ...string) *JsonlSource {
+	s.Fields = paths
+	return s
+}
+
+// SetSplitSize sets the size of the byte ranges that a large file is split into,
+// so the ranges of one file can be read in parallel.
+// A file is not split if the size is 0.
+func (s *JsonlSource) SetSplitSize(splitSize int64) *JsonlSource {
+	s.SplitSize = splitSize
+	return s
+}
+
+// SetMaxBadLines fails the shard reading if it has more lines that are not valid JSON.
+// The bad lines are skipped, logged, and counted in the flow's RunResult.Counter(BadLinesCounter). A negative value means no limit.
+func (s *JsonlSource) SetMaxBadLines(maxBadLines int) *JsonlSource {
+	s.MaxBadLines = maxBadLines
+	return s
+}
+
+func (s *JsonlSource) genShardInfos(f *flow.Flow) *flow.Dataset {
+	return f.Source(func(writer io.Writer) error {
+		for _, path := range s.Fields {
+			if _, err := parseJsonPath(path); err != nil {
+				return fmt.Errorf("Failed to select %s: %v", path, err)
+			}
+		}
+		if !s.hasWildcard && !filesystem.IsDir(s.Path) {
+			return s.genFileShardInfos(writer, s.Path)
+		}
+		folder := s.folder
+		if !s.hasWildcard {
+			folder = s.Path
+		}
+		virtualFiles, err := filesystem.List(folder)
+		if err != nil {
+			return fmt.Errorf("Failed to list folder %s: %v", folder, err)
+		}
+		for _, vf := range virtualFiles {
+			if !s.hasWildcard || s.match(vf.Location) {
+				if err := s.genFileShardInfos(writer, vf.Location); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// genFileShardInfos splits an uncompressed file larger than the split size into byte ranges.
+// JSON strings can not have new lines, so each split has the lines starting within it.
+func (s *JsonlSource) genFileShardInfos(writer io.Writer, fileName string) error {
+	shardInfo := &JsonlShardInfo{
+		FileName:    fileName,
+		Fields:      s.Fields,
+		MaxBadLines: s.MaxBadLines,
+	}
+	fileInfo, err := filesystem.Stat(fileName)
+	if err != nil || s.SplitSize <= 0 || fileInfo.Size <= s.SplitSize || filesystem.IsCompressed(fileName) {
+		return util.WriteRow(writer, util.Now(), encodeShardInfo(shardInfo))
+	}
+	for offset := int64(0); offset < fileInfo.Size; offset += s.SplitSize {
+		shardInfo.Offset, shardInfo.Length = offset, s.SplitSize
+		if err := util.WriteRow(writer, util.Now(), encodeShardInfo(shardInfo)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JsonlSource) match(fullPath string) bool {
+	baseName := filepath.Base(fullPath)
+	match, _ := filepath.Match(s.fileBaseName, baseName)
+	return match
+}
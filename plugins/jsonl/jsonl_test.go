@@ -0,0 +1,215 @@
+package jsonl
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/gio"
+	"github.com/chrislusf/gleamold/util"
+)
+
+// TestMain runs the mappers when the test binary is started by a flow,
+// from the folder of the test binary, as the flow starts it by its base name.
+func TestMain(m *testing.M) {
+	gio.Init()
+	if err := os.Chdir(filepath.Dir(os.Args[0])); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to change to the test binary folder: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestDecodeLine(t *testing.T) {
+
+	var paths [][]jsonPathElement
+	for _, field := range []string{"id", "$.user.name", "items[1].price", "missing.key", "$"} {
+		path, err := parseJsonPath(field)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", field, err)
+		}
+		paths = append(paths, path)
+	}
+
+	row, err := decodeLine([]byte(`{"id": 7, "user": {"name": "x"}, "items": [{"price": 1}, {"price": 2.5}]}`), paths)
+	if err != nil {
+		t.Fatalf("Failed to decode line: %v", err)
+	}
+	if row[0] != int64(7) || row[1] != "x" || row[2] != 2.5 || row[3] != nil {
+		t.Errorf("Unexpected row %v", row)
+	}
+	if object, ok := row[4].(map[string]interface{}); !ok || len(object) != 3 {
+		t.Errorf("Expect the whole object, but got %v", row[4])
+	}
+
+	if row, err = decodeLine([]byte("  "), paths); row != nil || err != nil {
+		t.Errorf("Expect no row for a blank line, but got %v: %v", row, err)
+	}
+	for _, badLine := range []string{`{"id": 1`, `{"id": 1} 2`, `not json`} {
+		if _, err = decodeLine([]byte(badLine), paths); err == nil {
+			t.Errorf("Expect an error for %s", badLine)
+		}
+	}
+}
+
+func TestParseJsonPath(t *testing.T) {
+	path, err := parseJsonPath("a.b[0][2].c")
+	if err != nil || fmt.Sprintf("%v", path) != "[{a 0 false} {b 0 false} { 0 true} { 2 true} {c 0 false}]" {
+		t.Errorf("Unexpected path %v: %v", path, err)
+	}
+	for _, badPath := range []string{"a[", "a[x]", "a..b"} {
+		if _, err := parseJsonPath(badPath); err == nil {
+			t.Errorf("Expect an error for %s", badPath)
+		}
+	}
+}
+
+func writeTempFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "jsonl")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	file.WriteString(content)
+	file.Close()
+	return file.Name()
+}
+
+// readRows reads the rows of the file in splits of the size, or the whole file if 0.
+// It also returns the number of the skipped bad lines.
+func readRows(fileName string, size, splitSize int64, maxBadLines int) (rows []string, badLines int, err error) {
+	emit := func(row ...interface{}) error {
+		rows = append(rows, fmt.Sprintf("%v", row))
+		return nil
+	}
+	if splitSize == 0 {
+		shardInfo := &JsonlShardInfo{FileName: fileName, Fields: []string{"id"}, MaxBadLines: maxBadLines}
+		badLines, err = shardInfo.readSplit(emit)
+		return
+	}
+	for offset := int64(0); offset < size; offset += splitSize {
+		shardInfo := &JsonlShardInfo{FileName: fileName, Fields: []string{"id"}, MaxBadLines: maxBadLines,
+			Offset: offset, Length: splitSize}
+		count, err := shardInfo.readSplit(emit)
+		badLines += count
+		if err != nil {
+			return rows, badLines, err
+		}
+	}
+	return
+}
+
+func TestReadSplit(t *testing.T) {
+
+	content := `{"id": 1}` + "\n" +
+		"\n" +
+		`{"id": 22, "name": "a longer line"}` + "\r\n" +
+		`{"id": 333}` + "\n" +
+		"  \n" +
+		`{"id": 4444}`
+	fileName := writeTempFile(t, content)
+	defer os.Remove(fileName)
+
+	size := int64(len(content))
+	expected, _, err := readRows(fileName, size, 0, 0)
+	if err != nil || len(expected) != 4 {
+		t.Fatalf("Expect 4 rows, but got %v: %v", expected, err)
+	}
+	for splitSize := int64(1); splitSize <= size; splitSize++ {
+		rows, _, err := readRows(fileName, size, splitSize, 0)
+		if err != nil {
+			t.Fatalf("Split size %d: %v", splitSize, err)
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("Split size %d: expect %v, but got %v", splitSize, expected, rows)
+		}
+	}
+}
+
+func TestMaxBadLines(t *testing.T) {
+
+	content := `{"id": 1}` + "\n" +
+		`{"id": ` + "\n" +
+		`{"id": 2}` + "\n" +
+		`not json` + "\n" +
+		`{"id": 3}` + "\n"
+	fileName := writeTempFile(t, content)
+	defer os.Remove(fileName)
+
+	size := int64(len(content))
+	tests := []struct {
+		maxBadLines int
+		splitSize   int64
+		isFailed    bool
+	}{
+		{-1, 0, false},
+		{2, 0, false},
+		{1, 0, true},
+		{0, 0, true},
+		// the limit is for each split
+		{1, size / 2, false},
+	}
+	for _, test := range tests {
+		rows, badLines, err := readRows(fileName, size, test.splitSize, test.maxBadLines)
+		if test.isFailed {
+			if err == nil {
+				t.Errorf("Expect more than %d bad lines to fail", test.maxBadLines)
+			}
+			continue
+		}
+		if err != nil || len(rows) != 3 {
+			t.Errorf("Expect 3 rows with at most %d bad lines, but got %v: %v", test.maxBadLines, rows, err)
+		}
+		if badLines != 2 {
+			t.Errorf("Expect 2 bad lines to be counted, but got %d", badLines)
+		}
+	}
+}
+
+func TestSelectBadPath(t *testing.T) {
+	fileName := writeTempFile(t, `{"id": 1}`)
+	defer os.Remove(fileName)
+
+	f := flow.New()
+	New(fileName, 1).Select("a[").genShardInfos(f).Output(func(reader io.Reader) error {
+		_, err := io.Copy(ioutil.Discard, reader)
+		return err
+	})
+	if _, err := f.Run(); err == nil {
+		t.Errorf("Expect a bad JSON path to fail the flow")
+	}
+}
+
+func TestBadLinesCounter(t *testing.T) {
+	content := `{"id": 1}` + "\n" +
+		`{"id": ` + "\n" +
+		`{"id": 2}` + "\n" +
+		`not json` + "\n" +
+		`{"id": 3}` + "\n" +
+		`[1, 2` + "\n"
+	fileName := writeTempFile(t, content)
+	defer os.Remove(fileName)
+
+	f := flow.New()
+	var rows int
+	New(fileName, 2).Select("id").SetSplitSize(int64(len(content) / 2)).Generate(f).Output(func(reader io.Reader) error {
+		return util.ProcessMessage(reader, func(data []byte) error {
+			rows++
+			return nil
+		})
+	})
+	result, err := f.Run()
+	if err != nil {
+		t.Fatalf("Failed to run the flow: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("Expect 3 rows, but got %d", rows)
+	}
+	if count := result.Counter(BadLinesCounter); count != 3 {
+		t.Errorf("Expect 3 bad lines to be counted, but got %d", count)
+	}
+}
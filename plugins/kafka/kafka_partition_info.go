@@ -3,6 +3,7 @@ package kafka
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
 	"log"
 	"time"
 
@@ -10,12 +11,21 @@ import (
 	"github.com/chrislusf/gleamold/gio"
 )
 
+// idleFetches is the number of fetch waits without messages for a bounded read
+// to decide the remaining offsets before the end offset have no messages.
+const idleFetches = 4
+
 type KafkaPartitionInfo struct {
 	Brokers        []string
 	Topic          string
 	Group          string
 	TimeoutSeconds int
 	PartitionId    int32
+	// StartOffset is the first offset to read, or sarama.OffsetOldest.
+	StartOffset int64
+	// EndOffset is the offset to stop before, if IsBounded.
+	EndOffset int64
+	IsBounded bool
 }
 
 var (
@@ -32,12 +42,22 @@ func readShard(row []interface{}) error {
 }
 
 func (s *KafkaPartitionInfo) ReadSplit() error {
+	return s.readPartition(func(ts int64, value []byte) error {
+		return gio.TsEmit(ts, value)
+	})
+}
+
+// readPartition reads the messages from the start offset.
+// The bounded reads stop before the end offset, and leave the offsets to be committed by the driver.
+// Since the last offsets before the end offset may have no messages, e.g., removed by compaction
+// or used by transaction markers, the bounded reads also stop once the high water mark
+// reaches the end offset and no messages arrive for idleFetches fetch waits.
+// The unbounded reads mark the offsets of the consumer group as the messages are read.
+func (s *KafkaPartitionInfo) readPartition(emit func(ts int64, value []byte) error) error {
 
 	// println("brokers:", s.Brokers)
-	config := sarama.NewConfig()
-	config.Net.DialTimeout = time.Duration(s.TimeoutSeconds) * time.Second
-	config.Net.ReadTimeout = time.Duration(s.TimeoutSeconds) * time.Second
-	config.Net.WriteTimeout = time.Duration(s.TimeoutSeconds) * time.Second
+	config := newConfig(s.TimeoutSeconds)
+	config.Consumer.Return.Errors = s.IsBounded
 
 	c, err := sarama.NewClient(s.Brokers, config)
 	if err != nil {
@@ -46,19 +66,26 @@ func (s *KafkaPartitionInfo) ReadSplit() error {
 	}
 	defer c.Close()
 
-	offsetManager, err := sarama.NewOffsetManagerFromClient(s.Group, c)
-	if err != nil {
-		log.Printf("Kafka NewOffsetManagerFromClient error: %v", err)
-		return err
+	if s.IsBounded && s.StartOffset >= s.EndOffset {
+		return nil
 	}
-	defer offsetManager.Close()
 
-	partitionOffsetManager, err := offsetManager.ManagePartition(s.Topic, s.PartitionId)
-	if err != nil {
-		log.Printf("Kafka ManagePartition error: %v", err)
-		return err
+	var partitionOffsetManager sarama.PartitionOffsetManager
+	if !s.IsBounded {
+		offsetManager, err := sarama.NewOffsetManagerFromClient(s.Group, c)
+		if err != nil {
+			log.Printf("Kafka NewOffsetManagerFromClient error: %v", err)
+			return err
+		}
+		defer offsetManager.Close()
+
+		partitionOffsetManager, err = offsetManager.ManagePartition(s.Topic, s.PartitionId)
+		if err != nil {
+			log.Printf("Kafka ManagePartition error: %v", err)
+			return err
+		}
+		defer partitionOffsetManager.Close()
 	}
-	defer partitionOffsetManager.Close()
 
 	consumer, err := sarama.NewConsumerFromClient(c)
 	if err != nil {
@@ -67,25 +94,60 @@ func (s *KafkaPartitionInfo) ReadSplit() error {
 	}
 	defer consumer.Close()
 
-	pc, err := consumer.ConsumePartition(s.Topic, s.PartitionId, sarama.OffsetOldest)
+	pc, err := consumer.ConsumePartition(s.Topic, s.PartitionId, s.StartOffset)
 	if err != nil {
 		log.Printf("Kafka Partition %d, error: %v", s.PartitionId, err)
 		return err
 	}
 	defer pc.Close()
 
-	for msg := range pc.Messages() {
-		if msg == nil {
-			continue
+	var idleCheck <-chan time.Time
+	if s.IsBounded {
+		ticker := time.NewTicker(idleFetches * config.Consumer.MaxWaitTime)
+		defer ticker.Stop()
+		idleCheck = ticker.C
+	}
+	var isIdle bool
+
+	errors := pc.Errors()
+	for {
+		select {
+		case msg, ok := <-pc.Messages():
+			if !ok {
+				return nil
+			}
+			if msg == nil {
+				continue
+			}
+			isIdle = false
+			if s.IsBounded && msg.Offset >= s.EndOffset {
+				return nil
+			}
+
+			if partitionOffsetManager != nil {
+				partitionOffsetManager.MarkOffset(msg.Offset, "")
+			}
+			ts := msg.Timestamp.UnixNano() / int64(time.Millisecond)
+			if err := emit(ts, msg.Value); err != nil {
+				return err
+			}
+			if s.IsBounded && msg.Offset+1 >= s.EndOffset {
+				return nil
+			}
+		case <-idleCheck:
+			if isIdle && len(pc.Messages()) == 0 && pc.HighWaterMarkOffset() >= s.EndOffset {
+				return nil
+			}
+			isIdle = true
+		case consumerError, ok := <-errors:
+			if !ok {
+				errors = nil
+				continue
+			}
+			return fmt.Errorf("Kafka Partition %d: %v", s.PartitionId, consumerError.Err)
 		}
-
-		partitionOffsetManager.MarkOffset(msg.Offset, "")
-		ts := msg.Timestamp.UnixNano() / int64(time.Millisecond)
-		gio.TsEmit(ts, msg.Value)
 	}
 
-	return nil
-
 }
 
 func decodeShardInfo(encodedShardInfo []byte) *KafkaPartitionInfo {
@@ -0,0 +1,183 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"log"
+
+	"github.com/Shopify/sarama"
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/gio"
+)
+
+// DefaultBatchSize is the number of messages sent to the brokers at a time.
+const DefaultBatchSize = 1000
+
+// KafkaSink writes each row as a message to a topic.
+// Each shard is written by a gio.ShardWriter on the executors.
+type KafkaSink struct {
+	Brokers        []string
+	Topic          string
+	TimeoutSeconds int
+	// KeyField and ValueField are 1-based row field indexes. The message has no key if KeyField is 0.
+	KeyField   int
+	ValueField int
+	BatchSize  int
+}
+
+var (
+	MapperWriteShard = gio.RegisterShardWriter(openShardWriter)
+)
+
+func init() {
+	gob.Register(KafkaSink{})
+}
+
+// NewSink creates a KafkaSink writing the first field of each row as the message value.
+func NewSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		Brokers:        brokers,
+		Topic:          topic,
+		TimeoutSeconds: 16,
+		ValueField:     1,
+		BatchSize:      DefaultBatchSize,
+	}
+}
+
+// Key uses the 1-based field of each row as the message key,
+// which also chooses the partition.
+func (s *KafkaSink) Key(field int) *KafkaSink {
+	s.KeyField = field
+	return s
+}
+
+// Value uses the 1-based field of each row as the message value.
+func (s *KafkaSink) Value(field int) *KafkaSink {
+	s.ValueField = field
+	return s
+}
+
+func (s *KafkaSink) Timeout(seconds int) *KafkaSink {
+	s.TimeoutSeconds = seconds
+	return s
+}
+
+// SetBatchSize sets the number of messages sent to the brokers at a time.
+func (s *KafkaSink) SetBatchSize(batchSize int) *KafkaSink {
+	s.BatchSize = batchSize
+	return s
+}
+
+// Save writes the dataset to the topic.
+// The strings and bytes are written as is, and other values are formatted as strings.
+func (s *KafkaSink) Save(d *flow.Dataset) *flow.Dataset {
+	if s.ValueField <= 0 || s.KeyField < 0 {
+		panic(fmt.Sprintf("KafkaSink expects 1-based key and value fields, but got key %d value %d", s.KeyField, s.ValueField))
+	}
+	return d.WriteShards(MapperWriteShard, encodeSink(s))
+}
+
+func openShardWriter(args []byte) (gio.ShardWriter, error) {
+	s, err := decodeSink(args)
+	if err != nil {
+		return nil, err
+	}
+	config := newConfig(s.TimeoutSeconds)
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer(s.Brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to %v: %v", s.Brokers, err)
+	}
+	return newKafkaWriter(s, producer), nil
+}
+
+// kafkaWriter sends the messages in batches.
+type kafkaWriter struct {
+	sink     *KafkaSink
+	producer sarama.SyncProducer
+	messages []*sarama.ProducerMessage
+}
+
+func newKafkaWriter(sink *KafkaSink, producer sarama.SyncProducer) *kafkaWriter {
+	return &kafkaWriter{sink: sink, producer: producer}
+}
+
+func (w *kafkaWriter) Write(row []interface{}) error {
+	if w.sink.ValueField > len(row) || w.sink.KeyField > len(row) {
+		return fmt.Errorf("Expecting key field %d and value field %d, but the row has %d fields", w.sink.KeyField, w.sink.ValueField, len(row))
+	}
+	message := &sarama.ProducerMessage{
+		Topic: w.sink.Topic,
+		Value: sarama.ByteEncoder(toBytes(row[w.sink.ValueField-1])),
+	}
+	if w.sink.KeyField > 0 {
+		message.Key = sarama.ByteEncoder(toBytes(row[w.sink.KeyField-1]))
+	}
+	w.messages = append(w.messages, message)
+	if len(w.messages) >= w.sink.BatchSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *kafkaWriter) flush() error {
+	if len(w.messages) == 0 {
+		return nil
+	}
+	if err := w.producer.SendMessages(w.messages); err != nil {
+		return fmt.Errorf("Failed to send %d messages to %s: %v", len(w.messages), w.sink.Topic, err)
+	}
+	w.messages = w.messages[:0]
+	return nil
+}
+
+// Commit sends the remaining messages.
+func (w *kafkaWriter) Commit() error {
+	err := w.flush()
+	if closeErr := w.Abort(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Abort closes the producer without sending the remaining messages.
+// The messages already sent are not revoked.
+func (w *kafkaWriter) Abort() error {
+	if err := w.producer.Close(); err != nil {
+		return fmt.Errorf("Failed to close producer to %s: %v", w.sink.Topic, err)
+	}
+	return nil
+}
+
+func toBytes(field interface{}) []byte {
+	switch v := field.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	case nil:
+		return nil
+	}
+	return []byte(fmt.Sprint(field))
+}
+
+func decodeSink(encodedSink []byte) (*KafkaSink, error) {
+	network := bytes.NewBuffer(encodedSink)
+	dec := gob.NewDecoder(network)
+	var s KafkaSink
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode kafka sink: %v", err)
+	}
+	return &s, nil
+}
+
+func encodeSink(s *KafkaSink) []byte {
+	var network bytes.Buffer
+	enc := gob.NewEncoder(&network)
+	if err := enc.Encode(s); err != nil {
+		log.Fatal("encode kafka sink:", err)
+	}
+	return network.Bytes()
+}
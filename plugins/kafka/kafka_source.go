@@ -16,52 +16,237 @@ type KafkaSource struct {
 	Group          string
 	Topic          string
 	TimeoutSeconds int
+
+	// StartOffsets, StartTime, and StartFromCommitted choose the first offset to read.
+	// By default, each partition is read from the oldest offset.
+	StartOffsets       map[int32]int64
+	StartTime          time.Time
+	StartFromCommitted bool
+
+	// EndOffsets, EndTime, and StopAtNewest bound the reads to stop before the end offset.
+	// By default, each partition is read without an end.
+	EndOffsets   map[int32]int64
+	EndTime      time.Time
+	StopAtNewest bool
+
+	// resolvedEndOffsets are the end offsets of the bounded reads, to be committed.
+	resolvedEndOffsets map[int32]int64
 }
 
 // Generate generates data shard info,
 // partitions them via round robin,
 // and reads each shard on each executor
 func (s *KafkaSource) Generate(f *flow.Flow) *flow.Dataset {
-	partitionIds, err := s.fetchPartitionIds()
+	c, err := s.newClient()
+	if err != nil {
+		log.Printf("KafkaSource failed to connect to kafka: %v", err)
+		return nil
+	}
+	defer c.Close()
+
+	partitionInfos, err := s.fetchPartitionInfos(c)
 	if err != nil {
 		log.Printf("KafkaSource failed to fetch kafka partitions: %v", err)
 		return nil
 	}
-	return s.genShardInfos(f, partitionIds).RoundRobin(len(partitionIds)).Mapper(MapperReadShard)
+	return s.genShardInfos(f, partitionInfos).RoundRobin(len(partitionInfos)).Mapper(MapperReadShard)
 }
 
-func (s *KafkaSource) fetchPartitionIds() ([]int32, error) {
-	config := sarama.NewConfig()
-	config.Net.DialTimeout = time.Duration(s.TimeoutSeconds) * time.Second
-	config.Net.ReadTimeout = time.Duration(s.TimeoutSeconds) * time.Second
-	config.Net.WriteTimeout = time.Duration(s.TimeoutSeconds) * time.Second
-
-	c, err := sarama.NewClient(s.Brokers, config)
+func (s *KafkaSource) newClient() (sarama.Client, error) {
+	c, err := sarama.NewClient(s.Brokers, newConfig(s.TimeoutSeconds))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to connect to %v: %v", s.Brokers, err)
 	}
-	defer c.Close()
+	return c, nil
+}
+
+func newConfig(timeoutSeconds int) *sarama.Config {
+	config := sarama.NewConfig()
+	// the message timestamps and the offsets by time need at least 0.10.1
+	config.Version = sarama.V0_10_1_0
+	config.Net.DialTimeout = time.Duration(timeoutSeconds) * time.Second
+	config.Net.ReadTimeout = time.Duration(timeoutSeconds) * time.Second
+	config.Net.WriteTimeout = time.Duration(timeoutSeconds) * time.Second
+	return config
+}
 
+func (s *KafkaSource) isBounded() bool {
+	return s.EndOffsets != nil || !s.EndTime.IsZero() || s.StopAtNewest
+}
+
+// fetchPartitionInfos resolves the start and end offsets of each partition on the driver,
+// so the bounded reads are repeatable, and the end offsets can be committed after the flow succeeds.
+func (s *KafkaSource) fetchPartitionInfos(c sarama.Client) (partitionInfos []*KafkaPartitionInfo, err error) {
 	// the partition ids for a topic
 	partitionIds, err := c.Partitions(s.Topic)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to list partitions of %v: %v", s.Topic, err)
 	}
 
-	return partitionIds, nil
+	var committedOffsets map[int32]int64
+	if s.StartFromCommitted {
+		if committedOffsets, err = s.fetchCommittedOffsets(c, partitionIds); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.isBounded() {
+		s.resolvedEndOffsets = make(map[int32]int64)
+	}
+	for _, pid := range partitionIds {
+		partitionInfo := &KafkaPartitionInfo{
+			Brokers:        s.Brokers,
+			Topic:          s.Topic,
+			Group:          s.Group,
+			TimeoutSeconds: s.TimeoutSeconds,
+			PartitionId:    pid,
+		}
+		if partitionInfo.StartOffset, err = s.startOffset(c, pid, committedOffsets); err != nil {
+			return nil, err
+		}
+		if s.isBounded() {
+			partitionInfo.IsBounded = true
+			if partitionInfo.EndOffset, err = s.endOffset(c, pid); err != nil {
+				return nil, err
+			}
+			s.resolvedEndOffsets[pid] = partitionInfo.EndOffset
+		}
+		partitionInfos = append(partitionInfos, partitionInfo)
+	}
+
+	return partitionInfos, nil
+}
+
+func (s *KafkaSource) startOffset(c sarama.Client, pid int32, committedOffsets map[int32]int64) (int64, error) {
+	if offset, found := s.StartOffsets[pid]; found {
+		return offset, nil
+	}
+	if offset, found := committedOffsets[pid]; found && offset >= 0 {
+		return offset, nil
+	}
+	if !s.StartTime.IsZero() {
+		return offsetAtTime(c, s.Topic, pid, s.StartTime)
+	}
+	if !s.isBounded() {
+		return sarama.OffsetOldest, nil
+	}
+	return getOffset(c, s.Topic, pid, sarama.OffsetOldest)
+}
+
+func (s *KafkaSource) endOffset(c sarama.Client, pid int32) (int64, error) {
+	if offset, found := s.EndOffsets[pid]; found {
+		return offset, nil
+	}
+	if !s.EndTime.IsZero() {
+		return offsetAtTime(c, s.Topic, pid, s.EndTime)
+	}
+	return getOffset(c, s.Topic, pid, sarama.OffsetNewest)
+}
+
+// offsetAtTime is the first offset of the messages at or after the time,
+// or the newest offset if there are no such messages.
+func offsetAtTime(c sarama.Client, topic string, pid int32, t time.Time) (int64, error) {
+	offset, err := getOffset(c, topic, pid, t.UnixNano()/int64(time.Millisecond))
+	if err != nil || offset >= 0 {
+		return offset, err
+	}
+	return getOffset(c, topic, pid, sarama.OffsetNewest)
+}
+
+func getOffset(c sarama.Client, topic string, pid int32, t int64) (int64, error) {
+	offset, err := c.GetOffset(topic, pid, t)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get offset of %s partition %d: %v", topic, pid, err)
+	}
+	return offset, nil
+}
+
+// fetchCommittedOffsets returns the committed offsets of the consumer group.
+// The partitions without committed offsets are not in the map.
+func (s *KafkaSource) fetchCommittedOffsets(c sarama.Client, partitionIds []int32) (map[int32]int64, error) {
+	if s.Group == "" {
+		return nil, fmt.Errorf("Missing consumer group to read committed offsets of %s", s.Topic)
+	}
+	coordinator, err := c.Coordinator(s.Group)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find coordinator of group %s: %v", s.Group, err)
+	}
+	request := &sarama.OffsetFetchRequest{Version: 1, ConsumerGroup: s.Group}
+	for _, pid := range partitionIds {
+		request.AddPartition(s.Topic, pid)
+	}
+	response, err := coordinator.FetchOffset(request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch offsets of group %s: %v", s.Group, err)
+	}
+	offsets := make(map[int32]int64)
+	for _, pid := range partitionIds {
+		block := response.GetBlock(s.Topic, pid)
+		if block == nil {
+			continue
+		}
+		if block.Err != sarama.ErrNoError {
+			return nil, fmt.Errorf("Failed to fetch offset of group %s partition %d: %v", s.Group, pid, block.Err)
+		}
+		if block.Offset >= 0 {
+			offsets[pid] = block.Offset
+		}
+	}
+	return offsets, nil
+}
+
+// ResolvedEndOffsets returns the end offsets of each partition for the bounded reads,
+// known after the flow is generated.
+func (s *KafkaSource) ResolvedEndOffsets() map[int32]int64 {
+	return s.resolvedEndOffsets
+}
+
+// CommitOffsets commits the end offsets of the bounded reads to the consumer group.
+// Call it after the flow runs successfully, so the next run with StartAtCommitted()
+// continues from where this run stopped, and a failed run can be repeated.
+func (s *KafkaSource) CommitOffsets() error {
+	if s.Group == "" {
+		return fmt.Errorf("Missing consumer group to commit offsets of %s", s.Topic)
+	}
+	if s.resolvedEndOffsets == nil {
+		return fmt.Errorf("No bounded reads of %s to commit", s.Topic)
+	}
+
+	c, err := s.newClient()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	coordinator, err := c.Coordinator(s.Group)
+	if err != nil {
+		return fmt.Errorf("Failed to find coordinator of group %s: %v", s.Group, err)
+	}
+	request := &sarama.OffsetCommitRequest{
+		Version:                 1,
+		ConsumerGroup:           s.Group,
+		ConsumerGroupGeneration: sarama.GroupGenerationUndefined,
+	}
+	for pid, offset := range s.resolvedEndOffsets {
+		request.AddBlock(s.Topic, pid, offset, sarama.ReceiveTime, "")
+	}
+	response, err := coordinator.CommitOffset(request)
+	if err != nil {
+		return fmt.Errorf("Failed to commit offsets of group %s: %v", s.Group, err)
+	}
+	for pid, kerr := range response.Errors[s.Topic] {
+		if kerr != sarama.ErrNoError {
+			return fmt.Errorf("Failed to commit offset of group %s partition %d: %v", s.Group, pid, kerr)
+		}
+	}
+	return nil
 }
 
-func (s *KafkaSource) genShardInfos(f *flow.Flow, partitionIds []int32) *flow.Dataset {
+func (s *KafkaSource) genShardInfos(f *flow.Flow, partitionInfos []*KafkaPartitionInfo) *flow.Dataset {
 	return f.Source(func(writer io.Writer) error {
 
-		for _, pid := range partitionIds {
-			util.WriteRow(writer, util.Now(), encodeShardInfo(&KafkaPartitionInfo{
-				Brokers:        s.Brokers,
-				Topic:          s.Topic,
-				Group:          s.Group,
-				TimeoutSeconds: s.TimeoutSeconds,
-				PartitionId:    pid,
-			}))
+		for _, partitionInfo := range partitionInfos {
+			util.WriteRow(writer, util.Now(), encodeShardInfo(partitionInfo))
 		}
 
 		return nil
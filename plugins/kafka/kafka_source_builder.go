@@ -1,5 +1,9 @@
 package kafka
 
+import (
+	"time"
+)
+
 /*
 This file is only for the builder API.
 */
@@ -17,3 +21,43 @@ func (s *KafkaSource) Timeout(seconds int) *KafkaSource {
 	s.TimeoutSeconds = seconds
 	return s
 }
+
+// StartAt reads the partitions from these offsets.
+func (s *KafkaSource) StartAt(offsets map[int32]int64) *KafkaSource {
+	s.StartOffsets = offsets
+	return s
+}
+
+// StartAtTime reads the partitions from the first messages at or after the time,
+// for the partitions without start offsets.
+func (s *KafkaSource) StartAtTime(t time.Time) *KafkaSource {
+	s.StartTime = t
+	return s
+}
+
+// StartAtCommitted reads the partitions from the offsets committed by the consumer group,
+// for the partitions without start offsets.
+func (s *KafkaSource) StartAtCommitted() *KafkaSource {
+	s.StartFromCommitted = true
+	return s
+}
+
+// EndAt stops reading the partitions before these offsets.
+// The partitions without end offsets stop at the newest offsets when the flow is generated.
+func (s *KafkaSource) EndAt(offsets map[int32]int64) *KafkaSource {
+	s.EndOffsets = offsets
+	return s
+}
+
+// EndAtTime stops reading the partitions before the first messages at or after the time,
+// for the partitions without end offsets.
+func (s *KafkaSource) EndAtTime(t time.Time) *KafkaSource {
+	s.EndTime = t
+	return s
+}
+
+// EndAtNewest stops reading the partitions at the newest offsets when the flow is generated.
+func (s *KafkaSource) EndAtNewest() *KafkaSource {
+	s.StopAtNewest = true
+	return s
+}
@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+)
+
+func newTestBroker(t *testing.T, group string) *sarama.MockBroker {
+	return newTestBrokerWithMessages(t, group, []int64{0, 1, 2, 3, 4}, 5)
+}
+
+// newTestBrokerWithMessages serves the messages at the offsets of partition 0 of "events".
+func newTestBrokerWithMessages(t *testing.T, group string, offsets []int64, highWaterMark int64) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+
+	fetchResponse := sarama.NewMockFetchResponse(t, 1).SetVersion(3).
+		SetHighWaterMark("events", 0, highWaterMark)
+	for _, offset := range offsets {
+		fetchResponse.SetMessage("events", 0, offset, sarama.StringEncoder(fmt.Sprintf("event%d", offset)))
+	}
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("events", 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).SetVersion(1).
+			SetOffset("events", 0, sarama.OffsetOldest, 0).
+			SetOffset("events", 0, sarama.OffsetNewest, highWaterMark),
+		"FetchRequest": fetchResponse,
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, group, broker),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(group, "events", 0, 2, "", sarama.ErrNoError),
+		"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+	})
+	return broker
+}
+
+func TestBoundedRead(t *testing.T) {
+	broker := newTestBroker(t, "readers")
+	defer broker.Close()
+
+	source := New([]string{broker.Addr()}, "events", "readers").StartAtCommitted().EndAt(map[int32]int64{0: 4})
+	c, err := source.newClient()
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	partitionInfos, err := source.fetchPartitionInfos(c)
+	c.Close()
+	if err != nil {
+		t.Fatalf("Failed to fetch partitions: %v", err)
+	}
+	if len(partitionInfos) != 1 || partitionInfos[0].StartOffset != 2 || partitionInfos[0].EndOffset != 4 {
+		t.Fatalf("Unexpected partitions %+v", partitionInfos)
+	}
+
+	var values []string
+	err = partitionInfos[0].readPartition(func(ts int64, value []byte) error {
+		values = append(values, string(value))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read partition: %v", err)
+	}
+	if fmt.Sprint(values) != "[event2 event3]" {
+		t.Errorf("Unexpected values %v", values)
+	}
+
+	if err = source.CommitOffsets(); err != nil {
+		t.Fatalf("Failed to commit offsets: %v", err)
+	}
+	var committed bool
+	for _, rr := range broker.History() {
+		if request, ok := rr.Request.(*sarama.OffsetCommitRequest); ok {
+			offset, _, err := request.Offset("events", 0)
+			if err != nil || offset != 4 {
+				t.Errorf("Expect to commit offset 4, but got %d: %v", offset, err)
+			}
+			committed = true
+		}
+	}
+	if !committed {
+		t.Errorf("Expect the offsets to be committed")
+	}
+}
+
+func TestBoundedReadWithoutLastOffset(t *testing.T) {
+	// offset 2 is compacted, and offset 4 is a transaction marker
+	broker := newTestBrokerWithMessages(t, "readers", []int64{0, 1, 3}, 5)
+	defer broker.Close()
+
+	partitionInfo := &KafkaPartitionInfo{
+		Brokers:        []string{broker.Addr()},
+		Topic:          "events",
+		TimeoutSeconds: 16,
+		StartOffset:    0,
+		EndOffset:      5,
+		IsBounded:      true,
+	}
+	var values []string
+	done := make(chan error)
+	go func() {
+		done <- partitionInfo.readPartition(func(ts int64, value []byte) error {
+			values = append(values, string(value))
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Failed to read partition: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Expect the read to stop without a message at the last offset")
+	}
+	if fmt.Sprint(values) != "[event0 event1 event3]" {
+		t.Errorf("Unexpected values %v", values)
+	}
+}
+
+func TestEndAtNewest(t *testing.T) {
+	broker := newTestBroker(t, "readers")
+	defer broker.Close()
+
+	source := New([]string{broker.Addr()}, "events", "readers").EndAtNewest()
+	c, err := source.newClient()
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer c.Close()
+	partitionInfos, err := source.fetchPartitionInfos(c)
+	if err != nil {
+		t.Fatalf("Failed to fetch partitions: %v", err)
+	}
+	if partitionInfos[0].StartOffset != 0 || partitionInfos[0].EndOffset != 5 || source.ResolvedEndOffsets()[0] != 5 {
+		t.Errorf("Unexpected partitions %+v", partitionInfos[0])
+	}
+}
+
+func TestKafkaWriter(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	for _, expected := range []string{"a", "2", "c"} {
+		expected := expected
+		producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(value []byte) error {
+			if string(value) != expected {
+				return fmt.Errorf("Expect %s, but got %s", expected, value)
+			}
+			return nil
+		})
+	}
+
+	sink := NewSink(nil, "events").Key(1).Value(2).SetBatchSize(2)
+	w := newKafkaWriter(sink, producer)
+	for _, row := range [][]interface{}{{"x", "a"}, {"y", int64(2)}, {"z", []byte("c")}} {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("Failed to write %v: %v", row, err)
+		}
+	}
+	if len(w.messages) != 1 {
+		t.Errorf("Expect one message in the batch, but got %d", len(w.messages))
+	}
+	if err := w.Commit(); err != nil {
+		t.Errorf("Failed to commit: %v", err)
+	}
+
+	if err := newKafkaWriter(sink, mocks.NewSyncProducer(t, nil)).Write([]interface{}{"x"}); err == nil {
+		t.Errorf("Expect an error for a row without the value field")
+	}
+}
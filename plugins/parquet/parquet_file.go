@@ -0,0 +1,96 @@
+package parquet
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// parquetFile reads a parquet file on any of the virtual file systems.
+// The parquet reader opens the file again for each column.
+type parquetFile struct {
+	filesystem.VirtualFile
+	fileName string
+}
+
+func openParquetFile(fileName string) (*parquetFile, error) {
+	file, err := filesystem.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open file %s: %v", fileName, err)
+	}
+	return &parquetFile{VirtualFile: file, fileName: fileName}, nil
+}
+
+// Open opens the same file if the name is empty,
+// or a column chunk file relative to the same folder.
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		return openParquetFile(f.fileName)
+	}
+	lastSlash := strings.LastIndex(f.fileName, "/")
+	return openParquetFile(path.Join(f.fileName[:lastSlash+1], name))
+}
+
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, fmt.Errorf("Can not create parquet file %s", name)
+}
+
+func (f *parquetFile) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("Can not write parquet file %s", f.fileName)
+}
+
+// openParquetReader reads the footer of the file, and prepares to read the columns.
+func openParquetReader(fileName string) (*reader.ParquetReader, error) {
+	file, err := openParquetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Failed to read parquet footer of %s: %v", fileName, err)
+	}
+	return pr, nil
+}
+
+func closeParquetReader(pr *reader.ParquetReader) {
+	pr.ReadStop()
+	pr.PFile.Close()
+}
+
+// leafColumns lists the names of the columns with one value per row, e.g., "name", "address.city".
+func leafColumns(pr *reader.ParquetReader) (columns []string) {
+	sh := pr.SchemaHandler
+	for _, inPath := range sh.ValueColumns {
+		if level, _ := sh.MaxRepetitionLevel(common.StrToPath(inPath)); level > 0 {
+			continue
+		}
+		exPath := common.StrToPath(sh.InPathToExPath[inPath])
+		columns = append(columns, strings.Join(exPath[1:], "."))
+	}
+	return columns
+}
+
+// columnPath converts a column name, e.g., "address.city", to the path in the parquet reader.
+// Only the columns with one value per row can be read.
+func columnPath(pr *reader.ParquetReader, column string) (string, error) {
+	sh := pr.SchemaHandler
+	exPath := append([]string{sh.GetRootExName()}, strings.Split(column, ".")...)
+	inPath, err := sh.ConvertToInPathStr(common.PathToStr(exPath))
+	if err != nil {
+		return "", fmt.Errorf("Unknown parquet column %s", column)
+	}
+	index, found := sh.MapIndex[inPath]
+	if !found || sh.SchemaElements[index].GetNumChildren() > 0 {
+		return "", fmt.Errorf("Parquet column %s is not a leaf column", column)
+	}
+	if level, _ := sh.MaxRepetitionLevel(common.StrToPath(inPath)); level > 0 {
+		return "", fmt.Errorf("Repeated parquet column %s is not supported", column)
+	}
+	return inPath, nil
+}
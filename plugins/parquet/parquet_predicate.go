@@ -0,0 +1,282 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/common"
+	format "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// Predicate compares a column with a value, e.g., "age" ">=" 21.
+// Rows with a nil column value do not match any predicate.
+type Predicate struct {
+	Column string
+	Op     string
+	Value  interface{}
+}
+
+var predicateOps = []string{"=", "!=", "<", "<=", ">", ">="}
+
+func newPredicate(column, op string, value interface{}) (Predicate, error) {
+	p := Predicate{Column: column, Op: op, Value: toRowValue(value)}
+	if !isPredicateOp(op) {
+		return p, fmt.Errorf("Unknown operator %s in predicate on %s, expecting one of %s", op, column, strings.Join(predicateOps, " "))
+	}
+	switch p.Value.(type) {
+	case bool, int64, float64, string:
+	default:
+		return p, fmt.Errorf("Unsupported value %v of type %T in predicate on %s", value, value, column)
+	}
+	return p, nil
+}
+
+func isPredicateOp(op string) bool {
+	for _, x := range predicateOps {
+		if x == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Predicate) String() string {
+	return fmt.Sprintf("%s %s %v", p.Column, p.Op, p.Value)
+}
+
+// Match checks the column value of a row.
+func (p Predicate) Match(value interface{}) (bool, error) {
+	if value == nil {
+		return false, nil
+	}
+	c, ok := compare(value, p.Value)
+	if !ok {
+		return false, fmt.Errorf("Can not compare %s value %v of type %T with %v", p.Column, value, value, p.Value)
+	}
+	switch p.Op {
+	case "=":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return false, nil
+}
+
+// MayMatch checks whether any value between min and max can match.
+// It is true if the values can not be compared.
+func (p Predicate) MayMatch(min, max interface{}) bool {
+	cMin, okMin := compare(min, p.Value)
+	cMax, okMax := compare(max, p.Value)
+	if !okMin || !okMax {
+		return true
+	}
+	switch p.Op {
+	case "=":
+		return cMin <= 0 && cMax >= 0
+	case "!=":
+		return cMin != 0 || cMax != 0
+	case "<":
+		return cMin < 0
+	case "<=":
+		return cMin <= 0
+	case ">":
+		return cMax > 0
+	case ">=":
+		return cMax >= 0
+	}
+	return true
+}
+
+// compare compares two row values of the same kind. Integers and floats can be compared.
+func compare(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, y), true
+		case float64:
+			return compareFloat(float64(x), y)
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return compareFloat(x, float64(y))
+		case float64:
+			return compareFloat(x, y)
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			if x == y {
+				return 0, true
+			}
+			if !x {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+func compareOrdered(x, y int64) int {
+	if x < y {
+		return -1
+	}
+	if x > y {
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(x, y float64) (int, bool) {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return 0, false
+	}
+	if x < y {
+		return -1, true
+	}
+	if x > y {
+		return 1, true
+	}
+	return 0, true
+}
+
+// toRowValue widens the parquet values to int64 and float64.
+func toRowValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []byte:
+		return string(v)
+	}
+	return value
+}
+
+// rowGroupMayMatch checks the predicates with the column statistics of the row group.
+// The row group is skipped only if some predicate can not match any of its rows.
+func rowGroupMayMatch(pr *reader.ParquetReader, rowGroup *format.RowGroup, predicates []Predicate) bool {
+	for _, p := range predicates {
+		inPath, err := columnPath(pr, p.Column)
+		if err != nil {
+			continue
+		}
+		chunk := findColumnChunk(pr, rowGroup, inPath)
+		if chunk == nil || chunk.GetMetaData() == nil || chunk.GetMetaData().GetStatistics() == nil {
+			continue
+		}
+		statistics := chunk.GetMetaData().GetStatistics()
+		if statistics.IsSetNullCount() && statistics.GetNullCount() >= rowGroup.GetNumRows() {
+			return false
+		}
+		element := pr.SchemaHandler.SchemaElements[pr.SchemaHandler.MapIndex[inPath]]
+		min, max, ok := decodeStatistics(chunk.GetMetaData().GetType(), element, statistics)
+		if ok && !p.MayMatch(min, max) {
+			return false
+		}
+	}
+	return true
+}
+
+func findColumnChunk(pr *reader.ParquetReader, rowGroup *format.RowGroup, inPath string) *format.ColumnChunk {
+	for _, chunk := range rowGroup.GetColumns() {
+		if chunk.GetMetaData() == nil {
+			continue
+		}
+		path := append([]string{pr.SchemaHandler.GetRootInName()}, chunk.GetMetaData().GetPathInSchema()...)
+		if common.PathToStr(path) == inPath {
+			return chunk
+		}
+	}
+	return nil
+}
+
+// decodeStatistics decodes the plain encoded min and max values.
+// The deprecated min and max fields are only used for the signed numbers.
+// Unsigned integers, INT96 and fixed length byte arrays are not ordered the same way as the row values.
+func decodeStatistics(t format.Type, element *format.SchemaElement, statistics *format.Statistics) (min, max interface{}, ok bool) {
+	if element.IsSetConvertedType() {
+		switch element.GetConvertedType() {
+		case format.ConvertedType_UINT_8, format.ConvertedType_UINT_16, format.ConvertedType_UINT_32, format.ConvertedType_UINT_64:
+			return nil, nil, false
+		}
+	}
+	minBytes, maxBytes := statistics.GetMinValue(), statistics.GetMaxValue()
+	if minBytes == nil || maxBytes == nil {
+		if t == format.Type_BYTE_ARRAY {
+			return nil, nil, false
+		}
+		minBytes, maxBytes = statistics.GetMin(), statistics.GetMax()
+	}
+	if minBytes == nil || maxBytes == nil {
+		return nil, nil, false
+	}
+	if min, ok = decodePlainValue(t, minBytes); !ok {
+		return nil, nil, false
+	}
+	if max, ok = decodePlainValue(t, maxBytes); !ok {
+		return nil, nil, false
+	}
+	return min, max, true
+}
+
+func decodePlainValue(t format.Type, data []byte) (interface{}, bool) {
+	switch t {
+	case format.Type_BOOLEAN:
+		if len(data) != 1 {
+			return nil, false
+		}
+		return data[0] != 0, true
+	case format.Type_INT32:
+		if len(data) != 4 {
+			return nil, false
+		}
+		return int64(int32(binary.LittleEndian.Uint32(data))), true
+	case format.Type_INT64:
+		if len(data) != 8 {
+			return nil, false
+		}
+		return int64(binary.LittleEndian.Uint64(data)), true
+	case format.Type_FLOAT:
+		if len(data) != 4 {
+			return nil, false
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data))), true
+	case format.Type_DOUBLE:
+		if len(data) != 8 {
+			return nil, false
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(data)), true
+	case format.Type_BYTE_ARRAY:
+		return string(data), true
+	}
+	return nil, false
+}
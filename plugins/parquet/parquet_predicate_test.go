@@ -0,0 +1,109 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	format "github.com/xitongsys/parquet-go/parquet"
+)
+
+func TestPredicateMatch(t *testing.T) {
+
+	p, err := newPredicate("age", ">=", 21)
+	if err != nil {
+		t.Fatalf("Failed to create predicate: %v", err)
+	}
+	for _, c := range []struct {
+		value    interface{}
+		expected bool
+	}{
+		{int32(20), false},
+		{int64(21), true},
+		{22.5, true},
+		{nil, false},
+	} {
+		if matched, err := p.Match(toRowValue(c.value)); err != nil || matched != c.expected {
+			t.Errorf("Expect %v >= 21 to be %v, but got %v: %v", c.value, c.expected, matched, err)
+		}
+	}
+	if _, err := p.Match("21"); err == nil {
+		t.Errorf("Expect an error comparing a string with an integer")
+	}
+
+	if _, err := newPredicate("age", "~", 21); err == nil {
+		t.Errorf("Expect an error for an unknown operator")
+	}
+	if _, err := newPredicate("age", "=", []int{21}); err == nil {
+		t.Errorf("Expect an error for an unsupported value")
+	}
+}
+
+func TestPredicateMayMatch(t *testing.T) {
+
+	for _, c := range []struct {
+		op       string
+		value    interface{}
+		expected bool
+	}{
+		{"=", 5, true},
+		{"=", 11, false},
+		{"!=", 5, true},
+		{"<", 3, false},
+		{"<=", 3, true},
+		{">", 10, false},
+		{">=", 9.5, true},
+	} {
+		p, err := newPredicate("x", c.op, c.value)
+		if err != nil {
+			t.Fatalf("Failed to create predicate: %v", err)
+		}
+		if matched := p.MayMatch(int64(3), int64(10)); matched != c.expected {
+			t.Errorf("Expect %v on [3, 10] to be %v", p, c.expected)
+		}
+	}
+
+	p, _ := newPredicate("x", "!=", 7)
+	if p.MayMatch(int64(7), int64(7)) {
+		t.Errorf("Expect %v on [7, 7] to be false", p)
+	}
+	p, _ = newPredicate("name", "<", "apple")
+	if !p.MayMatch(int64(1), int64(2)) {
+		t.Errorf("Expect values that can not be compared to match")
+	}
+}
+
+func TestDecodeStatistics(t *testing.T) {
+
+	int32Value := func(x int32) []byte {
+		data := make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, uint32(x))
+		return data
+	}
+	element := &format.SchemaElement{}
+
+	min, max, ok := decodeStatistics(format.Type_INT32, element, &format.Statistics{Min: int32Value(-3), Max: int32Value(42)})
+	if !ok || min != int64(-3) || max != int64(42) {
+		t.Errorf("Unexpected int32 statistics %v %v %v", min, max, ok)
+	}
+
+	double := make([]byte, 8)
+	binary.LittleEndian.PutUint64(double, math.Float64bits(2.5))
+	min, max, ok = decodeStatistics(format.Type_DOUBLE, element, &format.Statistics{MinValue: double, MaxValue: double})
+	if !ok || min != 2.5 || max != 2.5 {
+		t.Errorf("Unexpected double statistics %v %v %v", min, max, ok)
+	}
+
+	min, max, ok = decodeStatistics(format.Type_BYTE_ARRAY, element, &format.Statistics{MinValue: []byte("a"), MaxValue: []byte("z")})
+	if !ok || min != "a" || max != "z" {
+		t.Errorf("Unexpected byte array statistics %v %v %v", min, max, ok)
+	}
+	if _, _, ok = decodeStatistics(format.Type_BYTE_ARRAY, element, &format.Statistics{Min: []byte("a"), Max: []byte("z")}); ok {
+		t.Errorf("Expect the deprecated byte array statistics to be ignored")
+	}
+
+	unsigned := format.ConvertedType_UINT_32
+	if _, _, ok = decodeStatistics(format.Type_INT32, &format.SchemaElement{ConvertedType: &unsigned}, &format.Statistics{Min: int32Value(-3), Max: int32Value(42)}); ok {
+		t.Errorf("Expect the unsigned statistics to be ignored")
+	}
+}
@@ -0,0 +1,153 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"log"
+
+	"github.com/chrislusf/gleamold/gio"
+	format "github.com/xitongsys/parquet-go/parquet"
+)
+
+// readBatchSize is the number of rows read from each column at a time.
+const readBatchSize = 1024
+
+type ParquetShardInfo struct {
+	FileName string
+	// RowGroups are the indexes of the row groups to read in the file.
+	RowGroups  []int
+	Columns    []string
+	Predicates []Predicate
+}
+
+var (
+	MapperReadShard = gio.RegisterMapper(readShard)
+)
+
+func init() {
+	gob.Register(ParquetShardInfo{})
+}
+
+func readShard(row []interface{}) error {
+	encodedShardInfo := row[0].([]byte)
+	return decodeShardInfo(encodedShardInfo).ReadSplit()
+}
+
+// ReadSplit emits the projected columns of the rows matching all the predicates.
+// The predicate columns are also read if not projected.
+func (ds *ParquetShardInfo) ReadSplit() error {
+	return ds.readSplit(gio.Emit)
+}
+
+// readSplit sends the projected columns of the matching rows to emit.
+func (ds *ParquetShardInfo) readSplit(emit func(row ...interface{}) error) error {
+	pr, err := openParquetReader(ds.FileName)
+	if err != nil {
+		return err
+	}
+	defer closeParquetReader(pr)
+
+	// only read the row groups of this shard
+	var rowGroups []*format.RowGroup
+	var numRows int64
+	for _, index := range ds.RowGroups {
+		if index >= len(pr.Footer.RowGroups) {
+			return fmt.Errorf("Row group %d not found in %s", index, ds.FileName)
+		}
+		rowGroups = append(rowGroups, pr.Footer.RowGroups[index])
+		numRows += pr.Footer.RowGroups[index].GetNumRows()
+	}
+	pr.Footer.RowGroups = rowGroups
+
+	columns := ds.Columns
+	if len(columns) == 0 {
+		columns = leafColumns(pr)
+	}
+	var paths []string
+	columnIndexes := make(map[string]int)
+	for _, column := range columns {
+		if _, found := columnIndexes[column]; found {
+			continue
+		}
+		path, err := columnPath(pr, column)
+		if err != nil {
+			return fmt.Errorf("%v in %s", err, ds.FileName)
+		}
+		columnIndexes[column] = len(paths)
+		paths = append(paths, path)
+	}
+	for _, p := range ds.Predicates {
+		if _, found := columnIndexes[p.Column]; found {
+			continue
+		}
+		path, err := columnPath(pr, p.Column)
+		if err != nil {
+			return fmt.Errorf("%v in %s", err, ds.FileName)
+		}
+		columnIndexes[p.Column] = len(paths)
+		paths = append(paths, path)
+	}
+
+	values := make([][]interface{}, len(paths))
+	for start := int64(0); start < numRows; start += readBatchSize {
+		batchSize := numRows - start
+		if batchSize > readBatchSize {
+			batchSize = readBatchSize
+		}
+		for i, path := range paths {
+			if values[i], _, _, err = pr.ReadColumnByPath(path, batchSize); err != nil {
+				return fmt.Errorf("Failed to read column %s in %s: %v", path, ds.FileName, err)
+			}
+			if int64(len(values[i])) != batchSize {
+				return fmt.Errorf("Read %d values instead of %d in %s", len(values[i]), batchSize, ds.FileName)
+			}
+		}
+		for r := int64(0); r < batchSize; r++ {
+			matched, err := ds.matchRow(values, columnIndexes, r)
+			if err != nil {
+				return err
+			}
+			if !matched {
+				continue
+			}
+			row := make([]interface{}, len(columns))
+			for i, column := range columns {
+				row[i] = toRowValue(values[columnIndexes[column]][r])
+			}
+			if err := emit(row...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ds *ParquetShardInfo) matchRow(values [][]interface{}, columnIndexes map[string]int, r int64) (bool, error) {
+	for _, p := range ds.Predicates {
+		matched, err := p.Match(toRowValue(values[columnIndexes[p.Column]][r]))
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func decodeShardInfo(encodedShardInfo []byte) *ParquetShardInfo {
+	network := bytes.NewBuffer(encodedShardInfo)
+	dec := gob.NewDecoder(network)
+	var p ParquetShardInfo
+	if err := dec.Decode(&p); err != nil {
+		log.Fatal("decode shard info", err)
+	}
+	return &p
+}
+
+func encodeShardInfo(shardInfo *ParquetShardInfo) []byte {
+	var network bytes.Buffer
+	enc := gob.NewEncoder(&network)
+	if err := enc.Encode(shardInfo); err != nil {
+		log.Fatal("encode shard info:", err)
+	}
+	return network.Bytes()
+}
@@ -0,0 +1,174 @@
+/*
+Package parquet reads Parquet files.
+
+The source follows the same steps as the csv plugin:
+1) generate a list of shard info on the driver, one per row group or a few row groups of each file.
+2) send each piece of shard info to a remote executor.
+3) each executor reads the projected columns of the shard info via a mapper.
+
+The row groups are skipped if their column statistics show no rows can match the predicates.
+
+Since the mapper to process shard info is in Go, the call to "gio.Init()"
+is required.
+*/
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chrislusf/gleamold/filesystem"
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/util"
+)
+
+type ParquetSource struct {
+	folder            string
+	fileBaseName      string
+	hasWildcard       bool
+	Path              string
+	PartitionCount    int
+	RowGroupsPerShard int
+	Columns           []string
+	Predicates        []Predicate
+}
+
+// Generate generates data shard info,
+// partitions them via round robin,
+// and reads each shard on each executor
+func (s *ParquetSource) Generate(f *flow.Flow) *flow.Dataset {
+	return s.genShardInfos(f).RoundRobin(s.PartitionCount).Mapper(MapperReadShard)
+}
+
+// New creates a ParquetSource based on a file name.
+// The base file name can have "*", "?" pattern denoting a list of file names.
+// The file name can also be on hdfs:// or s3://, e.g., "s3://bucket/events/*.parquet".
+// By default, each row group is one shard, and each column with one value per row is one field.
+func New(fileOrPattern string, partitionCount int) *ParquetSource {
+	s := &ParquetSource{
+		PartitionCount:    partitionCount,
+		RowGroupsPerShard: 1,
+	}
+
+	if strings.Contains(fileOrPattern, "://") {
+		lastSlash := strings.LastIndex(fileOrPattern, "/")
+		s.folder = fileOrPattern[:lastSlash]
+		s.fileBaseName = fileOrPattern[lastSlash+1:]
+		s.Path = fileOrPattern
+	} else if strings.ContainsAny(fileOrPattern, "/\\") {
+		s.folder = filepath.Dir(fileOrPattern)
+		s.fileBaseName = filepath.Base(fileOrPattern)
+		s.Path = fileOrPattern
+	} else {
+		s.folder, _ = os.Getwd()
+		s.fileBaseName = fileOrPattern
+		s.Path = filepath.Join(s.folder, s.fileBaseName)
+	}
+	if strings.ContainsAny(s.fileBaseName, "*?") {
+		s.hasWildcard = true
+	}
+
+	return s
+}
+
+// Select reads only these columns as the fields of each row, e.g., "id", "address.city".
+// Repeated columns are not supported. A null value is nil.
+func (s *ParquetSource) Select(columns ...string) *ParquetSource {
+	s.Columns = columns
+	return s
+}
+
+// Where only reads the rows where the column compares to the value by the op,
+// which is one of "=", "!=", "<", "<=", ">", ">=".
+// The value can be a bool, an integer, a float, or a string.
+// Multiple predicates are combined with AND.
+func (s *ParquetSource) Where(column, op string, value interface{}) *ParquetSource {
+	p, err := newPredicate(column, op, value)
+	if err != nil {
+		panic(err)
+	}
+	s.Predicates = append(s.Predicates, p)
+	return s
+}
+
+// SetRowGroupsPerShard reads up to this many row groups of a file in one shard,
+// to reduce the number of shards for files with many small row groups.
+func (s *ParquetSource) SetRowGroupsPerShard(rowGroupsPerShard int) *ParquetSource {
+	s.RowGroupsPerShard = rowGroupsPerShard
+	return s
+}
+
+func (s *ParquetSource) genShardInfos(f *flow.Flow) *flow.Dataset {
+	return f.Source(func(writer io.Writer) error {
+		if !s.hasWildcard && !filesystem.IsDir(s.Path) {
+			return s.genFileShardInfos(writer, s.Path)
+		}
+		folder := s.folder
+		if !s.hasWildcard {
+			folder = s.Path
+		}
+		virtualFiles, err := filesystem.List(folder)
+		if err != nil {
+			return fmt.Errorf("Failed to list folder %s: %v", folder, err)
+		}
+		for _, vf := range virtualFiles {
+			if !s.hasWildcard || s.match(vf.Location) {
+				if err := s.genFileShardInfos(writer, vf.Location); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// genFileShardInfos reads the file footer, and groups the row groups that may match the predicates.
+func (s *ParquetSource) genFileShardInfos(writer io.Writer, fileName string) error {
+	pr, err := openParquetReader(fileName)
+	if err != nil {
+		return err
+	}
+	defer closeParquetReader(pr)
+
+	for _, column := range s.Columns {
+		if _, err := columnPath(pr, column); err != nil {
+			return fmt.Errorf("%v in %s", err, fileName)
+		}
+	}
+	for _, p := range s.Predicates {
+		if _, err := columnPath(pr, p.Column); err != nil {
+			return fmt.Errorf("%v in %s", err, fileName)
+		}
+	}
+
+	shardInfo := &ParquetShardInfo{
+		FileName:   fileName,
+		Columns:    s.Columns,
+		Predicates: s.Predicates,
+	}
+	for index, rowGroup := range pr.Footer.GetRowGroups() {
+		if rowGroup.GetNumRows() == 0 || !rowGroupMayMatch(pr, rowGroup, s.Predicates) {
+			continue
+		}
+		shardInfo.RowGroups = append(shardInfo.RowGroups, index)
+		if len(shardInfo.RowGroups) >= s.RowGroupsPerShard {
+			if err := util.WriteRow(writer, util.Now(), encodeShardInfo(shardInfo)); err != nil {
+				return err
+			}
+			shardInfo.RowGroups = nil
+		}
+	}
+	if len(shardInfo.RowGroups) > 0 {
+		return util.WriteRow(writer, util.Now(), encodeShardInfo(shardInfo))
+	}
+	return nil
+}
+
+func (s *ParquetSource) match(fullPath string) bool {
+	baseName := filepath.Base(fullPath)
+	match, _ := filepath.Match(s.fileBaseName, baseName)
+	return match
+}
@@ -0,0 +1,142 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/util"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type testRecord struct {
+	Id    int64   `parquet:"name=id, type=INT64"`
+	Name  *string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Score float64 `parquet:"name=score, type=DOUBLE"`
+}
+
+// writeTestFile writes the records with the parquet-go writer, one row group per slice of records.
+func writeTestFile(t *testing.T, rowGroups ...[]testRecord) string {
+	dir, err := ioutil.TempDir("", "parquet")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	fileName := filepath.Join(dir, "test.parquet")
+	file, err := os.Create(fileName)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", fileName, err)
+	}
+	defer file.Close()
+
+	pw, err := writer.NewParquetWriterFromWriter(file, new(testRecord), 1)
+	if err != nil {
+		t.Fatalf("Failed to create parquet writer: %v", err)
+	}
+	for _, records := range rowGroups {
+		for _, record := range records {
+			if err := pw.Write(record); err != nil {
+				t.Fatalf("Failed to write %+v: %v", record, err)
+			}
+		}
+		if err := pw.Flush(true); err != nil {
+			t.Fatalf("Failed to flush row group: %v", err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatalf("Failed to write parquet footer: %v", err)
+	}
+	return fileName
+}
+
+// readSource generates the shard infos of the source in a flow,
+// and reads each shard as the mapper would.
+func readSource(s *ParquetSource) (shardCount int, rows []string, err error) {
+	f := flow.New()
+	s.genShardInfos(f).Output(func(reader io.Reader) error {
+		for {
+			_, row, err := util.ReadRow(reader)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			shardCount++
+			if err := decodeShardInfo(row[0].([]byte)).readSplit(func(row ...interface{}) error {
+				rows = append(rows, fmt.Sprintf("%v", row))
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	})
+	_, err = f.Run()
+	return
+}
+
+func TestReadProjectedColumnsWithPredicates(t *testing.T) {
+	alice, bob, carol := "alice", "bob", "carol"
+	fileName := writeTestFile(t,
+		[]testRecord{{1, &alice, 0.5}, {2, &bob, 1.5}},
+		[]testRecord{{3, &carol, 2.5}, {4, nil, 3.5}, {5, &alice, 0.5}},
+	)
+	defer os.RemoveAll(filepath.Dir(fileName))
+
+	for _, c := range []struct {
+		name       string
+		source     *ParquetSource
+		shardCount int
+		expected   []string
+	}{
+		{
+			name:       "all columns",
+			source:     New(fileName, 1),
+			shardCount: 2,
+			expected:   []string{"[1 alice 0.5]", "[2 bob 1.5]", "[3 carol 2.5]", "[4 <nil> 3.5]", "[5 alice 0.5]"},
+		},
+		{
+			name:       "projection and predicate",
+			source:     New(fileName, 1).Select("name", "id").Where("score", ">", 1.0),
+			shardCount: 2,
+			expected:   []string{"[bob 2]", "[carol 3]", "[<nil> 4]"},
+		},
+		{
+			name:       "row group skipped by statistics",
+			source:     New(fileName, 1).Select("name").Where("id", ">=", 3).Where("name", "!=", "alice"),
+			shardCount: 1,
+			expected:   []string{"[carol]"},
+		},
+		{
+			name:       "row groups in one shard",
+			source:     New(fileName, 1).Select("id").Where("id", "<", 4).SetRowGroupsPerShard(2),
+			shardCount: 1,
+			expected:   []string{"[1]", "[2]", "[3]"},
+		},
+	} {
+		shardCount, rows, err := readSource(c.source)
+		if err != nil {
+			t.Errorf("%s: failed to read: %v", c.name, err)
+			continue
+		}
+		if shardCount != c.shardCount {
+			t.Errorf("%s: Expect %d shards, but got %d", c.name, c.shardCount, shardCount)
+		}
+		if !reflect.DeepEqual(rows, c.expected) {
+			t.Errorf("%s: Expect %v, but got %v", c.name, c.expected, rows)
+		}
+	}
+}
+
+func TestReadUnknownColumn(t *testing.T) {
+	fileName := writeTestFile(t, []testRecord{{Id: 1}})
+	defer os.RemoveAll(filepath.Dir(fileName))
+
+	if _, _, err := readSource(New(fileName, 1).Select("missing")); err == nil {
+		t.Errorf("Expect an error selecting an unknown column")
+	}
+}
@@ -0,0 +1,129 @@
+package sqldb
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/gleamold/gio"
+)
+
+// TimeFormat is a layout for FormatTimes.
+// The times as text in UTC in this layout sort the same as the times.
+const TimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+type SqlShardInfo struct {
+	DriverName     string
+	DataSourceName string
+	Query          string
+	Args           []interface{}
+	// TimeLayout formats the times as text in UTC, if not empty.
+	TimeLayout string
+}
+
+var (
+	MapperReadShard = gio.RegisterMapper(readShard)
+)
+
+func init() {
+	gob.Register(SqlShardInfo{})
+	gob.Register(time.Time{})
+}
+
+func readShard(row []interface{}) error {
+	encodedShardInfo := row[0].([]byte)
+	return decodeShardInfo(encodedShardInfo).ReadSplit()
+}
+
+func (s *SqlShardInfo) ReadSplit() error {
+	return s.readRows(func(values []interface{}) error {
+		return gio.Emit(values...)
+	})
+}
+
+func (s *SqlShardInfo) readRows(emit func([]interface{}) error) error {
+	db, err := sql.Open(s.DriverName, s.DataSourceName)
+	if err != nil {
+		return fmt.Errorf("Failed to open %s database: %v", s.DriverName, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(s.Query, s.Args...)
+	if err != nil {
+		return fmt.Errorf("Failed to query %s %v: %v", s.Query, s.Args, err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return fmt.Errorf("Failed to read column types: %v", err)
+	}
+	values := make([]interface{}, len(columnTypes))
+	pointers := make([]interface{}, len(columnTypes))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	objects := make([]interface{}, len(columnTypes))
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return fmt.Errorf("Failed to scan row: %v", err)
+		}
+		for i, v := range values {
+			objects[i] = toRowValue(v, columnTypes[i], s.TimeLayout)
+		}
+		if err := emit(objects); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Failed to read rows: %v", err)
+	}
+	return nil
+}
+
+// toRowValue keeps the integers, floats, booleans, times, and nulls as they are,
+// and converts the text to strings.
+// The times are converted to text in UTC only if the time layout is set.
+func toRowValue(value interface{}, columnType *sql.ColumnType, timeLayout string) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		if isBinary(columnType.DatabaseTypeName()) {
+			return append([]byte(nil), v...)
+		}
+		return string(v)
+	case time.Time:
+		if timeLayout != "" {
+			return v.UTC().Format(timeLayout)
+		}
+	}
+	return value
+}
+
+func isBinary(databaseTypeName string) bool {
+	name := strings.ToUpper(databaseTypeName)
+	return strings.Contains(name, "BLOB") || strings.Contains(name, "BINARY") || name == "BYTEA"
+}
+
+func decodeShardInfo(encodedShardInfo []byte) *SqlShardInfo {
+	network := bytes.NewBuffer(encodedShardInfo)
+	dec := gob.NewDecoder(network)
+	var p SqlShardInfo
+	if err := dec.Decode(&p); err != nil {
+		log.Fatal("decode shard info", err)
+	}
+	return &p
+}
+
+func encodeShardInfo(shardInfo *SqlShardInfo) []byte {
+	var network bytes.Buffer
+	enc := gob.NewEncoder(&network)
+	if err := enc.Encode(shardInfo); err != nil {
+		log.Fatal("encode shard info:", err)
+	}
+	return network.Bytes()
+}
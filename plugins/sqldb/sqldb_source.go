@@ -0,0 +1,270 @@
+/*
+Package sqldb reads the rows of a query from relational databases via database/sql.
+
+The source follows the same steps as the cassandra plugin:
+1) find the min and max values of the partition column on the driver.
+2) split the range evenly into a list of shard info, and send each to a remote executor.
+3) each executor reads the rows of its range via a mapper.
+
+The database driver needs to be imported by the driver program, e.g.,
+
+	import _ "github.com/go-sql-driver/mysql"
+
+Since the mapper to process shard info is in Go, the call to "gio.Init()"
+is required.
+*/
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/util"
+)
+
+type SqlSource struct {
+	DriverName     string
+	DataSourceName string
+	Query          string
+
+	// PartitionColumn is a numeric or time column of the query.
+	// The range between its min and max values is split evenly, and each executor reads one range.
+	PartitionColumn string
+	PartitionCount  int
+
+	// TimeLayout formats the times in the rows as text in UTC, if not empty.
+	TimeLayout string
+}
+
+// Generate generates data shard info,
+// partitions them via round robin,
+// and reads each shard on each executor
+func (s *SqlSource) Generate(f *flow.Flow) *flow.Dataset {
+	return s.genShardInfos(f).RoundRobin(s.PartitionCount).Mapper(MapperReadShard)
+}
+
+func (s *SqlSource) genShardInfos(f *flow.Flow) *flow.Dataset {
+	return f.Source(func(writer io.Writer) error {
+
+		shardInfos, err := s.fetchShardInfos()
+		if err != nil {
+			return err
+		}
+
+		for _, shardInfo := range shardInfos {
+			util.WriteRow(writer, util.Now(), encodeShardInfo(shardInfo))
+		}
+
+		return nil
+	})
+}
+
+// fetchShardInfos reads the min and max values of the partition column on the driver,
+// and splits the range into one query for each shard.
+func (s *SqlSource) fetchShardInfos() ([]*SqlShardInfo, error) {
+	if s.PartitionColumn == "" || s.PartitionCount <= 1 {
+		return []*SqlShardInfo{s.newShardInfo(s.Query)}, nil
+	}
+
+	db, err := sql.Open(s.DriverName, s.DataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open %s database: %v", s.DriverName, err)
+	}
+	defer db.Close()
+
+	var min, max interface{}
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM (%s) t", s.PartitionColumn, s.PartitionColumn, s.Query)
+	if err = db.QueryRow(query).Scan(&min, &max); err != nil {
+		return nil, fmt.Errorf("Failed to find the range of %s: %v", s.PartitionColumn, err)
+	}
+	if min == nil || max == nil {
+		return []*SqlShardInfo{s.newShardInfo(s.Query)}, nil
+	}
+
+	bounds, err := splitRange(min, max, s.PartitionCount)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to split the range of %s: %v", s.PartitionColumn, err)
+	}
+
+	var shardInfos []*SqlShardInfo
+	for i := 0; i+1 < len(bounds); i++ {
+		lower := fmt.Sprintf("%s >= %s", s.PartitionColumn, s.placeholder(1))
+		upper := fmt.Sprintf("%s < %s", s.PartitionColumn, s.placeholder(2))
+		if i+2 == len(bounds) {
+			upper = fmt.Sprintf("%s <= %s", s.PartitionColumn, s.placeholder(2))
+		}
+		where := lower + " AND " + upper
+		if i == 0 {
+			where = fmt.Sprintf("(%s OR %s IS NULL)", where, s.PartitionColumn)
+		}
+		shardInfo := s.newShardInfo(fmt.Sprintf("SELECT * FROM (%s) t WHERE %s", s.Query, where))
+		shardInfo.Args = []interface{}{bounds[i], bounds[i+1]}
+		shardInfos = append(shardInfos, shardInfo)
+	}
+	return shardInfos, nil
+}
+
+func (s *SqlSource) newShardInfo(query string) *SqlShardInfo {
+	return &SqlShardInfo{
+		DriverName:     s.DriverName,
+		DataSourceName: s.DataSourceName,
+		Query:          query,
+		TimeLayout:     s.TimeLayout,
+	}
+}
+
+// placeholder is the n-th bind parameter in the query, which differs by the database.
+func (s *SqlSource) placeholder(n int) string {
+	switch s.DriverName {
+	case "postgres", "pgx":
+		return "$" + strconv.Itoa(n)
+	case "sqlserver", "mssql":
+		return "@p" + strconv.Itoa(n)
+	case "oracle", "godror", "oci8":
+		return ":" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// splitRange splits [min, max] into at most n ranges with the same width.
+// The returned bounds are [min, b1, b2, ..., max].
+// The times returned as text are split into text bounds of the same layout,
+// so they are compared with the column values as text.
+func splitRange(min, max interface{}, n int) ([]interface{}, error) {
+	layout := textTimeLayout(min)
+	min, max = toRangeValue(min), toRangeValue(max)
+	switch lo := min.(type) {
+	case int64:
+		hi, ok := max.(int64)
+		if !ok {
+			break
+		}
+		var bounds []interface{}
+		for _, b := range splitInt64(lo, hi, n) {
+			bounds = append(bounds, b)
+		}
+		return bounds, nil
+	case float64:
+		hi, ok := max.(float64)
+		if !ok {
+			break
+		}
+		if hi <= lo || math.IsInf(hi-lo, 0) || math.IsNaN(hi-lo) {
+			return []interface{}{lo, hi}, nil
+		}
+		bounds := []interface{}{lo}
+		width := (hi - lo) / float64(n)
+		for i := 1; i < n; i++ {
+			bounds = append(bounds, lo+width*float64(i))
+		}
+		return append(bounds, hi), nil
+	case time.Time:
+		hi, ok := max.(time.Time)
+		if !ok {
+			break
+		}
+		var bounds []interface{}
+		for _, b := range splitInt64(lo.UnixNano(), hi.UnixNano(), n) {
+			t := time.Unix(0, b).In(lo.Location())
+			if layout != "" {
+				bounds = append(bounds, t.Format(layout))
+			} else {
+				bounds = append(bounds, t)
+			}
+		}
+		return bounds, nil
+	}
+	return nil, fmt.Errorf("expecting numbers or times, but got %v(%T) and %v(%T)", min, min, max, max)
+}
+
+func splitInt64(lo, hi int64, n int) []int64 {
+	if hi <= lo {
+		return []int64{lo, hi}
+	}
+	// the span may overflow int64, but not uint64
+	span := uint64(hi) - uint64(lo)
+	if span < uint64(n) {
+		n = int(span)
+	}
+	width := span / uint64(n)
+	bounds := []int64{lo}
+	for i := 1; i < n; i++ {
+		bounds = append(bounds, int64(uint64(lo)+width*uint64(i)))
+	}
+	return append(bounds, hi)
+}
+
+// timeLayouts are the layouts to parse the times returned as text, e.g., by SQLite.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// toRangeValue converts the min and max values to int64, float64, or time.Time.
+func toRangeValue(value interface{}) interface{} {
+	var text string
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return value
+	}
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+	if t, _, ok := parseTime(text); ok {
+		return t
+	}
+	return value
+}
+
+func parseTime(text string) (time.Time, string, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, layout, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// textTimeLayout is the layout of a time returned as text, or "" if it is not.
+func textTimeLayout(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return ""
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		return ""
+	}
+	_, layout, _ := parseTime(text)
+	return layout
+}
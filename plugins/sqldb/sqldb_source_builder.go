@@ -0,0 +1,31 @@
+package sqldb
+
+/*
+This file is only for the builder API.
+*/
+
+// New creates a SqlSource reading the rows of the query in one shard.
+func New(driverName, dataSourceName, query string) *SqlSource {
+	return &SqlSource{
+		DriverName:     driverName,
+		DataSourceName: dataSourceName,
+		Query:          query,
+		PartitionCount: 1,
+	}
+}
+
+// PartitionBy reads the query in parallel, split into partitionCount ranges of a numeric or time column.
+// The rows with null values in the column are read with the first range.
+func (s *SqlSource) PartitionBy(column string, partitionCount int) *SqlSource {
+	s.PartitionColumn = column
+	s.PartitionCount = partitionCount
+	return s
+}
+
+// FormatTimes emits the times as text in UTC with the layout, e.g., sqldb.TimeFormat.
+// By default, the times are emitted as time.Time, which the next steps read
+// as an array of the Unix seconds and nanoseconds.
+func (s *SqlSource) FormatTimes(layout string) *SqlSource {
+	s.TimeLayout = layout
+	return s
+}
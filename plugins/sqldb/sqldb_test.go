@@ -0,0 +1,148 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/gleamold/util"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestDatabase(t *testing.T) string {
+	dataSourceName := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err = db.Exec("CREATE TABLE users (id INTEGER, name TEXT, score REAL, created DATETIME, avatar BLOB)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 10; i++ {
+		_, err = db.Exec("INSERT INTO users VALUES (?, ?, ?, ?, ?)",
+			i, fmt.Sprintf("user%d", i), float64(i)/2, created.AddDate(0, 0, i), []byte{byte(i)})
+		if err != nil {
+			t.Fatalf("Failed to insert: %v", err)
+		}
+	}
+	if _, err = db.Exec("INSERT INTO users (name) VALUES ('nobody')"); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+	return dataSourceName
+}
+
+func readAll(t *testing.T, s *SqlSource) (rows [][]interface{}, shardCount int) {
+	shardInfos, err := s.fetchShardInfos()
+	if err != nil {
+		t.Fatalf("Failed to fetch shard infos: %v", err)
+	}
+	for _, shardInfo := range shardInfos {
+		shardInfo = decodeShardInfo(encodeShardInfo(shardInfo))
+		err = shardInfo.readRows(func(values []interface{}) error {
+			rows = append(rows, append([]interface{}(nil), values...))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", shardInfo.Query, err)
+		}
+	}
+	return rows, len(shardInfos)
+}
+
+func TestPartitionedRead(t *testing.T) {
+	dataSourceName := newTestDatabase(t)
+
+	for _, column := range []string{"id", "score", "created"} {
+		s := New("sqlite3", dataSourceName, "SELECT id, name FROM users").PartitionBy(column, 3)
+		if column != "id" {
+			s.Query = "SELECT id, name, " + column + " FROM users"
+		}
+		rows, shardCount := readAll(t, s)
+		if shardCount != 3 {
+			t.Errorf("Expect 3 shards by %s, but got %d", column, shardCount)
+		}
+		var names []string
+		for _, row := range rows {
+			names = append(names, row[1].(string))
+		}
+		sort.Strings(names)
+		if fmt.Sprint(names) != "[nobody user1 user10 user2 user3 user4 user5 user6 user7 user8 user9]" {
+			t.Errorf("Unexpected rows partitioned by %s: %v", column, names)
+		}
+	}
+}
+
+func TestRowTypes(t *testing.T) {
+	dataSourceName := newTestDatabase(t)
+
+	rows, _ := readAll(t, New("sqlite3", dataSourceName, "SELECT id, name, score, created, avatar FROM users WHERE id = 2"))
+	if len(rows) != 1 {
+		t.Fatalf("Expect 1 row, but got %d", len(rows))
+	}
+	created := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	expected := []interface{}{int64(2), "user2", 1.0, created, []byte{2}}
+	if fmt.Sprintf("%#v", rows[0]) != fmt.Sprintf("%#v", expected) {
+		t.Errorf("Expect %#v, but got %#v", expected, rows[0])
+	}
+
+	encoded, err := util.EncodeRow(0, rows[0]...)
+	if err != nil {
+		t.Fatalf("Failed to encode %v: %v", rows[0], err)
+	}
+	var id int64
+	var name string
+	var score float64
+	var decoded time.Time
+	if err = util.DecodeRowTo(encoded, &id, &name, &score, &decoded); err != nil || !decoded.Equal(created) {
+		t.Errorf("Expect the time %v to be decoded, but got %v: %v", created, decoded, err)
+	}
+	_, row, _ := util.DecodeRow(encoded)
+	if fmt.Sprint(row[3]) != fmt.Sprint([]interface{}{created.Unix(), 0}) {
+		t.Errorf("Expect the time as the Unix seconds and nanoseconds, but got %#v", row[3])
+	}
+}
+
+func TestFormatTimes(t *testing.T) {
+	dataSourceName := newTestDatabase(t)
+
+	s := New("sqlite3", dataSourceName, "SELECT id, created FROM users WHERE id = 2").FormatTimes(TimeFormat)
+	rows, _ := readAll(t, s)
+	if len(rows) != 1 {
+		t.Fatalf("Expect 1 row, but got %d", len(rows))
+	}
+	if rows[0][1] != "2020-01-03T00:00:00.000000000Z" {
+		t.Errorf("Expect the time as text, but got %#v", rows[0][1])
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	for _, c := range []struct {
+		min, max interface{}
+		n        int
+		expected string
+	}{
+		{int64(0), int64(10), 3, "[0 3 6 10]"},
+		{int64(1), int64(2), 4, "[1 2]"},
+		{int64(5), int64(5), 4, "[5 5]"},
+		{int64(math.MinInt64), int64(math.MaxInt64), 2, "[-9223372036854775808 -1 9223372036854775807]"},
+		{[]byte("10"), "20", 2, "[10 15 20]"},
+		{0.0, 1.0, 4, "[0 0.25 0.5 0.75 1]"},
+		{"2020-01-01", "2020-01-05", 2, "[2020-01-01 2020-01-03 2020-01-05]"},
+	} {
+		bounds, err := splitRange(c.min, c.max, c.n)
+		if err != nil || fmt.Sprint(bounds) != c.expected {
+			t.Errorf("Expect %v to %v split into %s, but got %v: %v", c.min, c.max, c.expected, bounds, err)
+		}
+	}
+
+	if _, err := splitRange("apple", "banana", 2); err == nil {
+		t.Errorf("Expect an error to split text")
+	}
+}
@@ -103,6 +103,100 @@ end
 	})
 }
 
+// HashReduceBy partially reduces unsorted rows with the same keys.
+// Rows are aggregated in a hash table of at most maxKeys entries,
+// which is flushed whenever it is full, so the output may still
+// contain duplicated keys.
+func (c *LuaScript) HashReduceBy(code string, indexes []int, maxKeys int) {
+	c.operations = append(c.operations, &Operation{
+		Type: "HashReduceBy",
+		Code: fmt.Sprintf(`
+local keyIndexes = {%s}
+local keyIndexesSet = set(keyIndexes)
+local keyWidth = #keyIndexes
+local maxKeys = %d
+
+local function _getKeysAndValues(row)
+  local keys, values = listNew(), listNew()
+  local ts = listUnpackTs(row)
+  for i=2, row.n, 1 do
+    if keyIndexesSet[i-1] then
+      listInsert(keys, row[i])
+    else
+      listInsert(values, row[i])
+    end
+  end
+  if row.n-2 == keyWidth then
+    return ts, keys, values[1]
+  end
+  return ts, keys, values
+end
+
+local function _writeKeyValues(ts, keys, values, rowWidth)
+  row = listNew()
+  listInsert(row, ts)
+  listExtend(row, keys)
+  if rowWidth-2 == keyWidth then
+    listInsert(row, values)
+  else
+    listExtend(row, values)
+  end
+  writeRowTs(listUnpackAll(row))
+end
+
+local function _hashKey(keys)
+  local packed = {}
+  for i=1, keys.n do
+    packed[i] = mp.pack(keys[i])
+  end
+  return table.concat(packed)
+end
+
+local _reduce = %s
+
+local entries, entryCount = {}, 0
+
+local function _flush()
+  for _, e in pairs(entries) do
+    _writeKeyValues(e.ts, e.keys, e.values, e.width)
+  end
+  entries, entryCount = {}, 0
+end
+
+while true do
+  local row = readRow()
+  if not row then break end
+
+  local ts, keys, values = _getKeysAndValues(row)
+  local hashKey = _hashKey(keys)
+  local e = entries[hashKey]
+  if not e then
+    if entryCount >= maxKeys then
+      _flush()
+    end
+    entries[hashKey] = {ts=ts, keys=keys, values=values, width=row.n}
+    entryCount = entryCount + 1
+  else
+    local params = listNew()
+    if row.n-2 == keyWidth then
+      listInsert(params, e.values)
+      listInsert(params, values)
+      e.values = _reduce(listUnpackAll(params))
+    else
+      listExtend(params, e.values)
+      listExtend(params, values)
+      e.values = listNew(_reduce(listUnpackAll(params)))
+    end
+    if ts > e.ts then
+      e.ts = ts
+    end
+  end
+end
+_flush()
+`, genKeyIndexes(indexes), maxKeys, code),
+	})
+}
+
 func (c *LuaScript) GroupBy(indexes []int) {
 	c.operations = append(c.operations, &Operation{
 		Type: "GroupBy",
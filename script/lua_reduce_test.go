@@ -154,6 +154,41 @@ func TestLuaReduceByWithNil(t *testing.T) {
 	)
 }
 
+func TestLuaHashReduceBy(t *testing.T) {
+
+	ts := time.Now().UnixNano() / int64(time.Millisecond)
+
+	testLuaScript(
+		"test HashReduceBy",
+		func(script Script) {
+			script.HashReduceBy(`
+				function(x, y)
+					return x+y
+				end
+			`, []int{1}, 100)
+		},
+		func(inputWriter io.Writer) {
+			util.WriteRow(inputWriter, ts, "key2", 3)
+			util.WriteRow(inputWriter, ts, "key1", 100)
+			util.WriteRow(inputWriter, ts, "key2", 4)
+		},
+		func(outputReader io.Reader) {
+			sums := make(map[string]uint64)
+			for {
+				_, row, err := util.ReadRow(outputReader)
+				if err != nil {
+					break
+				}
+				t.Logf("row: %+v", row)
+				sums[row[0].(string)] += row[1].(uint64)
+			}
+			if len(sums) != 2 || sums["key1"] != 100 || sums["key2"] != 7 {
+				t.Errorf("failed HashReduceBy results: %+v", sums)
+			}
+		},
+	)
+}
+
 func TestLuaGroupByMultipleValue(t *testing.T) {
 
 	ts := time.Now().UnixNano() / int64(time.Millisecond)
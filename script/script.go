@@ -20,6 +20,7 @@ type Script interface {
 	FlatMap(code string)
 	Reduce(code string)
 	ReduceBy(code string, indexes []int)
+	HashReduceBy(code string, indexes []int, maxKeys int)
 	Filter(code string)
 	GroupBy(indexes []int)
 	Select(indexes []int)
@@ -19,6 +19,9 @@ type Statement struct {
 	Text       string
 	Plan       plan.Plan
 	startTime  time.Time
+
+	// Flow is where the steps are added, the same flow as the datasets of the tables.
+	Flow *flow.Flow
 }
 
 func (a *Statement) OriginText() string {
@@ -32,7 +35,7 @@ func (a *Statement) OriginText() string {
 func (a *Statement) Exec(ctx context.Context) (*flow.Dataset, error) {
 	a.startTime = time.Now()
 
-	b := newExecutorBuilder(ctx, a.InfoSchema)
+	b := newExecutorBuilder(ctx, a.InfoSchema, a.Flow)
 
 	exe := b.build(a.Plan)
 	if b.err != nil {
@@ -3,7 +3,10 @@ package executor
 import (
 	"fmt"
 
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/ast"
 	"github.com/chrislusf/gleamold/sql/context"
+	"github.com/chrislusf/gleamold/sql/expression"
 	"github.com/chrislusf/gleamold/sql/infoschema"
 	"github.com/chrislusf/gleamold/sql/model"
 	"github.com/chrislusf/gleamold/sql/plan"
@@ -12,16 +15,18 @@ import (
 // executorBuilder builds an Executor from a Plan.
 // The InfoSchema must not change during execution.
 type executorBuilder struct {
-	ctx context.Context
-	is  infoschema.InfoSchema
+	ctx  context.Context
+	is   infoschema.InfoSchema
+	flow *flow.Flow
 	// If there is any error during Executor building process, err is set.
 	err error
 }
 
-func newExecutorBuilder(ctx context.Context, is infoschema.InfoSchema) *executorBuilder {
+func newExecutorBuilder(ctx context.Context, is infoschema.InfoSchema, f *flow.Flow) *executorBuilder {
 	return &executorBuilder{
-		ctx: ctx,
-		is:  is,
+		ctx:  ctx,
+		is:   is,
+		flow: f,
 	}
 }
 
@@ -75,10 +80,8 @@ func (b *executorBuilder) build(p plan.Plan) Executor {
 		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return nil
 	case *plan.Sort:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return b.buildSort(v)
 	case *plan.Union:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return b.buildUnion(v)
 	case *plan.Update:
 		b.err = fmt.Errorf("Unknown Plan %T", p)
@@ -86,16 +89,12 @@ func (b *executorBuilder) build(p plan.Plan) Executor {
 	case *plan.PhysicalUnionScan:
 		return b.buildUnionScanExec(v)
 	case *plan.PhysicalHashJoin:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return b.buildJoin(v)
 	case *plan.PhysicalHashSemiJoin:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
-		return nil // b.buildSemiJoin(v)
+		return b.buildSemiJoin(v)
 	case *plan.Selection:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return b.buildSelection(v)
 	case *plan.PhysicalAggregation:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return b.buildAggregation(v)
 	case *plan.Projection:
 		return b.buildProjection(v)
@@ -108,20 +107,16 @@ func (b *executorBuilder) build(p plan.Plan) Executor {
 		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return nil
 	case *plan.TableDual:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return b.buildTableDual(v)
 	case *plan.PhysicalApply:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return b.buildApply(v)
 	case *plan.Exists:
 		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return nil
 	case *plan.MaxOneRow:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
-		return nil
+		return b.buildMaxOneRow(v)
 	case *plan.Trim:
-		b.err = fmt.Errorf("Unknown Plan %T", p)
-		return nil
+		return b.buildTrim(v)
 	case *plan.PhysicalDummyScan:
 		b.err = fmt.Errorf("Unknown Plan %T", p)
 		return nil
@@ -176,32 +171,292 @@ func (b *executorBuilder) buildUnionScanExec(v *plan.PhysicalUnionScan) Executor
 	}
 	return us
 }
+
 func (b *executorBuilder) buildJoin(v *plan.PhysicalHashJoin) Executor {
-	return nil
+	left := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	right := b.build(v.GetChildByIndex(1))
+	if b.err != nil {
+		return nil
+	}
+	e := &HashJoinExec{
+		Left:     left,
+		Right:    right,
+		schema:   v.GetSchema(),
+		joinType: v.JoinType,
+	}
+
+	// the conditions on the outer side only decide whether the rows can match
+	leftKeys, rightKeys := joinKeys(v.EqualConditions, left.Schema())
+	var err error
+	if e.leftInput, err = joinInput(leftKeys, v.LeftConditions, left.Schema(), allFields(left.Schema())...); err != nil {
+		b.err = err
+		return nil
+	}
+	if e.rightInput, err = joinInput(rightKeys, v.RightConditions, right.Schema(), allFields(right.Schema())...); err != nil {
+		b.err = err
+		return nil
+	}
+	e.keyCount = joinKeyCount(leftKeys)
+
+	if len(v.OtherConditions) > 0 {
+		if v.JoinType != plan.InnerJoin {
+			b.err = fmt.Errorf("Unsupported conditions %v on outer join", v.OtherConditions)
+			return nil
+		}
+		if e.otherFilter, err = luaFilter(v.OtherConditions, v.GetSchema()); err != nil {
+			b.err = err
+			return nil
+		}
+	}
+	return e
+}
+
+func (b *executorBuilder) buildSemiJoin(v *plan.PhysicalHashSemiJoin) Executor {
+	left := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	right := b.build(v.GetChildByIndex(1))
+	if b.err != nil {
+		return nil
+	}
+	e := &SemiJoinExec{
+		Left:    left,
+		Right:   right,
+		schema:  v.GetSchema(),
+		withAux: v.WithAux,
+		anti:    v.Anti,
+	}
+
+	// the unknown null-aware conditions only matter for the rows without any matches, which are kept or returned
+	var equalConditions []*expression.ScalarFunction
+	var otherConditions, nullAwareConditions []expression.Expression
+	for _, cond := range v.EqualConditions {
+		if (v.Anti || v.WithAux) && isNullAware(cond, v.NullAware) {
+			nullAwareConditions = append(nullAwareConditions, cond)
+		} else {
+			equalConditions = append(equalConditions, cond)
+		}
+	}
+	for _, cond := range v.OtherConditions {
+		if (v.Anti || v.WithAux) && isNullAware(cond, v.NullAware) {
+			nullAwareConditions = append(nullAwareConditions, cond)
+		} else {
+			otherConditions = append(otherConditions, cond)
+		}
+	}
+	// a single null-aware equal condition is the last join key, with the NULL keys on the right side checked separately
+	if len(nullAwareConditions) == 1 && len(otherConditions) == 0 && len(v.LeftConditions) == 0 {
+		if cond, ok := nullAwareConditions[0].(*expression.ScalarFunction); ok && cond.FuncName.L == ast.EQ {
+			equalConditions = append(equalConditions, cond)
+			nullAwareConditions = nil
+			e.nullAwareKey = true
+		}
+	}
+
+	leftKeys, rightKeys := joinKeys(equalConditions, left.Schema())
+	var err error
+	if e.leftInput, err = joinInput(leftKeys, v.LeftConditions, left.Schema(), allFields(left.Schema())...); err != nil {
+		b.err = err
+		return nil
+	}
+	e.keyCount = joinKeyCount(leftKeys)
+	e.constantKey = len(leftKeys) == 0
+
+	// the right rows are only matched, so the rows not matching the conditions on the right side are removed first
+	if len(v.RightConditions) > 0 {
+		if e.rightFilter, err = luaFilter(v.RightConditions, right.Schema()); err != nil {
+			b.err = err
+			return nil
+		}
+	}
+
+	if len(otherConditions) == 0 && len(nullAwareConditions) == 0 {
+		if e.rightInput, err = joinInput(rightKeys, nil, right.Schema()); err != nil {
+			b.err = err
+			return nil
+		}
+		return e
+	}
+
+	// the other conditions are checked on the fields of both sides
+	if e.rightInput, err = joinInput(rightKeys, nil, right.Schema(), allFields(right.Schema())...); err != nil {
+		b.err = err
+		return nil
+	}
+	schema := expression.MergeSchema(left.Schema(), right.Schema())
+	if e.otherCheck, err = luaFilter(otherConditions, schema); err != nil {
+		b.err = err
+		return nil
+	}
+	if len(nullAwareConditions) > 0 {
+		if e.nullAwareCheck, err = luaNullAwareCheck(nullAwareConditions, schema); err != nil {
+			b.err = err
+			return nil
+		}
+	}
+	return e
+}
+
+// isNullAware checks whether the condition is one of the null-aware conditions of the semi join.
+func isNullAware(cond expression.Expression, nullAware []expression.Expression) bool {
+	for _, c := range nullAware {
+		if c == cond {
+			return true
+		}
+	}
+	return false
 }
 
 func (b *executorBuilder) buildAggregation(v *plan.PhysicalAggregation) Executor {
-	return nil
+	src := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	e := &AggregationExec{
+		Src:    src,
+		schema: v.GetSchema(),
+	}
+
+	// the keys and the arguments are computed as the input fields
+	inputs := append([]expression.Expression{}, v.GroupByItems...)
+	var argIndexes []int
+	for _, f := range v.AggFuncs {
+		a := aggregate{name: f.GetName(), distinct: f.IsDistinct()}
+		switch a.name {
+		case ast.AggFuncMin, ast.AggFuncMax, ast.AggFuncFirstRow:
+			a.distinct = false
+		case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncGroupConcat:
+		default:
+			b.err = fmt.Errorf("Unknown aggregation function %s", f)
+			return nil
+		}
+		args := f.GetArgs()
+		if a.distinct {
+			if a.name != ast.AggFuncCount || len(args) != 1 {
+				b.err = fmt.Errorf("Unsupported distinct aggregation %s", f)
+				return nil
+			}
+			e.complete = true
+		}
+		if a.name == ast.AggFuncCount && !a.distinct && isNotNullConstant(args) {
+			argIndexes = append(argIndexes, -1)
+			e.aggs = append(e.aggs, a)
+			continue
+		}
+		arg := args[0]
+		if len(args) > 1 {
+			var err error
+			if arg, err = expression.NewFunction(b.ctx, ast.Concat, f.GetType(), args...); err != nil {
+				b.err = err
+				return nil
+			}
+		}
+		argIndexes = append(argIndexes, len(inputs))
+		inputs = append(inputs, arg)
+		e.aggs = append(e.aggs, a)
+	}
+
+	fields, input, err := inputFields(inputs, src.Schema())
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	e.input = input
+	e.keys = fields[:len(v.GroupByItems)]
+	for i, argIndex := range argIndexes {
+		if argIndex >= 0 {
+			e.aggs[i].index = fields[argIndex]
+		}
+	}
+	return e
+}
+
+// inputFields finds the fields of the expressions, starting from 1.
+// If any expression is not a column, the input is the Lua function computing all the expressions as fields.
+func inputFields(exprs []expression.Expression, schema expression.Schema) (fields []int, input string, err error) {
+	for _, expr := range exprs {
+		col, ok := expr.(*expression.Column)
+		if !ok || schema.GetColumnIndex(col) < 0 {
+			break
+		}
+		fields = append(fields, schema.GetColumnIndex(col)+1)
+	}
+	if len(fields) == len(exprs) {
+		return fields, "", nil
+	}
+	if input, err = luaMap(exprs, schema); err != nil {
+		return nil, "", err
+	}
+	fields = fields[:0]
+	for i := range exprs {
+		fields = append(fields, i+1)
+	}
+	return fields, input, nil
+}
+
+func isNotNullConstant(exprs []expression.Expression) bool {
+	if len(exprs) != 1 {
+		return false
+	}
+	c, ok := exprs[0].(*expression.Constant)
+	return ok && !c.Value.IsNull()
 }
 
 func (b *executorBuilder) buildSelection(v *plan.Selection) Executor {
-	return nil
+	src := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	filter, err := luaFilter(v.Conditions, src.Schema())
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	return &SelectionExec{
+		Src:    src,
+		schema: v.GetSchema(),
+		filter: filter,
+	}
 }
 
 func (b *executorBuilder) buildProjection(v *plan.Projection) Executor {
-	return &ProjectionExec{
-		Src:    b.build(v.GetChildByIndex(0)),
+	src := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	e := &ProjectionExec{
+		Src:    src,
 		ctx:    b.ctx,
 		exprs:  v.Exprs,
 		schema: v.GetSchema(),
 	}
+	if !isSameColumns(v.Exprs, src.Schema()) {
+		mapper, err := luaMap(v.Exprs, src.Schema())
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		e.mapper = mapper
+	}
+	return e
 }
 
 func (b *executorBuilder) buildTableDual(v *plan.TableDual) Executor {
-	return nil
+	return &TableDualExec{
+		flow:   b.flow,
+		schema: v.GetSchema(),
+	}
 }
 
 func (b *executorBuilder) buildTableScan(v *plan.PhysicalTableScan) Executor {
+	if t, found := Tables[v.Table.Name.String()]; found && t.Dataset.Flow != b.flow {
+		b.err = fmt.Errorf("Table %s is registered in another flow", v.Table.Name)
+		return nil
+	}
 	table, _ := b.is.TableByName(model.NewCIStr(""), v.Table.Name)
 	st := &SelectTableExec{
 		tableInfo:  v.Table,
@@ -225,13 +480,180 @@ func (b *executorBuilder) buildTableScan(v *plan.PhysicalTableScan) Executor {
 }
 
 func (b *executorBuilder) buildSort(v *plan.Sort) Executor {
-	return nil
+	src := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	e := &SortExec{
+		Src:    src,
+		schema: v.GetSchema(),
+	}
+	if v.ExecLimit != nil {
+		e.hasLimit = true
+		e.offset = v.ExecLimit.Offset
+		e.count = v.ExecLimit.Count
+	}
+
+	var exprs []expression.Expression
+	for _, item := range v.ByItems {
+		exprs = append(exprs, item.Expr)
+		e.desc = append(e.desc, item.Desc)
+	}
+	if e.keys = sortKeys(exprs, src.Schema()); e.keys != nil {
+		return e
+	}
+
+	// the order-by items are computed and appended after all the fields
+	outputs := allFields(src.Schema())
+	for _, expr := range exprs {
+		o, err := luaExpression(expr, src.Schema())
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		outputs = append(outputs, o)
+		e.keys = append(e.keys, len(outputs))
+	}
+	e.input = luaFunction(src.Schema().Len(), outputs)
+	return e
 }
 
+// sortKeys finds the fields of the expressions, starting from 1, or nil if any expression is not a column.
+func sortKeys(exprs []expression.Expression, schema expression.Schema) (keys []int) {
+	for _, expr := range exprs {
+		col, ok := expr.(*expression.Column)
+		if !ok || schema.GetColumnIndex(col) < 0 {
+			return nil
+		}
+		keys = append(keys, schema.GetColumnIndex(col)+1)
+	}
+	return keys
+}
+
+// buildApply runs the correlated subquery, which is not decorrelated into a join, for each outer row.
+// The operators of the subquery down to its uncorrelated part run in Lua, see ApplyExec.
 func (b *executorBuilder) buildApply(v *plan.PhysicalApply) Executor {
-	return nil
+	outer := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	var ops []plan.Plan
+	p := v.GetChildByIndex(1)
+	for len(plan.CorrelatedColumns(p)) > 0 {
+		if len(p.GetChildren()) != 1 {
+			b.err = fmt.Errorf("Unsupported correlated subquery %s", plan.ToString(p))
+			return nil
+		}
+		ops = append(ops, p)
+		p = p.GetChildByIndex(0)
+	}
+	inner := b.build(p)
+	if b.err != nil {
+		return nil
+	}
+	e := &ApplyExec{
+		Outer:  outer,
+		Inner:  inner,
+		schema: v.GetSchema(),
+	}
+	var err error
+	if e.subquery, err = luaSubquery(ops, outer.Schema()); err != nil {
+		b.err = err
+		return nil
+	}
+
+	// the join conditions are checked on the outer row and each row of the subquery
+	subquery := v.GetChildByIndex(1).GetSchema()
+	schema := expression.MergeSchema(outer.Schema(), subquery)
+	switch j := v.PhysicalJoin.(type) {
+	case *plan.PhysicalHashJoin:
+		if j.JoinType != plan.InnerJoin && j.JoinType != plan.LeftOuterJoin {
+			b.err = fmt.Errorf("Unsupported join type %v of correlated subquery", j.JoinType)
+			return nil
+		}
+		var conditions []expression.Expression
+		for _, cond := range j.EqualConditions {
+			conditions = append(conditions, cond)
+		}
+		conditions = append(conditions, j.LeftConditions...)
+		conditions = append(conditions, j.RightConditions...)
+		conditions = append(conditions, j.OtherConditions...)
+		check, err := luaFilter(decorrelate(conditions, outer.Schema()), schema)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		e.join = luaJoinRows(outer.Schema().Len(), subquery.Len(), check, j.JoinType == plan.LeftOuterJoin)
+	case *plan.PhysicalHashSemiJoin:
+		var conditions, nullAwareConditions []expression.Expression
+		for _, cond := range j.EqualConditions {
+			if (j.Anti || j.WithAux) && isNullAware(cond, j.NullAware) {
+				nullAwareConditions = append(nullAwareConditions, cond)
+			} else {
+				conditions = append(conditions, cond)
+			}
+		}
+		conditions = append(conditions, j.LeftConditions...)
+		conditions = append(conditions, j.RightConditions...)
+		for _, cond := range j.OtherConditions {
+			if (j.Anti || j.WithAux) && isNullAware(cond, j.NullAware) {
+				nullAwareConditions = append(nullAwareConditions, cond)
+			} else {
+				conditions = append(conditions, cond)
+			}
+		}
+		check, err := luaFilter(decorrelate(conditions, outer.Schema()), schema)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		var nullAwareCheck string
+		if len(nullAwareConditions) > 0 {
+			if nullAwareCheck, err = luaNullAwareCheck(decorrelate(nullAwareConditions, outer.Schema()), schema); err != nil {
+				b.err = err
+				return nil
+			}
+		}
+		e.join = luaSemiJoinRows(outer.Schema().Len(), subquery.Len(), check, nullAwareCheck, j.Anti, j.WithAux)
+	default:
+		b.err = fmt.Errorf("Unsupported join %T of correlated subquery", v.PhysicalJoin)
+		return nil
+	}
+	return e
+}
+
+func (b *executorBuilder) buildMaxOneRow(v *plan.MaxOneRow) Executor {
+	src := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	return &MaxOneRowExec{
+		Src:    src,
+		schema: v.GetSchema(),
+	}
+}
+
+func (b *executorBuilder) buildTrim(v *plan.Trim) Executor {
+	src := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	return &TrimExec{
+		Src:    src,
+		schema: v.GetSchema(),
+	}
 }
 
 func (b *executorBuilder) buildUnion(v *plan.Union) Executor {
-	return nil
+	e := &UnionExec{
+		schema: v.GetSchema(),
+	}
+	for _, child := range v.GetChildren() {
+		src := b.build(child)
+		if b.err != nil {
+			return nil
+		}
+		e.Srcs = append(e.Srcs, src)
+	}
+	return e
 }
@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/ast"
+	"github.com/chrislusf/gleamold/sql/expression"
+)
+
+// AggregationExec groups the rows by the group-by items, and computes one field for each aggregation function.
+// The aggregation functions are partially computed within each shard, and the partial results
+// are shuffled by the keys and merged. With COUNT(DISTINCT ...), the rows are shuffled without partial aggregation.
+type AggregationExec struct {
+	Src    Executor
+	schema expression.Schema
+	// input is the Lua function computing the keys and the arguments, empty if they are all columns.
+	input string
+	// keys are the fields of the group-by items, starting from 1.
+	keys     []int
+	aggs     []aggregate
+	complete bool
+}
+
+// aggregate is an aggregation function on the field index, starting from 1.
+// The index is 0 for COUNT(*).
+type aggregate struct {
+	name     string
+	index    int
+	distinct bool
+}
+
+// Schema implements the Executor Schema interface.
+func (e *AggregationExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *AggregationExec) Exec() *flow.Dataset {
+	d := e.Src.Exec()
+
+	if e.input != "" {
+		d = d.Map(e.input)
+	}
+
+	var keys *flow.SortOption
+	if len(e.keys) > 0 {
+		keys = flow.Field(e.keys...)
+	}
+
+	if e.complete {
+		var aggs []flow.Aggregator
+		for _, a := range e.aggs {
+			aggs = append(aggs, a.complete())
+		}
+		return e.results(d.Aggregate(keys, aggs...))
+	}
+
+	var partials, finals []flow.Aggregator
+	index := len(e.keys) + 1
+	for _, a := range e.aggs {
+		p := a.partial()
+		partials = append(partials, p...)
+		finals = append(finals, a.final(index)...)
+		index += len(p)
+	}
+
+	if keys == nil {
+		d = d.LocalAggregate(nil, partials...).Aggregate(nil, finals...)
+	} else {
+		// the partial results have the keys in the leading fields
+		var partialKeys []int
+		for i := range e.keys {
+			partialKeys = append(partialKeys, i+1)
+		}
+		d = d.LocalSort(keys).LocalAggregate(keys, partials...)
+		d = d.Aggregate(flow.Field(partialKeys...), finals...)
+	}
+	return e.results(d)
+}
+
+// results drops the keys, and computes the averages from the merged sums and counts.
+func (e *AggregationExec) results(d *flow.Dataset) *flow.Dataset {
+	var outputs []string
+	var fields []int
+	hasAvg := false
+	index := len(e.keys)
+	for _, a := range e.aggs {
+		if a.name == ast.AggFuncAvg && !e.complete {
+			outputs = append(outputs, "_avg("+luaParam(index)+", "+luaParam(index+1)+")")
+			index += 2
+			hasAvg = true
+			continue
+		}
+		outputs = append(outputs, luaParam(index))
+		fields = append(fields, index+1)
+		index++
+	}
+
+	if hasAvg {
+		return d.Map(luaFunction(index, outputs))
+	}
+	if len(e.keys) > 0 {
+		return d.Select(flow.Field(fields...))
+	}
+	return d
+}
+
+// partial returns the aggregators computed within each shard before shuffling.
+func (a aggregate) partial() []flow.Aggregator {
+	switch a.name {
+	case ast.AggFuncCount:
+		if a.index == 0 {
+			return []flow.Aggregator{flow.Count()}
+		}
+		return []flow.Aggregator{flow.CountNotNull(a.index)}
+	case ast.AggFuncSum:
+		return []flow.Aggregator{flow.Sum(a.index)}
+	case ast.AggFuncAvg:
+		return []flow.Aggregator{flow.Sum(a.index), flow.CountNotNull(a.index)}
+	case ast.AggFuncMin:
+		return []flow.Aggregator{flow.Min(a.index)}
+	case ast.AggFuncMax:
+		return []flow.Aggregator{flow.Max(a.index)}
+	case ast.AggFuncGroupConcat:
+		return []flow.Aggregator{flow.Concat(a.index)}
+	}
+	return []flow.Aggregator{flow.First(a.index)}
+}
+
+// final returns the aggregators merging the partial results starting from field index.
+func (a aggregate) final(index int) []flow.Aggregator {
+	switch a.name {
+	case ast.AggFuncCount, ast.AggFuncSum:
+		return []flow.Aggregator{flow.Sum(index)}
+	case ast.AggFuncAvg:
+		return []flow.Aggregator{flow.Sum(index), flow.Sum(index + 1)}
+	case ast.AggFuncMin:
+		return []flow.Aggregator{flow.Min(index)}
+	case ast.AggFuncMax:
+		return []flow.Aggregator{flow.Max(index)}
+	case ast.AggFuncGroupConcat:
+		return []flow.Aggregator{flow.Concat(index)}
+	}
+	return []flow.Aggregator{flow.First(index)}
+}
+
+// complete returns the aggregator computed from all the rows of each group.
+func (a aggregate) complete() flow.Aggregator {
+	switch {
+	case a.name == ast.AggFuncCount && a.distinct:
+		return flow.CountDistinct(a.index)
+	case a.name == ast.AggFuncAvg:
+		return flow.Avg(a.index)
+	}
+	return a.partial()[0]
+}
@@ -0,0 +1,287 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/ast"
+	"github.com/chrislusf/gleamold/sql/expression"
+	"github.com/chrislusf/gleamold/sql/plan"
+)
+
+// ApplyExec runs the correlated subquery for each outer row, where the subquery is not decorrelated into a join.
+// The rows of the uncorrelated part of the subquery, the inner rows, are copied to every shard of the outer rows,
+// and the correlated part of the subquery runs in Lua on all the inner rows for each outer row.
+// So the inner rows should be small.
+type ApplyExec struct {
+	Outer  Executor
+	Inner  Executor
+	schema expression.Schema
+	// subquery is the Lua function returning the rows of the subquery, given the outer row and all the inner rows.
+	subquery string
+	// join is the Lua function returning the joined rows, given the outer row and the rows of the subquery.
+	join string
+}
+
+// Schema implements the Executor Schema interface.
+func (e *ApplyExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *ApplyExec) Exec() *flow.Dataset {
+	outerCount, innerCount := e.Outer.Schema().Len(), e.Inner.Schema().Len()
+	outer := e.Outer.Exec().Map(luaFunction(outerCount, []string{"1", luaParams(outerCount)}))
+	inner := e.Inner.Exec().Map(luaFunction(innerCount, []string{"1", luaParams(innerCount)}))
+	rowsOf := fmt.Sprintf(`
+        (function()
+          local subquery, join = %s, %s
+          return function(o, rows) return join(o, subquery(o, rows)) end
+        end)()
+    `, e.subquery, e.join)
+	return coGroupBroadcast(outer, inner, flow.Field(1)).
+		FlatMap(luaNestedLoop(rowsOf)).
+		Map(luaFunction(1, luaFields("_1", e.schema.Len())))
+}
+
+// luaJoinRows creates the Lua function returning the joined rows for the left row l and the right rows,
+// where the check is true. For the left outer join, the left row is padded with nil fields if no rows match.
+func luaJoinRows(leftCount, rightCount int, check string, leftOuter bool) string {
+	leftFields := luaFields("l", leftCount)
+	args := strings.Join(append(leftFields, luaFields("r", rightCount)...), ", ")
+	padding := ""
+	if leftOuter {
+		var fields []string
+		for i := 0; i < rightCount; i++ {
+			fields = append(fields, "nil")
+		}
+		padding = fmt.Sprintf("if #out == 0 then out[1] = {%s} end", strings.Join(append(leftFields, fields...), ", "))
+	}
+	return fmt.Sprintf(`
+        function(l, rows)
+          local check = %s
+          local out = {}
+          for _, r in ipairs(rows) do
+            if check(%s) then table.insert(out, {%s}) end
+          end
+          %s
+          return out
+        end
+    `, check, args, args, padding)
+}
+
+// luaSubquery translates the correlated operators, from the top down, into the Lua function
+// returning the rows of the subquery, given the outer row o and the rows from the child of the last operator.
+// The expressions of each operator are evaluated on the fields of its input row, followed by the outer fields.
+func luaSubquery(ops []plan.Plan, outer expression.Schema) (string, error) {
+	var defs, body []string
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		schema := expression.MergeSchema(op.GetChildByIndex(0).GetSchema(), outer)
+		args := strings.Join(append(luaFields("r", op.GetChildByIndex(0).GetSchema().Len()), luaFields("o", outer.Len())...), ", ")
+		name := fmt.Sprintf("op%d", len(defs)+1)
+		var def string
+		var err error
+		switch v := op.(type) {
+		case *plan.Selection:
+			def, err = luaSelection(decorrelate(v.Conditions, outer), schema, args)
+		case *plan.Projection:
+			def, err = luaProjection(decorrelate(v.Exprs, outer), schema, args)
+		case *plan.PhysicalAggregation:
+			def, err = luaAggregation(v, outer, schema, args)
+		case *plan.Sort:
+			def, err = luaSort(v, outer, schema, args)
+		case *plan.Limit:
+			def = luaLimit(v)
+		case *plan.MaxOneRow:
+			def = `
+        function(o, rows)
+          if #rows > 1 then error("Subquery returns more than 1 row") end
+          if #rows == 0 then return {{}} end
+          return rows
+        end
+    `
+		default:
+			err = fmt.Errorf("Unsupported plan %T in correlated subquery", op)
+		}
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, fmt.Sprintf("local %s = %s", name, def))
+		body = append(body, fmt.Sprintf("rows = %s(o, rows)", name))
+	}
+	return fmt.Sprintf(`
+        (function()
+          %s
+          return function(o, rows)
+            %s
+            return rows
+          end
+        end)()
+    `, strings.Join(defs, "\n"), strings.Join(body, "\n")), nil
+}
+
+// decorrelate refers to the correlated columns of the outer schema as the columns of the outer row.
+func decorrelate(exprs []expression.Expression, outer expression.Schema) (ret []expression.Expression) {
+	for _, expr := range exprs {
+		ret = append(ret, expr.Clone().Decorrelate(outer))
+	}
+	return ret
+}
+
+func luaSelection(conditions []expression.Expression, schema expression.Schema, args string) (string, error) {
+	check, err := luaFilter(conditions, schema)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`
+        function(o, rows)
+          local check = %s
+          local out = {}
+          for _, r in ipairs(rows) do
+            if check(%s) then table.insert(out, r) end
+          end
+          return out
+        end
+    `, check, args), nil
+}
+
+func luaProjection(exprs []expression.Expression, schema expression.Schema, args string) (string, error) {
+	project, err := luaMap(exprs, schema)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`
+        function(o, rows)
+          local project = %s
+          local out = {}
+          for i, r in ipairs(rows) do
+            out[i] = {project(%s)}
+          end
+          return out
+        end
+    `, project, args), nil
+}
+
+// luaAggregation computes the aggregation functions on all the rows into one row, without any group-by items.
+func luaAggregation(v *plan.PhysicalAggregation, outer, schema expression.Schema, args string) (string, error) {
+	if len(v.GroupByItems) > 0 {
+		return "", fmt.Errorf("Unsupported group by %v in correlated subquery", v.GroupByItems)
+	}
+	var exprs []expression.Expression
+	var inits, updates, outputs []string
+	for i, f := range v.AggFuncs {
+		if f.GetMode() != expression.CompleteMode || len(f.GetArgs()) != 1 {
+			return "", fmt.Errorf("Unsupported aggregation %s in correlated subquery", f)
+		}
+		exprs = append(exprs, f.GetArgs()[0])
+		x, acc, count := luaParam(i), fmt.Sprintf("acc%d", i+1), fmt.Sprintf("count%d", i+1)
+		inits = append(inits, fmt.Sprintf("local %s, %s, seen%d = nil, 0, {}", acc, count, i+1))
+		var update string
+		switch f.GetName() {
+		case ast.AggFuncCount:
+			update = fmt.Sprintf("%s = %s + 1", count, count)
+			outputs = append(outputs, count)
+		case ast.AggFuncSum:
+			update = fmt.Sprintf("%s = (%s or 0) + %s", acc, acc, x)
+			outputs = append(outputs, acc)
+		case ast.AggFuncAvg:
+			update = fmt.Sprintf("%s, %s = (%s or 0) + %s, %s + 1", acc, count, acc, x, count)
+			outputs = append(outputs, fmt.Sprintf("%s and %s / %s", acc, acc, count))
+		case ast.AggFuncMax:
+			update = fmt.Sprintf("if %s == nil or %s > %s then %s = %s end", acc, x, acc, acc, x)
+			outputs = append(outputs, acc)
+		case ast.AggFuncMin:
+			update = fmt.Sprintf("if %s == nil or %s < %s then %s = %s end", acc, x, acc, acc, x)
+			outputs = append(outputs, acc)
+		case ast.AggFuncFirstRow:
+			inits = append(inits, fmt.Sprintf("local first%d = true", i+1))
+			update = fmt.Sprintf("if first%d then %s, first%d = %s, false end", i+1, acc, i+1, x)
+			outputs = append(outputs, acc)
+		default:
+			return "", fmt.Errorf("Unsupported aggregation %s in correlated subquery", f)
+		}
+		if f.GetName() != ast.AggFuncFirstRow {
+			// the aggregation functions skip the nil values
+			update = fmt.Sprintf("if %s ~= nil then %s end", x, update)
+			if f.IsDistinct() {
+				update = fmt.Sprintf("if %s ~= nil and not seen%d[%s] then seen%d[%s] = true %s end", x, i+1, x, i+1, x, update)
+			}
+		}
+		updates = append(updates, update)
+	}
+	values, err := luaMap(decorrelate(exprs, outer), schema)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`
+        function(o, rows)
+          local values = %s
+          %s
+          for _, r in ipairs(rows) do
+            local %s = values(%s)
+            %s
+          end
+          return {{%s}}
+        end
+    `, values, strings.Join(inits, "\n"), luaParams(len(exprs)), args, strings.Join(updates, "\n"), strings.Join(outputs, ", ")), nil
+}
+
+// luaSort sorts the rows by the items, with nil values first in ascending order, and limits the rows if needed.
+func luaSort(v *plan.Sort, outer, schema expression.Schema, args string) (string, error) {
+	var exprs []expression.Expression
+	var compares []string
+	for i, item := range v.ByItems {
+		exprs = append(exprs, item.Expr)
+		x, y := fmt.Sprintf("a.keys[%d]", i+1), fmt.Sprintf("b.keys[%d]", i+1)
+		if item.Desc {
+			x, y = y, x
+		}
+		compares = append(compares, fmt.Sprintf(`
+            if %s ~= %s then
+              if %s == nil then return true end
+              if %s == nil then return false end
+              return %s < %s
+            end`, x, y, x, y, x, y))
+	}
+	keys, err := luaMap(decorrelate(exprs, outer), schema)
+	if err != nil {
+		return "", err
+	}
+	limit := ""
+	if v.ExecLimit != nil {
+		limit = fmt.Sprintf("out = (%s)(o, out)", luaLimit(v.ExecLimit))
+	}
+	return fmt.Sprintf(`
+        function(o, rows)
+          local keys = %s
+          local sorted = {}
+          for i, r in ipairs(rows) do
+            sorted[i] = {row = r, keys = {keys(%s)}}
+          end
+          table.sort(sorted, function(a, b)
+            %s
+            return false
+          end)
+          local out = {}
+          for i, s in ipairs(sorted) do
+            out[i] = s.row
+          end
+          %s
+          return out
+        end
+    `, keys, args, strings.Join(compares, "\n"), limit), nil
+}
+
+func luaLimit(v *plan.Limit) string {
+	return fmt.Sprintf(`
+        function(o, rows)
+          local out = {}
+          for i = %d + 1, math.min(#rows, %d + %d) do
+            table.insert(out, rows[i])
+          end
+          return out
+        end
+    `, v.Offset, v.Offset, v.Count)
+}
@@ -0,0 +1,367 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/expression"
+	"github.com/chrislusf/gleamold/sql/plan"
+)
+
+// BroadcastJoinMaxSize is the total size in MB, up to which one side of an inner join or a semi join
+// is broadcast to all shards of the other side, instead of partitioning both sides by the join keys.
+// The sizes come from the flow.TotalSize() or flow.PartitionSize() hints on the registered tables.
+var BroadcastJoinMaxSize int64 = 128
+
+// HashJoinExec joins the rows of both sides with the same join keys.
+// Both sides are first mapped to rows with the join keys followed by all the fields.
+type HashJoinExec struct {
+	Left     Executor
+	Right    Executor
+	schema   expression.Schema
+	joinType plan.JoinType
+	// leftInput and rightInput are the Lua functions returning the join keys followed by all the fields.
+	leftInput  string
+	rightInput string
+	keyCount   int
+	// otherFilter checks the other conditions on the joined rows, empty if there are no other conditions.
+	otherFilter string
+}
+
+// Schema implements the Executor Schema interface.
+func (e *HashJoinExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *HashJoinExec) Exec() *flow.Dataset {
+	left := e.Left.Exec().Map(e.leftInput)
+	right := e.Right.Exec().Map(e.rightInput)
+
+	// the rows with nil keys only remain on the outer side, where they match no rows
+	if e.joinType != plan.LeftOuterJoin {
+		left = left.Filter(notNullKeysFilter(e.keyCount))
+	}
+	if e.joinType != plan.RightOuterJoin {
+		right = right.Filter(notNullKeysFilter(e.keyCount))
+	}
+
+	keys := leadingFields(e.keyCount)
+	leftCount, rightCount := e.Left.Schema().Len(), e.Right.Schema().Len()
+	leftStart, rightStart := e.keyCount+1, e.keyCount+leftCount+1
+
+	var d *flow.Dataset
+	switch e.joinType {
+	case plan.LeftOuterJoin:
+		d = left.LeftOuterJoin(right, keys)
+	case plan.RightOuterJoin:
+		// the unmatched rows are padded with nils after the fields of the outer side,
+		// even if the inner side has no rows in the shard to know its width
+		d = right.LeftOuterJoin(left, keys)
+		leftStart, rightStart = e.keyCount+rightCount+1, e.keyCount+1
+	default:
+		switch {
+		case isSmall(right) && !(isSmall(left) && left.GetTotalSize() < right.GetTotalSize()):
+			d = left.HashJoin(right, keys)
+		case isSmall(left):
+			// the fields of the bigger side come first
+			d = right.HashJoin(left, keys)
+			leftStart, rightStart = e.keyCount+rightCount+1, e.keyCount+1
+		default:
+			d = left.Join(right, keys)
+		}
+	}
+
+	// drop the join keys, and keep the fields in the order of the schema
+	var fields []int
+	for i := 0; i < leftCount; i++ {
+		fields = append(fields, leftStart+i)
+	}
+	for i := 0; i < rightCount; i++ {
+		fields = append(fields, rightStart+i)
+	}
+	d = d.Select(flow.Field(fields...))
+
+	if e.otherFilter != "" {
+		d = d.Filter(e.otherFilter)
+	}
+	return d
+}
+
+// SemiJoinExec keeps the rows on the left side with or without any rows of the same join keys on the right side.
+// With the aux column, all the rows on the left side are kept, followed by whether they match.
+type SemiJoinExec struct {
+	Left   Executor
+	Right  Executor
+	schema expression.Schema
+	// leftInput is the Lua function returning the join keys followed by all the fields.
+	leftInput string
+	// rightFilter removes the rows not matching the conditions on the right side, empty if there are no such conditions.
+	rightFilter string
+	// rightInput is the Lua function returning the join keys,
+	// followed by all the fields if there are other conditions.
+	rightInput string
+	keyCount   int
+	// constantKey is set if there are no join keys, but a constant key joining all the rows.
+	constantKey bool
+	withAux     bool
+	anti        bool
+	// nullAwareKey is set if the last join key is of the IN subquery, so that the match is unknown instead of false
+	// if the key is NULL and there are right rows, or if any right row has a NULL key.
+	nullAwareKey bool
+	// otherCheck is the Lua function checking the other conditions on the fields of both sides,
+	// empty if there are no other conditions.
+	otherCheck string
+	// nullAwareCheck is the Lua function checking the null-aware conditions on the fields of both sides,
+	// which are checked after the other conditions, empty if there are no such conditions.
+	nullAwareCheck string
+}
+
+// Schema implements the Executor Schema interface.
+func (e *SemiJoinExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *SemiJoinExec) Exec() *flow.Dataset {
+	keys := leadingFields(e.keyCount)
+	left := e.Left.Exec().Map(e.leftInput)
+	right := e.Right.Exec()
+	if e.rightFilter != "" {
+		right = right.Filter(e.rightFilter)
+	}
+	right = right.Map(e.rightInput)
+	if e.otherCheck != "" {
+		// the rows with NULL keys do not match any rows, but would be grouped together
+		return e.coGroup(left, right.Filter(notNullKeysFilter(e.keyCount)), keys)
+	}
+
+	leftCount := e.Left.Schema().Len()
+	var fields []int
+	var outputs []string
+	for i := 0; i < leftCount; i++ {
+		fields = append(fields, e.keyCount+i+1)
+		outputs = append(outputs, luaParam(e.keyCount+i))
+	}
+	width := e.keyCount + leftCount
+	if e.nullAwareKey {
+		left = e.appendNullFlags(left, right)
+		width++
+	}
+	right = right.Filter(notNullKeysFilter(e.keyCount)).Distinct(keys)
+
+	if !e.withAux && !e.anti && isSmall(right) {
+		return left.HashJoin(right, keys).Select(flow.Field(fields...))
+	}
+
+	// the marker is nil for the rows without any matched rows
+	right = right.Map(luaFunction(e.keyCount, []string{luaParams(e.keyCount), "true"}))
+	d := left.LeftOuterJoin(right, keys)
+	matched := luaParam(width) + " ~= nil"
+	if e.nullAwareKey {
+		matched = fmt.Sprintf("_insubquery(%s, %s, %s)", matched, luaParam(e.keyCount-1), luaParam(width-1))
+	}
+	if e.anti {
+		matched = fmt.Sprintf("_not(%s)", matched)
+	}
+	if e.withAux {
+		return d.Map(luaFunction(width+1, append(outputs, matched)))
+	}
+	return d.Filter(luaFunction(width+1, []string{matched})).Select(flow.Field(fields...))
+}
+
+// appendNullFlags appends to each left row the flag of the right rows with the same keys except the last null-aware key:
+// nil if there are no such rows, 1 if any of them has a NULL null-aware key, or 0 otherwise.
+func (e *SemiJoinExec) appendNullFlags(left, right *flow.Dataset) *flow.Dataset {
+	n := e.keyCount - 1
+	var outputs []string
+	for i := 0; i < n; i++ {
+		outputs = append(outputs, luaParam(i))
+	}
+	if n == 0 {
+		outputs = append(outputs, "1")
+	}
+	flagKeys := leadingFields(len(outputs))
+	flags := right.Map(luaFunction(e.keyCount, append(outputs, luaParam(n)+" == nil and 1 or 0")))
+	if n > 0 {
+		return left.LeftOuterJoin(flags.Filter(notNullKeysFilter(n)).ReduceBy(`function(x, y) return math.max(x, y) end`, flagKeys), flagKeys)
+	}
+
+	// the only flag is copied to all the shards of the left rows
+	flag := left.Flow.Slices([][]interface{}{{1}}).LeftOuterJoin(flags.ReduceBy(`function(x, y) return math.max(x, y) end`, flagKeys), flagKeys)
+	leftCount := e.keyCount + e.Left.Schema().Len()
+	var fields []int
+	for i := 2; i <= leftCount+2; i++ {
+		fields = append(fields, i)
+	}
+	return left.Map(luaFunction(leftCount, []string{"1", luaParams(leftCount)})).HashJoin(flag, flow.Field(1)).Select(flow.Field(fields...))
+}
+
+// coGroup checks the other conditions on each row on the left side with all the rows of the same join keys
+// on the right side. The rows are emitted as Lua tables, which are unpacked to the fields afterwards.
+func (e *SemiJoinExec) coGroup(left, right *flow.Dataset, keys *flow.SortOption) *flow.Dataset {
+	leftCount, rightCount := e.Left.Schema().Len(), e.Right.Schema().Len()
+	var d *flow.Dataset
+	if e.constantKey || isSmall(right) {
+		d = coGroupBroadcast(left, right, keys)
+	} else {
+		d = left.CoGroup(right, keys)
+	}
+	width := leftCount
+	if e.withAux {
+		width++
+	}
+	rows := luaSemiJoinRows(leftCount, rightCount, e.otherCheck, e.nullAwareCheck, e.anti, e.withAux)
+	return d.FlatMap(luaNestedLoop(rows)).Map(luaFunction(1, luaFields("_1", width)))
+}
+
+// coGroupBroadcast co-groups the rows by the keys as CoGroup(), but copies all the right rows to every shard
+// of the left rows, instead of partitioning both sides by the keys. With a constant key, each shard of the left rows
+// is one group with all the right rows, which are checked with each left row in a nested loop,
+// so the right side should be small.
+func coGroupBroadcast(left, right *flow.Dataset, keys *flow.SortOption) *flow.Dataset {
+	right = right.Broadcast(len(left.Shards)).LocalSort(keys)
+	return left.LocalSort(keys).CoGroupPartitionedSorted(right, keys.Indexes())
+}
+
+// luaNestedLoop creates the function for FlatMap() on the co-grouped rows,
+// which emits the rows from the Lua function rowsOf(l, rights) for each left row l, as Lua tables.
+func luaNestedLoop(rowsOf string) string {
+	return fmt.Sprintf(`
+        function(keys, lefts, rights)
+          local rowsOf = %s
+          local i, rows, j = 0, {}, 0
+          return function()
+            while true do
+              j = j + 1
+              if rows[j] ~= nil then return rows[j] end
+              i = i + 1
+              local l = lefts[i]
+              if l == nil then return nil end
+              rows, j = rowsOf(l, rights), 0
+            end
+          end
+        end
+    `, rowsOf)
+}
+
+// luaSemiJoinRows creates the Lua function returning the rows of the semi join for the left row l and the right rows:
+// the left row if any right row matches, or the left row followed by whether any right row matches with the aux column.
+// The right rows matching the check are then checked by the null-aware check, if any, which may be unknown.
+// If no rows match but some are unknown, the match is unknown.
+func luaSemiJoinRows(leftCount, rightCount int, check, nullAwareCheck string, anti, withAux bool) string {
+	if nullAwareCheck == "" {
+		nullAwareCheck = "function() return true end"
+	}
+	leftFields := luaFields("l", leftCount)
+	args := strings.Join(append(leftFields, luaFields("r", rightCount)...), ", ")
+	matched := "matched"
+	if anti {
+		matched = "_not(matched)"
+	}
+	output := fmt.Sprintf("if istrue(%s) then return {{%s}} end return {}", matched, strings.Join(leftFields, ", "))
+	if withAux {
+		output = fmt.Sprintf("return {{%s, %s}}", strings.Join(leftFields, ", "), matched)
+	}
+	return fmt.Sprintf(`
+        function(l, rows)
+          local check, nullAwareCheck = %s, %s
+          local matched = false
+          for _, r in ipairs(rows) do
+            if check(%s) then
+              local m = _bool(nullAwareCheck(%s))
+              if m then
+                matched = true
+                break
+              end
+              if m == nil then matched = nil end
+            end
+          end
+          %s
+        end
+    `, check, nullAwareCheck, args, args, output)
+}
+
+// luaFields lists the first n fields of the Lua table, e.g., "r[1], r[2]".
+func luaFields(table string, n int) (fields []string) {
+	for i := 1; i <= n; i++ {
+		fields = append(fields, fmt.Sprintf("%s[%d]", table, i))
+	}
+	return fields
+}
+
+// joinKeys finds the keys on each side of the equal conditions.
+func joinKeys(conditions []*expression.ScalarFunction, left expression.Schema) (leftKeys, rightKeys []expression.Expression) {
+	for _, cond := range conditions {
+		l, r := cond.GetArgs()[0], cond.GetArgs()[1]
+		if col, ok := l.(*expression.Column); ok && left.GetColumnIndex(col) < 0 {
+			l, r = r, l
+		}
+		leftKeys = append(leftKeys, l)
+		rightKeys = append(rightKeys, r)
+	}
+	return
+}
+
+// joinInput translates the keys into a Lua function returning the keys followed by the outputs.
+// If any condition is not true, the keys are nil, so that the row does not match any row.
+// Without any keys, a constant key is used to match all rows.
+func joinInput(keys []expression.Expression, conditions []expression.Expression, schema expression.Schema, outputs ...string) (string, error) {
+	check, err := luaConditions(conditions, schema)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		keys = []expression.Expression{expression.One}
+	}
+	var fields []string
+	for _, key := range keys {
+		k, err := luaExpression(key, schema)
+		if err != nil {
+			return "", err
+		}
+		if len(conditions) > 0 {
+			k = fmt.Sprintf("(%s) and %s or nil", check, k)
+		}
+		fields = append(fields, k)
+	}
+	return luaFunction(schema.Len(), append(fields, outputs...)), nil
+}
+
+// joinKeyCount is the number of the leading key fields from joinInput().
+func joinKeyCount(keys []expression.Expression) int {
+	if len(keys) == 0 {
+		return 1
+	}
+	return len(keys)
+}
+
+func allFields(schema expression.Schema) (outputs []string) {
+	for i := 0; i < schema.Len(); i++ {
+		outputs = append(outputs, luaParam(i))
+	}
+	return outputs
+}
+
+func notNullKeysFilter(keyCount int) string {
+	var checks []string
+	for i := 0; i < keyCount; i++ {
+		checks = append(checks, luaParam(i)+" ~= nil")
+	}
+	return luaFunction(keyCount, []string{strings.Join(checks, " and ")})
+}
+
+func leadingFields(n int) *flow.SortOption {
+	var indexes []int
+	for i := 1; i <= n; i++ {
+		indexes = append(indexes, i)
+	}
+	return flow.Field(indexes...)
+}
+
+// isSmall checks whether the dataset is hinted to be small enough to broadcast.
+func isSmall(d *flow.Dataset) bool {
+	size := d.GetTotalSize()
+	return size > 0 && size <= BroadcastJoinMaxSize
+}
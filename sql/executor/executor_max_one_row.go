@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/expression"
+)
+
+// MaxOneRowExec checks that the scalar subquery returns no more than one row.
+// If the subquery returns no rows, one row of nil fields is returned instead.
+type MaxOneRowExec struct {
+	Src    Executor
+	schema expression.Schema
+}
+
+// Schema implements the Executor Schema interface.
+func (e *MaxOneRowExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *MaxOneRowExec) Exec() *flow.Dataset {
+	d := e.Src.Exec()
+	n := e.schema.Len()
+
+	// all the rows are joined to the single row by a constant key, so they end up on one shard
+	one := d.Flow.Slices([][]interface{}{{1}})
+	rows := d.Map(luaFunction(n, append([]string{"1"}, allFields(e.schema)...)))
+
+	var outputs []string
+	for i := 1; i <= n; i++ {
+		outputs = append(outputs, luaParam(i))
+	}
+	return one.LeftOuterJoin(rows, flow.Field(1)).Map(fmt.Sprintf(`
+        (function()
+          local count = 0
+          return function(%s)
+            count = count + 1
+            if count > 1 then error("Subquery returns more than 1 row") end
+            return %s
+          end
+        end)()
+    `, luaParams(n+1), strings.Join(outputs, ", ")))
+}
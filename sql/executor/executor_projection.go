@@ -1,10 +1,6 @@
 package executor
 
 import (
-	"fmt"
-	"regexp"
-	"strings"
-
 	"github.com/chrislusf/gleamold/flow"
 	"github.com/chrislusf/gleamold/sql/context"
 	"github.com/chrislusf/gleamold/sql/expression"
@@ -16,6 +12,8 @@ type ProjectionExec struct {
 	executed bool
 	ctx      context.Context
 	exprs    []expression.Expression
+	// mapper is the Lua function computing the expressions, empty if the rows are not changed.
+	mapper string
 }
 
 // Schema implements the Executor Schema interface.
@@ -27,49 +25,23 @@ func (e *ProjectionExec) Schema() expression.Schema {
 func (e *ProjectionExec) Exec() *flow.Dataset {
 	d := e.Src.Exec()
 
-	// fmt.Printf("=================\n")
-	// fmt.Printf("context: %+v\n", e.ctx)
-
-	var inputs []string
-	for _, col := range e.Src.Schema().Columns {
-		// fmt.Printf("input: %s TblName:%s DBName:%s FromID:%s %d\n", col, col.TblName, col.DBName, col.FromID, col.Position)
-		inputs = append(inputs, col.ColName.String())
-	}
-
-	/*
-		for _, col := range e.Schema().Columns {
-			fmt.Printf("output:%s TblName:%s DBName:%s FromID:%s %d\n", col, col.TblName, col.DBName, col.FromID, col.Position)
-		}
-	*/
-
-	var outputs []string
-	for _, expr := range e.exprs {
-		// fmt.Printf("expression: %s %+v\n", expr.String(), reflect.TypeOf(expr))
-		outputs = append(outputs, expr.String())
-	}
-
-	inputParams := removeTableName(strings.Join(inputs, ","))
-	outputParams := removeTableName(strings.Join(outputs, ","))
-
-	if inputParams == outputParams {
+	if e.mapper == "" {
 		return d
 	}
 
-	sqlText := fmt.Sprintf(`
-        function(%s)
-          return %s
-        end
-    `, inputParams, outputParams)
-
-	// println(sqlText)
-
-	ret := d.Map(sqlText)
-
-	return ret
+	return d.Map(e.mapper)
 }
 
-var re = regexp.MustCompile(`([a-z]+\w*\.)(\w+)`)
-
-func removeTableName(sqlText string) string {
-	return re.ReplaceAllString(sqlText, "$2")
+// isSameColumns checks whether the expressions are just all the columns of the schema in order.
+func isSameColumns(exprs []expression.Expression, schema expression.Schema) bool {
+	if len(exprs) != schema.Len() {
+		return false
+	}
+	for i, expr := range exprs {
+		col, ok := expr.(*expression.Column)
+		if !ok || schema.GetColumnIndex(col) != i {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/expression"
+)
+
+// SelectionExec keeps the rows matching the conditions of the WHERE or HAVING clause.
+type SelectionExec struct {
+	Src    Executor
+	schema expression.Schema
+	// filter is the Lua function translated from the conditions.
+	filter string
+}
+
+// Schema implements the Executor Schema interface.
+func (e *SelectionExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *SelectionExec) Exec() *flow.Dataset {
+	d := e.Src.Exec()
+
+	return d.Filter(e.filter)
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/expression"
+)
+
+// SortExec sorts all the rows by the order-by items into one shard.
+// With a limit, only the first offset+count rows are kept while sorting.
+type SortExec struct {
+	Src    Executor
+	schema expression.Schema
+	// input is the Lua function appending the order-by items as fields, empty if they are all columns.
+	input string
+	// keys are the fields of the order-by items, starting from 1.
+	keys     []int
+	desc     []bool
+	hasLimit bool
+	offset   uint64
+	count    uint64
+}
+
+// Schema implements the Executor Schema interface.
+func (e *SortExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *SortExec) Exec() *flow.Dataset {
+	d := e.Src.Exec()
+
+	if e.input != "" {
+		d = d.Map(e.input)
+	}
+
+	if e.hasLimit {
+		// Top picks the last rows in the order, so the orders are reversed to pick the first rows
+		d = d.Top(int(e.offset+e.count), e.sortOption(true))
+		if e.offset > 0 {
+			d = d.LocalLimit(int(e.count), int(e.offset))
+		}
+	} else {
+		d = d.Sort(e.sortOption(false))
+	}
+
+	if e.input != "" {
+		// drop the appended order-by fields
+		var fields []int
+		for i := 1; i <= e.Src.Schema().Len(); i++ {
+			fields = append(fields, i)
+		}
+		d = d.Select(flow.Field(fields...))
+	}
+	return d
+}
+
+func (e *SortExec) sortOption(reverse bool) *flow.SortOption {
+	var sortOption *flow.SortOption
+	for i, key := range e.keys {
+		ascending := e.desc[i] == reverse
+		if sortOption == nil {
+			sortOption = flow.OrderBy(key, ascending)
+		} else {
+			sortOption = sortOption.By(key, ascending)
+		}
+	}
+	return sortOption
+}
@@ -0,0 +1,22 @@
+package executor
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/expression"
+)
+
+// TableDualExec produces one row without any fields, for the selects without any tables.
+type TableDualExec struct {
+	flow   *flow.Flow
+	schema expression.Schema
+}
+
+// Schema implements the Executor Schema interface.
+func (e *TableDualExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *TableDualExec) Exec() *flow.Dataset {
+	return e.flow.Slices([][]interface{}{{}})
+}
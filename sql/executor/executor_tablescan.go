@@ -54,5 +54,23 @@ func (e *SelectTableExec) Exec() *flow.Dataset {
 
 	d := t.Dataset
 
+	// the unused columns are pruned from the schema, so only select the used fields
+	var indexes []int
+	reordered := false
+	for i, col := range e.Columns {
+		for j, c := range t.TableInfo.Columns {
+			if c.Name.L == col.Name.L {
+				indexes = append(indexes, j+1)
+				if i != j {
+					reordered = true
+				}
+				break
+			}
+		}
+	}
+	if len(indexes) > 0 && (reordered || len(indexes) < len(t.TableInfo.Columns)) {
+		d = d.Select(flow.Field(indexes...))
+	}
+
 	return d
 }
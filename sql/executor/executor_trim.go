@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/expression"
+)
+
+// TrimExec drops the extra columns added for the order-by items, which are not selected.
+type TrimExec struct {
+	Src    Executor
+	schema expression.Schema
+}
+
+// Schema implements the Executor Schema interface.
+func (e *TrimExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *TrimExec) Exec() *flow.Dataset {
+	var fields []int
+	for i := 1; i <= e.schema.Len(); i++ {
+		fields = append(fields, i)
+	}
+	return e.Src.Exec().Select(flow.Field(fields...))
+}
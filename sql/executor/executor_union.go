@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql/expression"
+)
+
+// UnionExec merges the rows of all the selects. The duplicated rows are removed by the plans above.
+type UnionExec struct {
+	Srcs   []Executor
+	schema expression.Schema
+}
+
+// Schema implements the Executor Schema interface.
+func (e *UnionExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Next implements the Executor Next interface.
+func (e *UnionExec) Exec() *flow.Dataset {
+	var ds []*flow.Dataset
+	for _, src := range e.Srcs {
+		ds = append(ds, src.Exec())
+	}
+	return ds[0].Union(ds[1:]...)
+}
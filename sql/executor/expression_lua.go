@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/chrislusf/gleamold/sql/ast"
+	"github.com/chrislusf/gleamold/sql/expression"
+	"github.com/chrislusf/gleamold/sql/util/types"
+)
+
+// luaParams names the first n row fields by their positions, e.g., "_1,_2,_3".
+func luaParams(n int) string {
+	var params []string
+	for i := 0; i < n; i++ {
+		params = append(params, luaParam(i))
+	}
+	return strings.Join(params, ",")
+}
+
+func luaParam(index int) string {
+	return fmt.Sprintf("_%d", index+1)
+}
+
+// luaExpression translates the expression into Lua code calling the functions defined in Functions.
+// The columns are referred to by their positions in the schema, named as in luaParams().
+func luaExpression(expr expression.Expression, schema expression.Schema) (string, error) {
+	switch x := expr.(type) {
+	case *expression.Column:
+		index := schema.GetColumnIndex(x)
+		if index < 0 {
+			return "", fmt.Errorf("Can not find column %s in %s", x, schema)
+		}
+		return luaParam(index), nil
+	case *expression.Constant:
+		return luaConstant(x.Value), nil
+	case *expression.ScalarFunction:
+		var args []string
+		for _, arg := range x.GetArgs() {
+			a, err := luaExpression(arg, schema)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, a)
+		}
+		if x.FuncName.L == ast.Cast {
+			args = append(args, luaString(types.TypeToStr(x.GetType().Tp, x.GetType().Charset)))
+		}
+		return fmt.Sprintf("%s(%s)", x.FuncName.L, strings.Join(args, ", ")), nil
+	}
+	return "", fmt.Errorf("Unsupported expression %s of %T", expr, expr)
+}
+
+// luaFilter translates the conditions into a function for Dataset.Filter(),
+// keeping the rows where all the conditions are true.
+func luaFilter(conditions []expression.Expression, schema expression.Schema) (string, error) {
+	check, err := luaConditions(conditions, schema)
+	if err != nil {
+		return "", err
+	}
+	return luaFunction(schema.Len(), []string{check}), nil
+}
+
+// luaConditions translates the conditions into a Lua boolean expression, true if there are no conditions.
+func luaConditions(conditions []expression.Expression, schema expression.Schema) (string, error) {
+	var checks []string
+	for _, cond := range conditions {
+		c, err := luaExpression(cond, schema)
+		if err != nil {
+			return "", err
+		}
+		checks = append(checks, fmt.Sprintf("istrue(%s)", c))
+	}
+	if len(checks) == 0 {
+		return "true", nil
+	}
+	return strings.Join(checks, " and "), nil
+}
+
+// luaNullAwareCheck translates the conditions into a function returning whether they are all true,
+// or nil if some conditions are unknown but none is false.
+func luaNullAwareCheck(conditions []expression.Expression, schema expression.Schema) (string, error) {
+	var check string
+	for _, cond := range conditions {
+		c, err := luaExpression(cond, schema)
+		if err != nil {
+			return "", err
+		}
+		if check == "" {
+			check = c
+		} else {
+			check = fmt.Sprintf("_and(%s, %s)", check, c)
+		}
+	}
+	return luaFunction(schema.Len(), []string{check}), nil
+}
+
+// luaMap translates the expressions into a function for Dataset.Map(),
+// returning one field for each expression.
+func luaMap(exprs []expression.Expression, schema expression.Schema) (string, error) {
+	var outputs []string
+	for _, expr := range exprs {
+		o, err := luaExpression(expr, schema)
+		if err != nil {
+			return "", err
+		}
+		outputs = append(outputs, o)
+	}
+	return luaFunction(schema.Len(), outputs), nil
+}
+
+// luaFunction creates a function of n parameters returning the outputs.
+func luaFunction(n int, outputs []string) string {
+	return fmt.Sprintf(`
+        function(%s)
+          return %s
+        end
+    `, luaParams(n), strings.Join(outputs, ", "))
+}
+
+func luaConstant(d types.Datum) string {
+	switch d.Kind() {
+	case types.KindNull:
+		return "nil"
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64:
+		return fmt.Sprintf("%v", d.GetValue())
+	case types.KindMysqlDecimal:
+		return d.GetMysqlDecimal().String()
+	}
+	s, err := d.ToString()
+	if err != nil {
+		s = fmt.Sprintf("%v", d.GetValue())
+	}
+	return luaString(s)
+}
+
+// luaString quotes the string as a Lua string literal.
+func luaString(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < ' ' || c == 127:
+			fmt.Fprintf(&buf, "\\%03d", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
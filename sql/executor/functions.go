@@ -8,7 +8,17 @@ function _startWith(s,prefix)
 end
 local bit = require("bit")
 
-function _and(x, y) return x and y end
+-- _bool converts a SQL value to a Lua boolean, where 0 is false, and NULL stays nil.
+function _bool(x)
+  if x == nil then return nil end
+  return x ~= false and x ~= 0
+end
+function _and(x, y)
+  x, y = _bool(x), _bool(y)
+  if x == false or y == false then return false end
+  if x == nil or y == nil then return nil end
+  return true
+end
 function cast(x, typ)
   if typ == 'decimal'
   or typ == 'double'
@@ -38,13 +48,18 @@ function cast(x, typ)
 end
 function leftshift(x, n) return bit.lshift(x, n) end
 function rightshift(x, n) return bit.rshift(x, n) end
-function _or(x, y) return x or y end
-function ge(x, y) return x >= y end
-function le(x, y) return x <= y end
-function eq(x, y) return x == y end
-function ne(x, y) return x ~= y end
-function lt(x, y) return x < y end
-function gt(x, y) return x > y end
+function _or(x, y)
+  x, y = _bool(x), _bool(y)
+  if x or y then return true end
+  if x == nil or y == nil then return nil end
+  return false
+end
+function ge(x, y) if x == nil or y == nil then return nil end return x >= y end
+function le(x, y) if x == nil or y == nil then return nil end return x <= y end
+function eq(x, y) if x == nil or y == nil then return nil end return x == y end
+function ne(x, y) if x == nil or y == nil then return nil end return x ~= y end
+function lt(x, y) if x == nil or y == nil then return nil end return x < y end
+function gt(x, y) if x == nil or y == nil then return nil end return x > y end
 function plus(x, y)  return x + y end
 function minus(x, y) return x - y end
 function bitand(x, y) return bit.band(x, y) end
@@ -53,7 +68,7 @@ function mod(x, y) return math.mod(x, y)  end
 function bitxor(x, y) return bit.bitxor(x, y)  end
 function div(x, y) return x / y end
 function mul(x, y) return x * y end
-function _not(x) return not x end
+function _not(x) x = _bool(x) if x == nil then return nil end return not x end
 function bitneg(x)  return bit.bnot(x)  end
 function intdiv(x, y)  return math.floor(x/y)  end
 function xor(x, y) if x == y then return false else return true end end
@@ -72,8 +87,54 @@ function _in(...)
   end
   return false
 end
+-- _insubquery is whether the key is in the subquery, given whether it matches any row, and the flag of the rows:
+-- nil if there are no rows, 1 if any row has a NULL key, or 0 otherwise.
+function _insubquery(matched, key, flag)
+  if matched then return true end
+  if flag == nil then return false end
+  if key == nil or flag == 1 then return nil end
+  return false
+end
 function isnull(x) return x == nil end
+function ifnull(x, y) if x == nil then return y end return x end
+function istrue(x) return x ~= nil and x ~= false and x ~= 0 end
+function isfalse(x) return x == false or x == 0 end
+function like(s, pattern, escape)
+  if s == nil or pattern == nil then return nil end
+  s, pattern = tostring(s), tostring(pattern)
+  local esc = string.char(escape or 92)
+  local p, i, n = "^", 1, string.len(pattern)
+  while i <= n do
+    local c = string.sub(pattern, i, i)
+    if c == esc and i < n then
+      i = i + 1
+      c = string.sub(pattern, i, i)
+      if string.find(c, "^%W") then c = "%" .. c end
+    elseif c == "%" then
+      c = ".*"
+    elseif c == "_" then
+      c = "."
+    elseif string.find(c, "^%W") then
+      c = "%" .. c
+    end
+    p = p .. c
+    i = i + 1
+  end
+  return string.find(s, p .. "$") ~= nil
+end
 
-function concat(x,y) return x..y end
+function concat(...)
+  local s = ""
+  for i=1, select('#', ...) do
+    local v = select(i, ...)
+    if v == nil then return nil end
+    s = s .. tostring(v)
+  end
+  return s
+end
+function _avg(sum, count)
+  if sum == nil or count == 0 then return nil end
+  return sum / count
+end
 	`
 )
@@ -38,7 +38,8 @@ func tableInfoList() (infos []*model.TableInfo) {
 	return infos
 }
 
-func Query(sql string) (*flow.Dataset, plan.Plan, error) {
+// Query adds the steps of the SQL query to the flow, which has the datasets of the registered tables.
+func Query(f *flow.Flow, sql string) (*flow.Dataset, plan.Plan, error) {
 	p := parser.New()
 	tree, err := p.ParseOneStmt(sql, "", "")
 	if err != nil {
@@ -59,6 +60,7 @@ func Query(sql string) (*flow.Dataset, plan.Plan, error) {
 
 	sa := &executor.Statement{
 		InfoSchema: infoSchema,
+		Flow:       f,
 		Plan:       physicalPlan,
 		Text:       tree.Text(),
 	}
@@ -14,7 +14,9 @@
 package plan
 
 import (
+	"github.com/chrislusf/gleamold/sql/ast"
 	"github.com/chrislusf/gleamold/sql/expression"
+	"github.com/chrislusf/gleamold/sql/mysql"
 	"github.com/chrislusf/gleamold/sql/util/types"
 )
 
@@ -72,14 +74,183 @@ func decorrelate(p LogicalPlan) LogicalPlan {
 			apply.SetChildren(outerPlan, innerPlan)
 			innerPlan.SetParents(apply)
 			return decorrelate(p)
+		} else if m, ok := innerPlan.(*MaxOneRow); ok && isScalarAggregation(m.children[0].(LogicalPlan)) {
+			// The scalar aggregation always returns exactly one row.
+			innerPlan = m.children[0].(LogicalPlan)
+			apply.SetChildren(outerPlan, innerPlan)
+			innerPlan.SetParents(apply)
+			return decorrelate(p)
+		} else if proj, ok := innerPlan.(*Projection); ok && apply.JoinType != LeftOuterJoin && apply.JoinType != RightOuterJoin {
+			// If the inner plan is a projection, we pull it up above the apply.
+			for i, expr := range proj.Exprs {
+				proj.Exprs[i] = expr.Decorrelate(outerPlan.GetSchema())
+			}
+			innerPlan = proj.children[0].(LogicalPlan)
+			apply.SetChildren(outerPlan, innerPlan)
+			innerPlan.SetParents(apply)
+			if apply.JoinType != InnerJoin {
+				// The semi join only outputs the outer columns, so the projected columns only need to be
+				// substituted in the join conditions.
+				conds := concatOnAndWhereConds(&apply.Join, nil)
+				for i, cond := range conds {
+					conds[i] = expression.ColumnSubstitute(cond, proj.GetSchema(), proj.Exprs)
+				}
+				for i, cond := range apply.nullAware {
+					apply.nullAware[i] = expression.ColumnSubstitute(cond, proj.GetSchema(), proj.Exprs)
+				}
+				apply.EqualConditions, apply.LeftConditions, apply.RightConditions, apply.OtherConditions = nil, nil, nil, nil
+				apply.attachOnConds(conds)
+				return decorrelate(p)
+			}
+			proj.Exprs = append(expression.Column2Exprs(outerPlan.GetSchema().Columns), proj.Exprs...)
+			proj.SetSchema(apply.GetSchema())
+			apply.SetSchema(expression.MergeSchema(outerPlan.GetSchema(), innerPlan.GetSchema()))
+			np := decorrelate(p)
+			proj.SetChildren(np)
+			np.SetParents(proj)
+			return proj
+		} else if agg, ok := innerPlan.(*Aggregation); ok && apply.decorrelateAggregation(agg) {
+			return zeroNullCounts(decorrelate(p), agg)
 		}
-		// TODO: Deal with aggregation and projection.
 	}
 	newChildren := make([]Plan, 0, len(p.GetChildren()))
 	for _, child := range p.GetChildren() {
-		newChildren = append(newChildren, decorrelate(child.(LogicalPlan)))
-		child.SetParents(p)
+		newChild := decorrelate(child.(LogicalPlan))
+		newChild.SetParents(p)
+		newChildren = append(newChildren, newChild)
 	}
 	p.SetChildren(newChildren...)
 	return p
 }
+
+// isScalarAggregation checks whether the plan is an aggregation without group-by items, beneath any projections.
+func isScalarAggregation(p LogicalPlan) bool {
+	for {
+		switch x := p.(type) {
+		case *Projection:
+			p = x.children[0].(LogicalPlan)
+		case *Aggregation:
+			return len(x.GroupByItems) == 0
+		default:
+			return false
+		}
+	}
+}
+
+// decorrelateAggregation decorrelates the apply whose inner plan is a scalar aggregation over a selection,
+// where the correlated conditions are all like "inner column = correlated column".
+// The aggregation is grouped by these inner columns instead, and joined to the outer plan by them.
+// e.g. "select a, (select count(*) from t2 where t2.b = t1.b) from t1" is converted to
+// "select a, ifnull(cnt, 0) from t1 left join (select count(*) cnt, b from t2 group by b) t2 on t2.b = t1.b".
+// It returns false and keeps the plans unchanged if the apply can not be decorrelated in this way.
+func (a *Apply) decorrelateAggregation(agg *Aggregation) bool {
+	if len(agg.GroupByItems) > 0 {
+		return false
+	}
+	sel, ok := agg.children[0].(*Selection)
+	if !ok {
+		return false
+	}
+	hasCount := false
+	for _, f := range agg.AggFuncs {
+		if f.GetName() == ast.AggFuncCount {
+			hasCount = true
+		}
+		for _, arg := range f.GetArgs() {
+			if len(extractCorColumns(arg)) > 0 {
+				return false
+			}
+		}
+	}
+	switch a.JoinType {
+	case InnerJoin:
+	case SemiJoin, LeftOuterSemiJoin:
+		// The missing groups would not be counted as 0 in the semi join.
+		if hasCount {
+			return false
+		}
+	default:
+		return false
+	}
+	outerSchema := a.children[0].GetSchema()
+	var eqConds, rest []expression.Expression
+	var groupByItems []expression.Expression
+	for _, cond := range sel.Conditions {
+		if len(extractCorColumns(cond)) == 0 {
+			rest = append(rest, cond)
+			continue
+		}
+		f, ok := cond.(*expression.ScalarFunction)
+		if !ok || f.FuncName.L != ast.EQ {
+			return false
+		}
+		corCol, ok1 := f.GetArgs()[0].(*expression.CorrelatedColumn)
+		innerCol, ok2 := f.GetArgs()[1].(*expression.Column)
+		if !ok1 || !ok2 {
+			corCol, ok1 = f.GetArgs()[1].(*expression.CorrelatedColumn)
+			innerCol, ok2 = f.GetArgs()[0].(*expression.Column)
+		}
+		if !ok1 || !ok2 || outerSchema.GetColumnIndex(&corCol.Column) == -1 {
+			return false
+		}
+		// The aggregation keeps the first row of each column of its child, with the same column.
+		idx := agg.schema.GetColumnIndex(innerCol)
+		if idx == -1 {
+			return false
+		}
+		eq, err := expression.NewFunction(a.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), corCol.Column.Clone(), agg.schema.Columns[idx].Clone())
+		if err != nil {
+			return false
+		}
+		eqConds = append(eqConds, eq)
+		groupByItems = append(groupByItems, innerCol.Clone())
+	}
+	if len(eqConds) == 0 {
+		return false
+	}
+
+	agg.GroupByItems = groupByItems
+	agg.collectGroupByColumns()
+	if len(rest) > 0 {
+		sel.Conditions = rest
+	} else {
+		child := sel.children[0]
+		agg.SetChildren(child)
+		child.SetParents(agg)
+	}
+	if a.JoinType == InnerJoin {
+		// The outer rows without any group are kept, with null aggregation results.
+		a.JoinType = LeftOuterJoin
+	}
+	a.attachOnConds(eqConds)
+	return true
+}
+
+// zeroNullCounts projects the null counts of the aggregation joined by decorrelateAggregation() to 0.
+func zeroNullCounts(p LogicalPlan, agg *Aggregation) LogicalPlan {
+	counts := make([]*expression.Column, 0, len(agg.AggFuncs))
+	for i, f := range agg.AggFuncs {
+		if f.GetName() == ast.AggFuncCount {
+			counts = append(counts, agg.schema.Columns[i])
+		}
+	}
+	if len(counts) == 0 {
+		return p
+	}
+	proj := &Projection{
+		Exprs:           make([]expression.Expression, 0, p.GetSchema().Len()),
+		baseLogicalPlan: newBaseLogicalPlan(Proj, agg.allocator)}
+	proj.self = proj
+	proj.initIDAndContext(agg.ctx)
+	for _, col := range p.GetSchema().Columns {
+		var expr expression.Expression = col.Clone()
+		if expression.NewSchema(counts).GetColumnIndex(col) != -1 {
+			expr, _ = expression.NewFunction(agg.ctx, ast.Ifnull, col.GetType(), col.Clone(), expression.Zero)
+		}
+		proj.Exprs = append(proj.Exprs, expr)
+	}
+	proj.SetSchema(p.GetSchema().Clone())
+	proj.SetChildren(p)
+	p.SetParents(proj)
+	return proj
+}
@@ -28,9 +28,6 @@ import (
 	"github.com/chrislusf/gleamold/sql/util/types"
 )
 
-// EvalSubquery evaluates incorrelated subqueries once.
-var EvalSubquery func(p PhysicalPlan, is infoschema.InfoSchema, ctx context.Context) ([]types.Datum, error)
-
 // evalAstExpr evaluates ast expression directly.
 func evalAstExpr(expr ast.ExprNode, ctx context.Context) (types.Datum, error) {
 	if val, ok := expr.(*ast.ValueExpr); ok {
@@ -444,24 +441,14 @@ func (er *expressionRewriter) handleExistSubquery(v *ast.ExistsSubqueryExpr) (as
 	if er.err != nil {
 		return v, true
 	}
+	// The subquery can not be evaluated while planning, because the datasets are only computed when the flow runs.
+	// Without correlated columns, the apply is decorrelated into a semi join.
 	np = er.b.buildExists(np)
-	if np.IsCorrelated() {
-		er.p = er.b.buildSemiApply(er.p, np.GetChildren()[0].(LogicalPlan), nil, er.asScalar, false)
-		if !er.asScalar {
-			return v, true
-		}
-		er.ctxStack = append(er.ctxStack, er.p.GetSchema().Columns[er.p.GetSchema().Len()-1])
-	} else {
-		physicalPlan, err := doOptimize(np, er.b.ctx, er.b.allocator)
-		d, err := EvalSubquery(physicalPlan, er.b.is, er.b.ctx)
-		if err != nil {
-			er.err = errors.Trace(err)
-			return v, true
-		}
-		er.ctxStack = append(er.ctxStack, &expression.Constant{
-			Value:   d[0],
-			RetType: types.NewFieldType(mysql.TypeTiny)})
+	er.p = er.b.buildSemiApply(er.p, np.GetChildren()[0].(LogicalPlan), nil, er.asScalar, false)
+	if !er.asScalar {
+		return v, true
 	}
+	er.ctxStack = append(er.ctxStack, er.p.GetSchema().Columns[er.p.GetSchema().Len()-1])
 	return v, true
 }
 
@@ -523,53 +510,23 @@ func (er *expressionRewriter) handleScalarSubquery(v *ast.SubqueryExpr) (ast.Nod
 	if er.err != nil {
 		return v, true
 	}
+	// The subquery can not be evaluated while planning, because the datasets are only computed when the flow runs.
+	// Without correlated columns, the apply is decorrelated into a join.
 	np = er.b.buildMaxOneRow(np)
-	if np.IsCorrelated() {
-		er.p = er.b.buildInnerApply(er.p, np)
-		if np.GetSchema().Len() > 1 {
-			newCols := make([]expression.Expression, 0, np.GetSchema().Len())
-			for _, col := range np.GetSchema().Columns {
-				newCols = append(newCols, col.Clone())
-			}
-			expr, err := expression.NewFunction(er.ctx, ast.RowFunc, nil, newCols...)
-			if err != nil {
-				er.err = errors.Trace(err)
-				return v, true
-			}
-			er.ctxStack = append(er.ctxStack, expr)
-		} else {
-			er.ctxStack = append(er.ctxStack, er.p.GetSchema().Columns[er.p.GetSchema().Len()-1])
-		}
-		return v, true
-	}
-	physicalPlan, err := doOptimize(np, er.b.ctx, er.b.allocator)
-	if err != nil {
-		er.err = errors.Trace(err)
-		return v, true
-	}
-	d, err := EvalSubquery(physicalPlan, er.b.is, er.b.ctx)
-	if err != nil {
-		er.err = errors.Trace(err)
-		return v, true
-	}
+	er.p = er.b.buildInnerApply(er.p, np)
 	if np.GetSchema().Len() > 1 {
 		newCols := make([]expression.Expression, 0, np.GetSchema().Len())
-		for i, data := range d {
-			newCols = append(newCols, &expression.Constant{
-				Value:   data,
-				RetType: np.GetSchema().Columns[i].GetType()})
+		for _, col := range np.GetSchema().Columns {
+			newCols = append(newCols, col.Clone())
 		}
-		expr, err1 := expression.NewFunction(er.ctx, ast.RowFunc, nil, newCols...)
-		if err1 != nil {
-			er.err = errors.Trace(err1)
+		expr, err := expression.NewFunction(er.ctx, ast.RowFunc, nil, newCols...)
+		if err != nil {
+			er.err = errors.Trace(err)
 			return v, true
 		}
 		er.ctxStack = append(er.ctxStack, expr)
 	} else {
-		er.ctxStack = append(er.ctxStack, &expression.Constant{
-			Value:   d[0],
-			RetType: np.GetSchema().Columns[0].GetType(),
-		})
+		er.ctxStack = append(er.ctxStack, er.p.GetSchema().Columns[er.p.GetSchema().Len()-1])
 	}
 	return v, true
 }
@@ -731,7 +688,7 @@ func (er *expressionRewriter) binaryOpToExpression(v *ast.BinaryOperationExpr) {
 		if er.err != nil {
 			return
 		}
-		function, er.err = expression.NewFunction(er.ctx, v.Op.String(), &v.Type, er.ctxStack[stkLen-2:]...)
+		function, er.err = expression.NewFunction(er.ctx, binaryOpFuncName(v.Op), &v.Type, er.ctxStack[stkLen-2:]...)
 	}
 	if er.err != nil {
 		er.err = errors.Trace(er.err)
@@ -741,6 +698,18 @@ func (er *expressionRewriter) binaryOpToExpression(v *ast.BinaryOperationExpr) {
 	er.ctxStack = append(er.ctxStack, function)
 }
 
+// binaryOpFuncName returns the function name of the binary operator.
+// The logical operators are renamed to avoid conflicts with the Lua keywords.
+func binaryOpFuncName(op opcode.Op) string {
+	switch op {
+	case opcode.AndAnd:
+		return ast.AndAnd
+	case opcode.OrOr:
+		return ast.OrOr
+	}
+	return op.String()
+}
+
 func (er *expressionRewriter) notToExpression(hasNot bool, op string, tp *types.FieldType,
 	args ...expression.Expression) expression.Expression {
 	opFunc, err := expression.NewFunction(er.ctx, op, tp, args...)
@@ -1047,6 +1047,7 @@ func (b *planBuilder) buildSemiJoin(outerPlan, innerPlan LogicalPlan, onConditio
 		joinPlan.JoinType = SemiJoin
 	}
 	joinPlan.anti = not
+	joinPlan.nullAware = onCondition
 	joinPlan.SetCorrelated()
 	return joinPlan
 }
@@ -45,6 +45,9 @@ type Join struct {
 	anti          bool
 	reordered     bool
 	cartesianJoin bool
+	// nullAware are the conditions of the IN, ANY or ALL subquery of the semi join, which are unknown
+	// instead of false for null values, unlike the conditions in the WHERE clause of the subquery.
+	nullAware []expression.Expression
 
 	EqualConditions []*expression.ScalarFunction
 	LeftConditions  []expression.Expression
@@ -64,6 +67,32 @@ func (p *Join) attachOnConds(onConds []expression.Expression) {
 	p.OtherConditions = append(other, p.OtherConditions...)
 }
 
+// nullAwareConditions finds the join conditions among the null-aware conditions,
+// which may have been rebuilt since, e.g., as equal conditions with the arguments swapped.
+func (p *Join) nullAwareConditions() (conds []expression.Expression) {
+	for _, cond := range concatOnAndWhereConds(p, nil) {
+		for _, c := range p.nullAware {
+			if sameCondition(cond, c) {
+				conds = append(conds, cond)
+				break
+			}
+		}
+	}
+	return conds
+}
+
+func sameCondition(a, b expression.Expression) bool {
+	if a.String() == b.String() {
+		return true
+	}
+	x, ok1 := a.(*expression.ScalarFunction)
+	y, ok2 := b.(*expression.ScalarFunction)
+	if !ok1 || !ok2 || x.FuncName.L != ast.EQ || y.FuncName.L != ast.EQ {
+		return false
+	}
+	return x.GetArgs()[0].String() == y.GetArgs()[1].String() && x.GetArgs()[1].String() == y.GetArgs()[0].String()
+}
+
 func (p *Join) extractCorrelatedCols() []*expression.CorrelatedColumn {
 	corCols := p.basePlan.extractCorrelatedCols()
 	for _, fun := range p.EqualConditions {
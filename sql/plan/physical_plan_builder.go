@@ -53,6 +53,9 @@ func (p *DataSource) convert2TableScan(prop *requiredProperty) (*physicalPlanInf
 
 	var resultPlan PhysicalPlan
 	resultPlan = ts
+	if sel, ok := p.GetParentByIndex(0).(*Selection); ok {
+		resultPlan = newSelectionOnTable(sel, ts)
+	}
 	return resultPlan.matchProperty(prop, &physicalPlanInfo{count: 0}), nil
 }
 
@@ -73,9 +76,21 @@ func (p *DataSource) convert2IndexScan(prop *requiredProperty, index *model.Inde
 
 	var resultPlan PhysicalPlan
 	resultPlan = is
+	if sel, ok := p.GetParentByIndex(0).(*Selection); ok {
+		resultPlan = newSelectionOnTable(sel, is)
+	}
 	return resultPlan.matchProperty(prop, &physicalPlanInfo{count: 0}), nil
 }
 
+// newSelectionOnTable keeps the conditions of the selection above the scan.
+// The scans do not filter the rows themselves, so the conditions are evaluated by the selection.
+func newSelectionOnTable(sel *Selection, scan PhysicalPlan) PhysicalPlan {
+	newSel := *sel
+	newSel.SetChildren(scan)
+	newSel.onTable = true
+	return &newSel
+}
+
 func isCoveringIndex(columns []*model.ColumnInfo, indexColumns []*model.IndexColumn, pkIsHandle bool) bool {
 	for _, colInfo := range columns {
 		if pkIsHandle && mysql.HasPriKeyFlag(colInfo.Flag) {
@@ -280,6 +295,7 @@ func (p *Join) convert2PhysicalPlanSemi(prop *requiredProperty) (*physicalPlanIn
 		RightConditions: p.RightConditions,
 		OtherConditions: p.OtherConditions,
 		Anti:            p.anti,
+		NullAware:       p.nullAwareConditions(),
 	}
 	join.ctx = p.ctx
 	join.tp = "HashSemiJoin"
@@ -858,6 +874,43 @@ func (p *Sort) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlanInfo,
 	return sortedPlanInfo, nil
 }
 
+// convert2PhysicalPlan implements the LogicalPlan convert2PhysicalPlan interface.
+func (p *MaxOneRow) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlanInfo, error) {
+	info, err := p.getPlanInfo(prop)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if info != nil {
+		return info, nil
+	}
+	info, err = p.GetChildByIndex(0).(LogicalPlan).convert2PhysicalPlan(&requiredProperty{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info = addPlanToResponse(p, info)
+	info = enforceProperty(prop, info)
+	p.storePlanInfo(prop, info)
+	return info, nil
+}
+
+// convert2PhysicalPlan implements the LogicalPlan convert2PhysicalPlan interface.
+func (p *Trim) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlanInfo, error) {
+	info, err := p.getPlanInfo(prop)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if info != nil {
+		return info, nil
+	}
+	info, err = p.GetChildByIndex(0).(LogicalPlan).convert2PhysicalPlan(prop)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info = addPlanToResponse(p, info)
+	p.storePlanInfo(prop, info)
+	return info, nil
+}
+
 // convert2PhysicalPlan implements the LogicalPlan convert2PhysicalPlan interface.
 func (p *Apply) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlanInfo, error) {
 	info, err := p.getPlanInfo(prop)
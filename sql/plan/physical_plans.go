@@ -374,6 +374,10 @@ type PhysicalHashSemiJoin struct {
 	LeftConditions  []expression.Expression
 	RightConditions []expression.Expression
 	OtherConditions []expression.Expression
+
+	// NullAware are the conditions above of the IN, ANY or ALL subquery,
+	// which make the semi join unknown instead of false if they are unknown for some rows but true for none.
+	NullAware []expression.Expression
 }
 
 // AggregationType stands for the mode of aggregation plan.
@@ -289,6 +289,11 @@ func (p *basePlan) extractCorrelatedCols() []*expression.CorrelatedColumn {
 	return corCols
 }
 
+// CorrelatedColumns returns the correlated columns referred to by the plan and its children.
+func CorrelatedColumns(p Plan) []*expression.CorrelatedColumn {
+	return p.extractCorrelatedCols()
+}
+
 // ResolveIndicesAndCorCols implements LogicalPlan interface.
 func (p *baseLogicalPlan) ResolveIndicesAndCorCols() {
 	for _, child := range p.children {
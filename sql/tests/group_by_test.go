@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	words := table{
+		name:    "words",
+		columns: wordColumns,
+		rows: [][]interface{}{
+			{"this", 1},
+			{"is", 2},
+			{"a", 3},
+			{"table", 4},
+			{"that", 5},
+			{"are", 6},
+			{"many", 7},
+			{"pencils", 6},
+			{"is", 8},
+		},
+		partitionCount: 2,
+	}
+
+	testQueries(t, []table{words}, []queryTest{
+		{
+			name: "aggregates with having",
+			sql: `
+            select word, count(*), sum(line), avg(line)
+            from words
+            group by word
+            having count(*) > 1
+            `,
+			steps: "LocalSort LocalAggregate ScatterPartitions CollectPartitions LocalSort LocalAggregate Map Filter Map",
+			rows:  []string{"is 2 10 5"},
+		},
+		{
+			name: "count distinct by an expression",
+			sql: `
+            select line % 2, count(distinct word), max(line)
+            from words
+            group by line % 2
+            `,
+			steps: "Map ScatterPartitions CollectPartitions LocalSort LocalAggregate Select Map",
+			rows:  []string{"0 4 8", "1 4 7"},
+		},
+		{
+			name:  "group concat",
+			sql:   "select word, group_concat(line) from words where line < 4 group by word",
+			steps: "LocalAggregate",
+			rows:  []string{"this 1", "is 2", "a 3"},
+		},
+		{
+			name:  "aggregates without group by",
+			sql:   "select count(*), min(word), max(line), sum(line) from words",
+			steps: "LocalAggregate",
+			rows:  []string{"9 a 8 42"},
+		},
+		{
+			name:  "aggregates of no rows",
+			sql:   "select count(*), sum(line) from words where line > 100",
+			steps: "LocalAggregate",
+			rows:  []string{"0 NULL"},
+		},
+	})
+}
@@ -0,0 +1,145 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/chrislusf/gleamold/sql/executor"
+	"github.com/chrislusf/gleamold/sql/mysql"
+)
+
+var colorColumns = []executor.TableColumn{
+	{"line", mysql.TypeLong},
+	{"color", mysql.TypeVarchar},
+}
+
+// joinTables are the words and the colors, with the colors hinted at the size to broadcast them if positive.
+func joinTables(colorsSize int64) []table {
+	return []table{{
+		name:    "words",
+		columns: wordColumns,
+		rows: [][]interface{}{
+			{"this", 1},
+			{"is", 2},
+			{"a", 3},
+			{"table", 4},
+			{"that", 5},
+		},
+		partitionCount: 2,
+	}, {
+		name:    "colors",
+		columns: colorColumns,
+		rows: [][]interface{}{
+			{1, "red"},
+			{2, "green"},
+			{3, "blue"},
+			{7, "white"},
+		},
+		totalSize: colorsSize,
+	}}
+}
+
+func TestJoin(t *testing.T) {
+	testQueries(t, joinTables(0), []queryTest{
+		{
+			name: "partitioned join with other conditions",
+			sql: `
+            select w.word, c.color
+            from words w join colors c on w.line = c.line
+            where w.word < c.color
+            `,
+			steps: "JoinPartitionedSorted",
+			rows:  []string{"a blue"},
+		},
+		{
+			name: "left outer join",
+			sql: `
+            select w.word, c.color
+            from words w left join colors c on w.line = c.line and w.word <> 'a'
+            `,
+			steps: "JoinPartitionedSorted",
+			rows:  []string{"this red", "is green", "a NULL", "table NULL", "that NULL"},
+		},
+		{
+			name: "right outer join",
+			sql: `
+            select w.word, c.line, c.color
+            from words w right join colors c on w.line = c.line
+            `,
+			steps: "JoinPartitionedSorted",
+			rows:  []string{"this 1 red", "is 2 green", "a 3 blue", "NULL 7 white"},
+		},
+		{
+			name: "right outer join with other conditions",
+			sql: `
+            select c.color, w.word
+            from words w right join colors c on w.line = c.line and w.word <> 'is'
+            `,
+			steps: "JoinPartitionedSorted",
+			rows:  []string{"red this", "green NULL", "blue a", "white NULL"},
+		},
+	})
+
+	testQueries(t, joinTables(1), []queryTest{
+		{
+			name: "broadcast join",
+			sql: `
+            select w.word, c.color
+            from words w join colors c on w.line = c.line
+            `,
+			steps: "Broadcast",
+			rows:  []string{"this red", "is green", "a blue"},
+		},
+	})
+}
+
+func TestRightOuterJoinWithEmptyShards(t *testing.T) {
+	// most shards have no words, but only colors
+	tables := joinTables(0)
+	tables[0].rows = [][]interface{}{{"this", 1}}
+
+	testQueries(t, tables, []queryTest{
+		{
+			name: "right outer join with empty shards",
+			sql: `
+            select w.word, w.line, c.color
+            from words w right join colors c on w.line = c.line
+            `,
+			steps: "JoinPartitionedSorted",
+			rows:  []string{"this 1 red", "NULL NULL green", "NULL NULL blue", "NULL NULL white"},
+		},
+		{
+			name: "left outer join with empty shards",
+			sql: `
+            select c.color, w.word, w.line
+            from colors c left join words w on w.line = c.line
+            `,
+			steps: "JoinPartitionedSorted",
+			rows:  []string{"red this 1", "green NULL NULL", "blue NULL NULL", "white NULL NULL"},
+		},
+	})
+}
+
+func TestSemiJoin(t *testing.T) {
+	testQueries(t, joinTables(1), []queryTest{
+		{
+			name: "in subquery",
+			sql: `
+            select word
+            from words
+            where line in (select line from colors where color <> 'red')
+            `,
+			steps: "Broadcast",
+			rows:  []string{"is", "a"},
+		},
+		{
+			name: "not exists subquery",
+			sql: `
+            select word
+            from words w
+            where not exists (select * from colors c where c.line = w.line)
+            `,
+			steps: "JoinPartitionedSorted",
+			rows:  []string{"table", "that"},
+		},
+	})
+}
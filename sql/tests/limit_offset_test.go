@@ -47,7 +47,7 @@ func TestLimitOffset(t *testing.T) {
 		{"line", mysql.TypeLong},
 	})
 
-	out, p, err := sql.Query(sqlText)
+	out, p, err := sql.Query(f, sqlText)
 	if err != nil {
 		fmt.Printf("error: %s\n", err)
 		return
@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"testing"
+)
+
+func TestOrderBy(t *testing.T) {
+	words := table{
+		name:    "words",
+		columns: wordColumns,
+		rows: [][]interface{}{
+			{"this", 1},
+			{"is", 2},
+			{"a", 3},
+			{"table", 4},
+			{"that", 5},
+			{"are", 6},
+			{"many", 7},
+			{"pencils", 6},
+		},
+		partitionCount: 2,
+	}
+
+	testQueries(t, []table{words}, []queryTest{
+		{
+			name: "order by columns",
+			sql: `
+            select word, line
+            from words
+            order by line desc, word
+            `,
+			steps:   "LocalSort MergeSortedTo",
+			rows:    []string{"many 7", "are 6", "pencils 6", "that 5", "table 4", "a 3", "is 2", "this 1"},
+			ordered: true,
+		},
+		{
+			name: "order by an expression",
+			sql: `
+            select word
+            from words
+            order by line % 3, word desc
+            `,
+			steps:   "Map LocalSort MergeSortedTo Select",
+			rows:    []string{"pencils", "are", "a", "this", "table", "many", "that", "is"},
+			ordered: true,
+		},
+		{
+			name: "order by with limit",
+			sql: `
+            select word, line
+            from words
+            order by line desc, word
+            limit 2, 3
+            `,
+			steps:   "LocalTop MergeSortedTo Limit Limit",
+			rows:    []string{"pencils 6", "that 5", "table 4"},
+			ordered: true,
+		},
+	})
+}
@@ -0,0 +1,123 @@
+package sql
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql"
+	"github.com/chrislusf/gleamold/sql/executor"
+	"github.com/chrislusf/gleamold/util"
+)
+
+// table is registered with its rows for the queries of a test.
+type table struct {
+	name    string
+	columns []executor.TableColumn
+	rows    [][]interface{}
+	// partitionCount splits the rows round robin, if more than 1.
+	partitionCount int
+	// totalSize hints the size in MB, e.g., to broadcast a small table in joins.
+	totalSize int64
+}
+
+// queryTest is a query with its expected steps and rows.
+type queryTest struct {
+	name string
+	sql  string
+	// steps are expected in the names of the flow steps, e.g., "LocalSort MergeSortedTo".
+	steps string
+	// rows are the expected rows, each with the values separated by a space, and NULL for nil.
+	rows []string
+	// ordered compares the rows in the output order, instead of sorting them first.
+	ordered bool
+}
+
+// testQueries runs each query on the tables, and compares the steps and the rows.
+func testQueries(t *testing.T, tables []table, tests []queryTest) {
+	for _, test := range tests {
+		steps, rows, err := runQuery(test.sql, tables)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !strings.Contains(steps, test.steps) {
+			t.Errorf("%s: Expect steps %s, but got %s", test.name, test.steps, steps)
+		}
+		expected := append([]string(nil), test.rows...)
+		if !test.ordered {
+			sort.Strings(expected)
+			sort.Strings(rows)
+		}
+		if strings.Join(rows, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("%s: Expect rows %q, but got %q", test.name, expected, rows)
+		}
+	}
+}
+
+// runQuery registers the tables in a new flow, and runs the query.
+// It returns the names of the flow steps, and the output rows formatted as in queryTest.
+func runQuery(sqlText string, tables []table) (steps string, rows []string, err error) {
+	f := flow.New()
+	f.Init(executor.Functions)
+	for _, t := range tables {
+		ds := f.Slices(t.rows)
+		if t.partitionCount > 1 {
+			ds = ds.RoundRobin(t.partitionCount)
+		}
+		if t.totalSize > 0 {
+			ds = ds.Hint(flow.TotalSize(t.totalSize))
+		}
+		sql.RegisterTable(ds, t.name, t.columns)
+	}
+
+	out, _, err := sql.Query(f, sqlText)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var names []string
+	for _, step := range f.Steps {
+		names = append(names, step.Name)
+	}
+
+	var lock sync.Mutex
+	out.Output(func(reader io.Reader) error {
+		for {
+			_, row, err := util.ReadRow(reader)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			lock.Lock()
+			rows = append(rows, formatRow(row))
+			lock.Unlock()
+		}
+	})
+
+	if _, err = f.Run(); err != nil {
+		return "", nil, fmt.Errorf("Failed to run %s: %v", sqlText, err)
+	}
+	return strings.Join(names, " "), rows, nil
+}
+
+func formatRow(row []interface{}) string {
+	var values []string
+	for _, v := range row {
+		switch x := v.(type) {
+		case nil:
+			values = append(values, "NULL")
+		case []byte:
+			values = append(values, string(x))
+		default:
+			values = append(values, fmt.Sprint(x))
+		}
+	}
+	return strings.Join(values, " ")
+}
@@ -0,0 +1,241 @@
+package sql
+
+import (
+	"testing"
+)
+
+// subqueryTables are the words and the colors, each with a NULL line.
+func subqueryTables() []table {
+	return []table{{
+		name:    "words",
+		columns: wordColumns,
+		rows: [][]interface{}{
+			{"this", 1},
+			{"is", 2},
+			{"red", 3},
+			{"a", 3},
+			{"table", 4},
+			{"black", 5},
+			{"none", nil},
+		},
+		partitionCount: 2,
+	}, {
+		name:    "colors",
+		columns: colorColumns,
+		rows: [][]interface{}{
+			{1, "red"},
+			{2, "green"},
+			{3, "red"},
+			{7, "white"},
+			{nil, "black"},
+		},
+	}}
+}
+
+func TestSubquery(t *testing.T) {
+	testQueries(t, subqueryTables(), []queryTest{
+		{
+			name: "exists",
+			sql: `
+            select word
+            from words
+            where exists (select * from colors where color = 'red')
+            `,
+			steps: "JoinPartitionedSorted Filter Select",
+			rows:  []string{"this", "is", "red", "a", "table", "black", "none"},
+		},
+		{
+			name: "scalar",
+			sql: `
+            select word
+            from words
+            where line < (select max(line) from colors where color <> 'white')
+            `,
+			steps: "Slices Map LocalSort LocalSort JoinPartitionedSorted Map",
+			rows:  []string{"this", "is"},
+		},
+		{
+			name: "not in",
+			sql: `
+            select word
+            from words
+            where line not in (select line from colors where color <> 'black')
+            `,
+			steps: "JoinPartitionedSorted Filter Select",
+			rows:  []string{"table", "black"},
+		},
+		{
+			name: "not in with a NULL in the subquery",
+			sql: `
+            select word
+            from words
+            where line not in (select line from colors)
+            `,
+			steps: "JoinPartitionedSorted Filter Select",
+		},
+		{
+			name: "not in an empty subquery",
+			sql: `
+            select word
+            from words
+            where line not in (select line from colors where color = 'pink')
+            `,
+			steps: "JoinPartitionedSorted Filter Select",
+			rows:  []string{"this", "is", "red", "a", "table", "black", "none"},
+		},
+		{
+			name: "not in as a value",
+			sql: `
+            select word, line not in (select line from colors where color <> 'black')
+            from words
+            `,
+			steps: "JoinPartitionedSorted Map Map",
+			rows:  []string{"this false", "is false", "red false", "a false", "table true", "black true", "none NULL"},
+		},
+		{
+			name: "not in with a NULL in the subquery as a value",
+			sql: `
+            select word, line not in (select line from colors)
+            from words
+            `,
+			steps: "JoinPartitionedSorted Map Map",
+			rows:  []string{"this false", "is false", "red false", "a false", "table NULL", "black NULL", "none NULL"},
+		},
+	})
+}
+
+func TestCorrelatedSubquery(t *testing.T) {
+	testQueries(t, subqueryTables(), []queryTest{
+		{
+			name: "correlated in",
+			sql: `
+            select word
+            from words w
+            where w.line in (select c.line from colors c where c.color <> w.word)
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this", "is", "a"},
+		},
+		{
+			name: "correlated not in",
+			sql: `
+            select word
+            from words w
+            where w.line not in (select c.line from colors c where c.color <> w.word)
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"black"},
+		},
+		{
+			name: "correlated not in a partitioned table",
+			sql: `
+            select color
+            from colors c
+            where c.line not in (select w.line from words w where w.word <> c.color and w.line is not null)
+            `,
+			steps: "MergeTo Broadcast LocalSort LocalSort CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"white"},
+		},
+		{
+			name: "correlated exists",
+			sql: `
+            select word, exists (select * from colors c where c.line > w.line)
+            from words w
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this true", "is true", "red true", "a true", "table true", "black true", "none false"},
+		},
+		{
+			name: "correlated scalar",
+			sql: `
+            select word, (select count(*) from colors c where c.line = w.line)
+            from words w
+            `,
+			steps: "LocalSort LocalAggregate LocalAggregate",
+			rows:  []string{"this 1", "is 1", "red 1", "a 1", "table 0", "black 0", "none 0"},
+		},
+	})
+}
+
+func TestCorrelatedSubqueryForEachRow(t *testing.T) {
+	testQueries(t, subqueryTables(), []queryTest{
+		{
+			name: "scalar aggregation on non-equal conditions",
+			sql: `
+            select word
+            from words w
+            where line = (select max(line) from colors c where c.line <= w.line)
+            `,
+			steps: "Broadcast LocalSort LocalSort CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this", "is", "red", "a"},
+		},
+		{
+			name: "scalar count on non-equal conditions",
+			sql: `
+            select word, (select count(*) from colors c where c.line < w.line)
+            from words w
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this 0", "is 1", "red 2", "a 2", "table 3", "black 3", "none 0"},
+		},
+		{
+			name: "scalar distinct count and average on non-equal conditions",
+			sql: `
+            select word,
+              (select count(distinct c.color) from colors c where c.line < w.line),
+              (select avg(c.line) from colors c where c.line < w.line)
+            from words w
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this 0 NULL", "is 1 1", "red 2 1.5", "a 2 1.5", "table 2 2", "black 2 2", "none 0 NULL"},
+		},
+		{
+			name: "scalar aggregation on the outer columns",
+			sql: `
+            select word, (select sum(c.line + w.line) from colors c where c.line = w.line)
+            from words w
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this 2", "is 4", "red 6", "a 6", "table NULL", "black NULL", "none NULL"},
+		},
+		{
+			name: "scalar column",
+			sql: `
+            select word, (select color from colors c where c.line = w.line)
+            from words w
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this red", "is green", "red red", "a red", "table NULL", "black NULL", "none NULL"},
+		},
+		{
+			name: "exists with limit",
+			sql: `
+            select word
+            from words w
+            where exists (select * from colors c where c.line = w.line limit 1)
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this", "is", "red", "a"},
+		},
+		{
+			name: "not in with order by and limit",
+			sql: `
+            select word
+            from words w
+            where w.line not in (select c.line from colors c where c.color <> w.word order by c.line desc limit 2)
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"this", "is", "red", "table", "black"},
+		},
+		{
+			name: "not in with a NULL in the subquery",
+			sql: `
+            select word
+            from words w
+            where w.line not in (select c.line from colors c where c.color <> w.word order by c.line limit 2)
+            `,
+			steps: "CoGroupPartitionedSorted FlatMap Map",
+			rows:  []string{"black"},
+		},
+	})
+}
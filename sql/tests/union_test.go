@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/chrislusf/gleamold/flow"
+	"github.com/chrislusf/gleamold/sql"
+	"github.com/chrislusf/gleamold/sql/executor"
+)
+
+func TestUnion(t *testing.T) {
+	tables := []table{{
+		name:    "words",
+		columns: wordColumns,
+		rows: [][]interface{}{
+			{"this", 1},
+			{"is", 2},
+			{"red", 3},
+			{"is", 4},
+		},
+		partitionCount: 2,
+	}, {
+		name:    "colors",
+		columns: colorColumns,
+		rows: [][]interface{}{
+			{1, "red"},
+			{2, "green"},
+		},
+	}}
+
+	testQueries(t, tables, []queryTest{
+		{
+			name: "union removes duplicates",
+			sql: `
+            select word from words
+            union
+            select color from colors
+            `,
+			steps: "MergeTo",
+			rows:  []string{"this", "is", "red", "green"},
+		},
+		{
+			name: "union all keeps duplicates",
+			sql: `
+            select word from words
+            union all
+            select color from colors
+            `,
+			steps: "RoundRobin MergeTo",
+			rows:  []string{"this", "is", "red", "is", "red", "green"},
+		},
+		{
+			name:  "select without tables",
+			sql:   "select 1 + 1, 'x'",
+			steps: "Slices Map",
+			rows:  []string{"2 x"},
+		},
+	})
+}
+
+func TestSelectWithoutRegisteredTables(t *testing.T) {
+	defer func(tables map[string]*executor.TableSource) {
+		executor.Tables = tables
+	}(executor.Tables)
+	executor.Tables = make(map[string]*executor.TableSource)
+
+	_, rows, err := runQuery("select 1 + 1", nil)
+	if err != nil || len(rows) != 1 || rows[0] != "2" {
+		t.Errorf("Expect one row of 2, but got %v: %v", rows, err)
+	}
+}
+
+func TestQueryTableInAnotherFlow(t *testing.T) {
+	defer func(tables map[string]*executor.TableSource) {
+		executor.Tables = tables
+	}(executor.Tables)
+	executor.Tables = make(map[string]*executor.TableSource)
+
+	sql.RegisterTable(flow.New().Slices([][]interface{}{{"a", 1}}), "words", wordColumns)
+	if _, _, err := sql.Query(flow.New(), "select word from words"); err == nil {
+		t.Errorf("Expect an error querying a table in another flow")
+	}
+}
@@ -0,0 +1,74 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/chrislusf/gleamold/sql/executor"
+	"github.com/chrislusf/gleamold/sql/mysql"
+)
+
+var wordColumns = []executor.TableColumn{
+	{"word", mysql.TypeVarchar},
+	{"line", mysql.TypeLong},
+}
+
+func TestWhere(t *testing.T) {
+	words := table{
+		name:    "words",
+		columns: wordColumns,
+		rows: [][]interface{}{
+			{"this", 1},
+			{"is", 2},
+			{"a", 3},
+			{"table", 4},
+			{"that", 5},
+			{"are", 6},
+			{"many", 7},
+			{"pencils", 6},
+		},
+		partitionCount: 2,
+	}
+
+	testQueries(t, []table{words}, []queryTest{
+		{
+			name: "comparisons, like and in",
+			sql: `
+            select line, word
+            from words
+            where line > 3 and (word like 't%' or word in ('is', 'are')) and word <> 'that'
+            `,
+			steps: "Filter",
+			rows:  []string{"4 table", "6 are"},
+		},
+		{
+			name:  "0 is false in and",
+			sql:   "select word from words where (line % 2 and line > 2) or word = 'is'",
+			steps: "Filter",
+			rows:  []string{"is", "a", "that", "many"},
+		},
+		{
+			name:  "0 is false in or",
+			sql:   "select word from words where line % 3 or line = 6",
+			steps: "Filter",
+			rows:  []string{"this", "is", "table", "that", "are", "many", "pencils"},
+		},
+		{
+			name:  "not 0 is true",
+			sql:   "select word from words where not (line % 2)",
+			steps: "Filter",
+			rows:  []string{"is", "table", "are", "pencils"},
+		},
+		{
+			name:  "null and false is false",
+			sql:   "select word from words where not (null and line > 1)",
+			steps: "Filter",
+			rows:  []string{"this"},
+		},
+		{
+			name:  "null or true is true",
+			sql:   "select word from words where null or line = 2",
+			steps: "Filter",
+			rows:  []string{"is"},
+		},
+	})
+}
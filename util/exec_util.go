@@ -6,6 +6,7 @@ import (
 	"io"
 	"os/exec"
 	"sync"
+	"syscall"
 
 	"github.com/chrislusf/gleamold/pb"
 )
@@ -35,7 +36,7 @@ func Execute(ctx context.Context, executeWaitGroup *sync.WaitGroup, stat *pb.Ins
 			command.Stdin = reader
 		} else if !prevIsPipe && !isPipe {
 			// println("step", name, "input is msgpack->msgpack")
-			command.Stdin = reader
+			command.Stdin = NewMessageCountingReader(reader, &stat.InputCounter)
 		} else {
 			inputWriter, stdinErr := command.StdinPipe()
 			if stdinErr != nil {
@@ -53,9 +54,15 @@ func Execute(ctx context.Context, executeWaitGroup *sync.WaitGroup, stat *pb.Ins
 		}
 	}
 
-	command.Stdout = writer
+	if !prevIsPipe && !isPipe {
+		command.Stdout = NewMessageCountingWriter(writer, &stat.OutputCounter)
+	} else {
+		command.Stdout = writer
+	}
 
-	command.Stderr = errWriter
+	// the counter lines of the Go mappers and reducers are added to the stat
+	counters := NewCounterFilter(errWriter)
+	command.Stderr = counters
 
 	// println(name, "starting...")
 
@@ -68,10 +75,13 @@ func Execute(ctx context.Context, executeWaitGroup *sync.WaitGroup, stat *pb.Ins
 	go func() {
 		wg.Wait()
 		waitError := command.Wait()
+		counters.Flush()
+		counters.AddTo(stat)
 		if waitError != nil {
-			waitError = fmt.Errorf("%s Wait error %+v.\n", name, waitError)
+			errChan <- &ExecError{Name: name, ExitCode: exitCode(waitError), Err: waitError}
+			return
 		}
-		errChan <- waitError
+		errChan <- nil
 	}()
 
 	// defer fmt.Printf("%s Command is finished.\n", name)
@@ -87,3 +97,23 @@ func Execute(ctx context.Context, executeWaitGroup *sync.WaitGroup, stat *pb.Ins
 	}
 
 }
+
+// ExecError is returned by Execute when the command fails.
+type ExecError struct {
+	Name     string
+	ExitCode int // -1 if the command did not exit normally
+	Err      error
+}
+
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("%s Wait error %+v.", e.Name, e.Err)
+}
+
+func exitCode(err error) int {
+	if exitError, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitError.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return -1
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"encoding/binary"
+	"io"
+	"sync/atomic"
+)
+
+// messageCounter counts the messages in a stream of (size, msgpack_encoded) tuples
+// by following the message length headers, without decoding the messages.
+type messageCounter struct {
+	counter    *int64
+	header     [4]byte
+	headerSize int
+	remaining  int64
+}
+
+func (c *messageCounter) scan(data []byte) {
+	for len(data) > 0 {
+		if c.remaining > 0 {
+			n := int64(len(data))
+			if n > c.remaining {
+				n = c.remaining
+			}
+			data = data[n:]
+			c.remaining -= n
+			continue
+		}
+		n := copy(c.header[c.headerSize:], data)
+		c.headerSize += n
+		data = data[n:]
+		if c.headerSize < len(c.header) {
+			return
+		}
+		c.headerSize = 0
+		length := int32(binary.LittleEndian.Uint32(c.header[:]))
+		if length == int32(MessageControlEOF) {
+			continue
+		}
+		atomic.AddInt64(c.counter, 1)
+		if length > 0 {
+			c.remaining = int64(length)
+		}
+	}
+}
+
+type messageCountingReader struct {
+	messageCounter
+	reader io.Reader
+}
+
+// NewMessageCountingReader adds the number of messages read through it to the counter.
+func NewMessageCountingReader(reader io.Reader, counter *int64) io.Reader {
+	return &messageCountingReader{messageCounter: messageCounter{counter: counter}, reader: reader}
+}
+
+func (r *messageCountingReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	r.scan(p[:n])
+	return
+}
+
+type messageCountingWriter struct {
+	messageCounter
+	writer io.Writer
+}
+
+// NewMessageCountingWriter adds the number of messages written through it to the counter.
+func NewMessageCountingWriter(writer io.Writer, counter *int64) io.Writer {
+	return &messageCountingWriter{messageCounter: messageCounter{counter: counter}, writer: writer}
+}
+
+func (w *messageCountingWriter) Write(p []byte) (n int, err error) {
+	n, err = w.writer.Write(p)
+	w.scan(p[:n])
+	return
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestMessageCounter(t *testing.T) {
+
+	var buf bytes.Buffer
+	for i := 0; i < 3; i++ {
+		WriteRow(&buf, Now(), "row", i)
+	}
+	WriteMessage(&buf, nil)
+	WriteEOFMessage(&buf)
+
+	var readCount, writeCount int64
+	reader := NewMessageCountingReader(iotest.OneByteReader(bytes.NewReader(buf.Bytes())), &readCount)
+	writer := NewMessageCountingWriter(&bytes.Buffer{}, &writeCount)
+	if _, err := io.Copy(writer, reader); err != nil {
+		t.Fatalf("Failed to copy: %v", err)
+	}
+
+	if readCount != 4 || writeCount != 4 {
+		t.Errorf("Expect 4 messages, but read %d and wrote %d", readCount, writeCount)
+	}
+}
@@ -0,0 +1,152 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// SpillBudget is the memory shared by all the SpillBuffers created with it,
+// so that the memory does not grow with the number of buffers.
+type SpillBudget struct {
+	sync.Mutex
+	limit int64
+	used  int64
+}
+
+func NewSpillBudget(limit int64) *SpillBudget {
+	return &SpillBudget{limit: limit}
+}
+
+func (s *SpillBudget) reserve(n int) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.used+int64(n) > s.limit {
+		return false
+	}
+	s.used += int64(n)
+	return true
+}
+
+func (s *SpillBudget) release(n int) {
+	s.Lock()
+	defer s.Unlock()
+	s.used -= int64(n)
+}
+
+// SpillBuffer is an in-process pipe whose writes never block.
+// Unread data is kept in memory while the shared budget allows, and the rest is
+// appended to a temporary file until the reader catches up.
+type SpillBuffer struct {
+	sync.Mutex
+	cond        *sync.Cond
+	dir         string
+	budget      *SpillBudget
+	memory      bytes.Buffer
+	file        *os.File
+	readOffset  int64
+	writeOffset int64
+	closed      bool
+	err         error
+}
+
+func NewSpillBuffer(dir string, budget *SpillBudget) *SpillBuffer {
+	b := &SpillBuffer{
+		dir:    dir,
+		budget: budget,
+	}
+	b.cond = sync.NewCond(b)
+	return b
+}
+
+func (b *SpillBuffer) Write(p []byte) (n int, err error) {
+	b.Lock()
+	defer b.Unlock()
+	defer b.cond.Broadcast()
+
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if b.file == nil && b.budget.reserve(len(p)) {
+		return b.memory.Write(p)
+	}
+	if b.file == nil {
+		if b.file, err = ioutil.TempFile(b.dir, "buffer-"); err != nil {
+			b.err = fmt.Errorf("Failed to create buffer file under %s: %v", b.dir, err)
+			return 0, b.err
+		}
+	}
+	n, err = b.file.WriteAt(p, b.writeOffset)
+	b.writeOffset += int64(n)
+	if err != nil {
+		b.err = fmt.Errorf("Failed to write buffer file %s: %v", b.file.Name(), err)
+	}
+	return n, b.err
+}
+
+// Close marks the end of the writes. The reader gets io.EOF after
+// reading all the buffered data.
+func (b *SpillBuffer) Close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	b.closed = true
+	b.cond.Broadcast()
+	return nil
+}
+
+// Read reads the data in the order written, from memory first,
+// and then from the file. It blocks until there is some data.
+func (b *SpillBuffer) Read(p []byte) (n int, err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	for b.memory.Len() == 0 && b.readOffset == b.writeOffset && !b.closed && b.err == nil {
+		b.cond.Wait()
+	}
+	if b.memory.Len() > 0 {
+		n, err = b.memory.Read(p)
+		b.budget.release(n)
+		if b.memory.Len() == 0 {
+			// free the memory of idle buffers
+			b.memory = bytes.Buffer{}
+		}
+		return n, err
+	}
+	if b.readOffset < b.writeOffset {
+		if remaining := b.writeOffset - b.readOffset; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+		n, err = b.file.ReadAt(p, b.readOffset)
+		b.readOffset += int64(n)
+		if err == io.EOF {
+			err = nil
+		}
+		if b.readOffset == b.writeOffset {
+			// caught up with the writer, so go back to memory
+			b.removeFile()
+		}
+		return n, err
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	b.removeFile()
+	return 0, io.EOF
+}
+
+func (b *SpillBuffer) removeFile() {
+	if b.file == nil {
+		return
+	}
+	b.file.Close()
+	os.Remove(b.file.Name())
+	b.file = nil
+	b.readOffset, b.writeOffset = 0, 0
+}
@@ -0,0 +1,103 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestSpillBuffer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spill_buffer_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := make([]byte, 1024*1024)
+	rand.Read(data)
+	half := len(data) / 2
+
+	budget := NewSpillBudget(4096)
+	b := NewSpillBuffer(dir, budget)
+
+	// write more than the memory limit before anything is read
+	for i := 0; i < half; i += 1000 {
+		end := i + 1000
+		if end > half {
+			end = half
+		}
+		if _, err := b.Write(data[i:end]); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+	if fileInfos, _ := ioutil.ReadDir(dir); len(fileInfos) != 1 {
+		t.Errorf("Expect data to be spilled to 1 file, but found %d files", len(fileInfos))
+	}
+
+	// read while writing the rest
+	go func() {
+		defer b.Close()
+		for i := half; i < len(data); i += 1000 {
+			end := i + 1000
+			if end > len(data) {
+				end = len(data)
+			}
+			b.Write(data[i:end])
+		}
+	}()
+	output, err := ioutil.ReadAll(b)
+	if err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	if !bytes.Equal(output, data) {
+		t.Errorf("Expect the %d bytes written, but read %d different bytes", len(data), len(output))
+	}
+
+	if n, err := b.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Expect EOF after close, but got %d bytes and error %v", n, err)
+	}
+	if fileInfos, _ := ioutil.ReadDir(dir); len(fileInfos) != 0 {
+		t.Errorf("Expect buffer file to be removed, but found %d files", len(fileInfos))
+	}
+	if budget.used != 0 {
+		t.Errorf("Expect all the memory to be released, but %d bytes are still used", budget.used)
+	}
+}
+
+func TestSpillBufferSharedBudget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spill_buffer_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	budget := NewSpillBudget(4096)
+	var buffers []*SpillBuffer
+	for i := 0; i < 4; i++ {
+		b := NewSpillBuffer(dir, budget)
+		if _, err := b.Write(make([]byte, 3000)); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+		buffers = append(buffers, b)
+	}
+	// only the first buffer fits in the budget
+	if fileInfos, _ := ioutil.ReadDir(dir); len(fileInfos) != 3 {
+		t.Errorf("Expect 3 buffers to spill to files, but found %d files", len(fileInfos))
+	}
+	if budget.used != 3000 {
+		t.Errorf("Expect 3000 bytes in memory, but got %d", budget.used)
+	}
+
+	for _, b := range buffers {
+		b.Close()
+		if output, err := ioutil.ReadAll(b); err != nil || len(output) != 3000 {
+			t.Errorf("Expect 3000 bytes, but read %d bytes with error %v", len(output), err)
+		}
+	}
+	if budget.used != 0 {
+		t.Errorf("Expect all the memory to be released, but %d bytes are still used", budget.used)
+	}
+}
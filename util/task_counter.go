@@ -0,0 +1,98 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/chrislusf/gleamold/pb"
+)
+
+// counterLinePrefix starts the lines that a Go mapper or reducer process writes to its stderr
+// to add to the named counters of its task, which are not passed through to the logs.
+const counterLinePrefix = "gleamold.counter\t"
+
+// WriteCounter writes the line to add the value to the named counter of the task.
+func WriteCounter(writer io.Writer, name string, value int64) error {
+	_, err := fmt.Fprintf(writer, "%s%s\t%d\n", counterLinePrefix, name, value)
+	return err
+}
+
+// AddCounter adds the value to the named counter of the stat.
+func AddCounter(stat *pb.InstructionStat, name string, value int64) {
+	for _, counter := range stat.Counters {
+		if counter.Name == name {
+			counter.Value += value
+			return
+		}
+	}
+	stat.Counters = append(stat.Counters, &pb.InstructionStat_Counter{Name: name, Value: value})
+}
+
+// CounterFilter sums up the counter lines written to it,
+// and passes the other lines through to the writer.
+type CounterFilter struct {
+	writer   io.Writer
+	line     []byte
+	names    []string
+	counters map[string]int64
+}
+
+func NewCounterFilter(writer io.Writer) *CounterFilter {
+	return &CounterFilter{writer: writer, counters: make(map[string]int64)}
+}
+
+func (f *CounterFilter) Write(p []byte) (n int, err error) {
+	n = len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			f.line = append(f.line, p...)
+			return n, nil
+		}
+		f.line = append(f.line, p[:i+1]...)
+		p = p[i+1:]
+		if err = f.writeLine(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Flush passes through the last line if it is not complete.
+func (f *CounterFilter) Flush() error {
+	if len(f.line) == 0 {
+		return nil
+	}
+	_, err := f.writer.Write(f.line)
+	f.line = f.line[:0]
+	return err
+}
+
+func (f *CounterFilter) writeLine() (err error) {
+	line := f.line
+	f.line = f.line[:0]
+	if bytes.HasPrefix(line, []byte(counterLinePrefix)) {
+		fields := bytes.Split(bytes.TrimSpace(line[len(counterLinePrefix):]), []byte("\t"))
+		if len(fields) == 2 {
+			if value, parseErr := strconv.ParseInt(string(fields[1]), 10, 64); parseErr == nil {
+				name := string(fields[0])
+				if _, ok := f.counters[name]; !ok {
+					f.names = append(f.names, name)
+				}
+				f.counters[name] += value
+				return nil
+			}
+		}
+	}
+	_, err = f.writer.Write(line)
+	return err
+}
+
+// AddTo adds the counters to the stat.
+func (f *CounterFilter) AddTo(stat *pb.InstructionStat) {
+	for _, name := range f.names {
+		AddCounter(stat, name, f.counters[name])
+	}
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/chrislusf/gleamold/pb"
+)
+
+func TestCounterFilter(t *testing.T) {
+
+	var input bytes.Buffer
+	input.WriteString("log line 1\n")
+	WriteCounter(&input, "a", 2)
+	WriteCounter(&input, "b", 5)
+	input.WriteString(counterLinePrefix + "bad counter\n")
+	WriteCounter(&input, "a", 3)
+	input.WriteString("last line")
+
+	var output bytes.Buffer
+	filter := NewCounterFilter(&output)
+	if _, err := io.Copy(filter, iotest.OneByteReader(&input)); err != nil {
+		t.Fatalf("Failed to copy: %v", err)
+	}
+	filter.Flush()
+
+	expected := "log line 1\n" + counterLinePrefix + "bad counter\nlast line"
+	if output.String() != expected {
+		t.Errorf("Expect %q, but got %q", expected, output.String())
+	}
+
+	stat := &pb.InstructionStat{}
+	AddCounter(stat, "b", 1)
+	filter.AddTo(stat)
+	if len(stat.Counters) != 2 || stat.Counters[0].Name != "b" || stat.Counters[0].Value != 6 ||
+		stat.Counters[1].Name != "a" || stat.Counters[1].Value != 5 {
+		t.Errorf("Unexpected counters %v", stat.Counters)
+	}
+}